@@ -0,0 +1,66 @@
+package command
+
+import (
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"time"
+)
+
+// fetchMetabolicRates calculates the metabolic rates with the config and weight effective on the given date
+// The body fat is only fetched, if the configured formula needs it
+func fetchMetabolicRates(ds datasource.DataSource, date time.Time) (float64, float64, error) {
+	config, err := ds.FetchConfigForDate(date)
+	if err != nil {
+		return 0, 0, err
+	}
+	weight, err := ds.FetchWeightForDate(date)
+	if err != nil {
+		return 0, 0, err
+	}
+	var bodyFat float64
+	if config.Formula == util.KatchMcArdle {
+		bodyFat, err = ds.FetchBodyFatForDate(date)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	bmr, amr := util.CalculateMetabolicRatesForFormula(config.Formula, date, config.Birthday, config.Height, weight.Weight, bodyFat, config.Activity, config.Gender)
+	return bmr, amr, nil
+}
+
+// fetchWeeklyBudget calculates the budget of the week of the given day as of that day
+// The budget of the week is the sum of the AMR of each day of the week and the AMR of the
+// given day is the daily target
+func fetchWeeklyBudget(ds datasource.DataSource, today time.Time) (*model.WeeklyBudget, error) {
+	today = util.TruncateToDate(today)
+	from := util.GetBeginningOfWeek(today)
+	budget := &model.WeeklyBudget{
+		From: from,
+		To:   from.AddDate(0, 0, 6),
+	}
+	for date := from; !date.After(budget.To); date = date.AddDate(0, 0, 1) {
+		_, amr, err := fetchMetabolicRates(ds, date)
+		if err != nil {
+			return nil, err
+		}
+		budget.Total += amr
+		if date.Equal(today) {
+			budget.Target = amr
+		}
+	}
+	days, err := fetchDuration(ds, from, today)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		budget.Used += day.Used
+		if day.Date.Equal(today) {
+			budget.UsedToday = day.Used
+		}
+	}
+	budget.Remaining = budget.Total - float64(budget.Used)
+	budget.DaysLeft = int(budget.To.Sub(today).Hours()/24) + 1
+	budget.DailyAllowance = (budget.Remaining + float64(budget.UsedToday)) / float64(budget.DaysLeft)
+	return budget, nil
+}
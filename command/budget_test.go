@@ -0,0 +1,60 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"testing"
+	"time"
+)
+
+func TestFetchWeeklyBudget(t *testing.T) {
+	exps := make(mock.Expectations)
+	lighter := dummyWeight
+	lighter.Weight = dummyWeight.Weight - 10
+	exps.Add("FetchConfigForDate", nil, &dummyConfig, &dummyConfig, &dummyConfig, &dummyConfig, &dummyConfig, &dummyConfig, &dummyConfig)
+	exps.Add("FetchWeightForDate", nil, &dummyWeight, &dummyWeight, &dummyWeight, &lighter, &lighter, &lighter, &lighter)
+	for i := 0; i < 3; i++ {
+		exps.Add("FetchEntries", nil, model.Entries{model.Entry{Calories: 1000}})
+	}
+	today, _ := time.Parse(util.DateFormat, "04.01.2017")
+	budget, err := fetchWeeklyBudget(&mock.DataSource{Expectations: exps}, today)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if budget.From.Format(util.DateFormat) != "02.01.2017" || budget.To.Format(util.DateFormat) != "08.01.2017" {
+		t.Errorf("Error, actual: %v %v expected: %v %v", budget.From, budget.To, "02.01.2017", "08.01.2017")
+		return
+	}
+	total := 0.0
+	for i := 0; i < 7; i++ {
+		weight := dummyWeight.Weight
+		if i >= 3 {
+			weight = lighter.Weight
+		}
+		_, amr := util.CalculateMetabolicRatesForFormula(dummyConfig.Formula, budget.From.AddDate(0, 0, i), dummyConfig.Birthday, dummyConfig.Height, weight, 0, dummyConfig.Activity, dummyConfig.Gender)
+		total += amr
+	}
+	_, target := util.CalculateMetabolicRatesForFormula(dummyConfig.Formula, today, dummyConfig.Birthday, dummyConfig.Height, dummyWeight.Weight, 0, dummyConfig.Activity, dummyConfig.Gender)
+	if budget.Target != target || budget.Total != total || budget.Used != 3000 || budget.UsedToday != 1000 || budget.Remaining != budget.Total-3000 {
+		t.Errorf("Error, actual: %v expected: %v", budget, "3000 of the sum of the AMR of each day used")
+		return
+	}
+	if budget.DaysLeft != 5 || budget.DailyAllowance != (budget.Total-2000)/5 {
+		t.Errorf("Error, actual: %v %v expected: %v %v", budget.DaysLeft, budget.DailyAllowance, 5, (budget.Total-2000)/5)
+		return
+	}
+}
+
+func TestFetchWeeklyBudgetConfigFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfigForDate", &model.Config{}, errors.New("someError"))
+	_, err := fetchWeeklyBudget(&mock.DataSource{Expectations: exps}, time.Now())
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
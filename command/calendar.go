@@ -0,0 +1,39 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"time"
+)
+
+// CalendarCommand is the command to show a month as a calendar
+type CalendarCommand struct {
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	Date        string
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+}
+
+// Execute shows the month of the given date, or the current month, if no date is given
+func (c *CalendarCommand) Execute() (string, error) {
+	date := util.TruncateToDate(util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover))
+	if c.Date != "" {
+		parsedDate, err := util.ParseDate(c.Date, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		date = parsedDate
+	}
+	fromDate := date.AddDate(0, 0, -date.Day()+1)
+	toDate := fromDate.AddDate(0, 1, -1)
+	days, err := fetchDuration(c.DataSource, fromDate, toDate)
+	if err != nil {
+		return "", err
+	}
+	return c.Renderer.Calendar(days, fromDate)
+}
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+)
+
+func TestExecuteCalendarWrongDate(t *testing.T) {
+	c := CalendarCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Date:       "bla",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteCalendarFetchFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	for i := 0; i <= 31; i++ {
+		exps.Add("FetchEntries", model.Entries{}, errors.New("someError"))
+	}
+	c := CalendarCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "15.02.2017",
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteCalendarSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	for i := 0; i <= 31; i++ {
+		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	}
+	c := CalendarCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "15.02.2017",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"time"
 )
 
+// noEatingWindow is the eating window, which removes the current eating window
+const noEatingWindow = "none"
+
 // ConfigCommand is the command to save and show the configuration
 type ConfigCommand struct {
 	DataSource datasource.DataSource
@@ -20,41 +23,154 @@ type ConfigCommand struct {
 	Birthday   string
 	Gender     string
 	UnitSystem string
-	YesMode    bool
-	Mode       int
+	DateFormat string
+	// CurrentDateFormat is the date format of the other commands, which is used if no date format
+	// is given and the current config has none
+	CurrentDateFormat string
+	Timezone          string
+	DayRollover       int
+	Budget            string
+	WaterTarget       float64
+	Formula           string
+	EatingWindow      string
+	Date              string
+	History           bool
+	YesMode           bool
+	Mode              int
+	Clock             util.Clock
+	Backup            func() error
 }
 
 // Execute shows the current config, if no parameters are given, otherwise it
-// parses the given configuration and saves it to the database, asking for
-// confirmation first
+// parses the given configuration and saves it to the database as a new version, which
+// is effective from the given date on, asking for confirmation and backing up the database first
+// Settings, which are not given, are kept from the current config
 // The weight from the given config is added to the weight table
+// In history mode, all config versions are shown
 func (c *ConfigCommand) Execute() (string, error) {
+	if c.History {
+		configs, err := c.DataSource.FetchConfigHistory()
+		if err != nil {
+			return "", fmt.Errorf("could not fetch config history: %v", err)
+		}
+		return c.Renderer.ConfigHistory(configs)
+	}
 	if c.Mode < 2 {
-		return printConfig(c.DataSource, c.Renderer)
+		return printConfig(c.DataSource, c.Renderer, c.Clock)
 	}
 	if c.Weight == -1 || c.Height == -1 || c.Activity == -1 || c.Birthday == "" {
 		return "", fmt.Errorf("usage: calories config --w=0.0 --h=0.0 --a=0.0 --b=01.01.1970 --g=male --u=metric")
 	}
-	parsedBirthday, err := time.Parse(util.DateFormat, c.Birthday)
+	if c.DateFormat != "" && !util.IsValidDateFormat(c.DateFormat) {
+		return "", fmt.Errorf("wrong date format: %s, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd", c.DateFormat)
+	}
+	if _, err := util.LoadLocation(c.Timezone); err != nil {
+		return "", fmt.Errorf("unknown timezone: %s, please use an IANA timezone name (e.g.: Europe/Vienna)", c.Timezone)
+	}
+	if c.DayRollover != -1 && (c.DayRollover < 0 || c.DayRollover > 23) {
+		return "", fmt.Errorf("wrong day rollover: %d, please use an hour from 0 to 23", c.DayRollover)
+	}
+	if c.Budget != "" && c.Budget != util.DailyBudget && c.Budget != util.WeeklyBudget {
+		return "", fmt.Errorf("wrong budget mode: %s, please use daily or weekly", c.Budget)
+	}
+	if c.WaterTarget != -1 && c.WaterTarget < 0 {
+		return "", fmt.Errorf("wrong water target: %.1f, please use a positive amount", c.WaterTarget)
+	}
+	if !util.IsValidFormula(c.Formula) {
+		return "", fmt.Errorf("wrong formula: %s, please use harris-benedict or katch-mcardle", c.Formula)
+	}
+	if c.EatingWindow != "" && c.EatingWindow != noEatingWindow {
+		if _, _, err := util.ParseEatingWindow(c.EatingWindow); err != nil {
+			return "", fmt.Errorf("wrong eating window: %s, please use START-END with hours from 0 to 23 (e.g.: 12-20)", c.EatingWindow)
+		}
+	}
+	c.inheritConfig()
+	parsedBirthday, err := util.ParseDate(c.Birthday, c.DateFormat)
 	if err != nil {
-		return "", fmt.Errorf("wrong format for birthday: %v, please use dd.mm.yyyy", err)
+		return "", fmt.Errorf("wrong format for birthday: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+	}
+	var effective time.Time
+	if c.Date != "" {
+		effective, err = util.ParseDate(c.Date, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
 	}
 	if choice, askErr := checkYesMode(c.YesMode); askErr != nil || !choice {
 		return "", askErr
 	}
-	if err := setConfigAndWeight(c, parsedBirthday); err != nil {
+	if err := backupDatabase(c.Backup); err != nil {
 		return "", err
 	}
-	return printConfig(c.DataSource, c.Renderer)
+	if err := setConfigAndWeight(c, parsedBirthday, effective); err != nil {
+		return "", err
+	}
+	return printConfig(c.DataSource, c.Renderer, c.Clock)
+}
+
+// inheritConfig sets the settings, which were not given, to the ones of the current config,
+// so updating the config doesn't reset them. If there is no config yet, the defaults are used
+// A day rollover and a water target of -1 and empty strings mean, that they were not given
+// The eating window none removes the eating window of the current config and without a date
+// format in both, the current date format of the other commands is used
+func (c *ConfigCommand) inheritConfig() {
+	if c.DateFormat == "" || c.Timezone == "" || c.DayRollover == -1 || c.Budget == "" || c.WaterTarget == -1 || c.Formula == "" || c.EatingWindow == "" {
+		if config, err := c.DataSource.FetchConfig(); err == nil {
+			if c.DateFormat == "" {
+				c.DateFormat = config.DateFormat
+			}
+			if c.Timezone == "" {
+				c.Timezone = config.Timezone
+			}
+			if c.DayRollover == -1 {
+				c.DayRollover = config.DayRollover
+			}
+			if c.Budget == "" {
+				c.Budget = config.Budget
+			}
+			if c.WaterTarget == -1 {
+				c.WaterTarget = config.WaterTarget
+				if c.UnitSystem == util.Imperial {
+					c.WaterTarget = util.ToFluidOunces(c.WaterTarget)
+				}
+			}
+			if c.Formula == "" {
+				c.Formula = config.Formula
+			}
+			if c.EatingWindow == "" {
+				c.EatingWindow = config.EatingWindow
+			}
+		}
+	}
+	if c.DateFormat == "" {
+		c.DateFormat = c.CurrentDateFormat
+	}
+	if c.DayRollover == -1 {
+		c.DayRollover = 0
+	}
+	if c.WaterTarget == -1 {
+		c.WaterTarget = 0
+	}
+	if c.EatingWindow == noEatingWindow {
+		c.EatingWindow = ""
+	}
 }
 
-func setConfigAndWeight(c *ConfigCommand, parsedBirthday time.Time) error {
+func setConfigAndWeight(c *ConfigCommand, parsedBirthday, effective time.Time) error {
 	err := c.DataSource.SetConfig(&model.Config{
-		Height:     c.Height,
-		Activity:   c.Activity,
-		Birthday:   parsedBirthday,
-		Gender:     c.Gender,
-		UnitSystem: c.UnitSystem,
+		Effective:    effective,
+		Height:       c.Height,
+		Activity:     c.Activity,
+		Birthday:     parsedBirthday,
+		Gender:       c.Gender,
+		UnitSystem:   c.UnitSystem,
+		DateFormat:   util.NormalizeDateFormat(c.DateFormat),
+		Timezone:     c.Timezone,
+		DayRollover:  c.DayRollover,
+		Budget:       c.Budget,
+		WaterTarget:  c.WaterTarget,
+		Formula:      c.Formula,
+		EatingWindow: c.EatingWindow,
 	})
 	if err != nil {
 		return fmt.Errorf("could not update config: %v", err)
@@ -73,9 +189,9 @@ func checkYesMode(yesMode bool) (bool, error) {
 	return true, nil
 }
 
-// printConfig fetches and prints the current config, calculating the age and the metabolic rates
-// for the current config
-func printConfig(ds datasource.DataSource, r renderer.Renderer) (string, error) {
+// printConfig fetches and prints the current config, calculating the age, the body composition and the metabolic rates
+// for the current config, using the lean body mass, if the formula is katch-mcardle and the body fat is known
+func printConfig(ds datasource.DataSource, r renderer.Renderer, clock util.Clock) (string, error) {
 	config, err := ds.FetchConfig()
 	if err != nil {
 		return "", fmt.Errorf("could not fetch config: %v", err)
@@ -84,7 +200,31 @@ func printConfig(ds datasource.DataSource, r renderer.Renderer) (string, error)
 	if err != nil {
 		return "", fmt.Errorf("could not fetch current weight: %v", err)
 	}
-	age := util.CalculateAgeInYears(config.Birthday)
+	age := util.CalculateAgeInYears(clock, config.Birthday)
+	bodyFat, err := ds.FetchBodyFatForDate(util.Now(clock))
+	if err != nil {
+		return "", fmt.Errorf("could not fetch body fat: %v", err)
+	}
 	bmr, amr := util.CalculateHarrisBenedict(float64(age), config.Height, weight.Weight, config.Activity, config.Gender)
-	return r.Config(config, weight, amr, bmr, age)
+	if config.Formula == util.KatchMcArdle && bodyFat > 0 {
+		bmr, amr = util.CalculateKatchMcArdle(util.LeanMass(weight.Weight, bodyFat), config.Activity)
+	}
+	return r.Config(config, weight, calculateBodyComposition(config.Height, weight.Weight, bodyFat), amr, bmr, age)
+}
+
+// calculateBodyComposition calculates the BMI and the healthy weight range for the given height
+// and the lean mass and FFMI, if the body fat is known
+func calculateBodyComposition(height, weight, bodyFat float64) *model.BodyComposition {
+	bmi := util.CalculateBMI(weight, height)
+	composition := &model.BodyComposition{
+		BMI:         bmi,
+		BMICategory: util.BMICategory(bmi),
+	}
+	composition.HealthyWeightMin, composition.HealthyWeightMax = util.HealthyWeightRange(height)
+	if bodyFat > 0 {
+		composition.BodyFat = bodyFat
+		composition.LeanMass = util.LeanMass(weight, bodyFat)
+		composition.FFMI = util.CalculateFFMI(composition.LeanMass, height)
+	}
+	return composition
 }
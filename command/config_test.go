@@ -2,8 +2,12 @@ package command
 
 import (
 	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
 	"github.com/zupzup/calories/mock"
 	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"math"
 	"testing"
 	"time"
 )
@@ -57,6 +61,7 @@ func TestExecuteConfigOutputModeSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
 	exps.Add("FetchConfig", nil, &dummyConfig)
 	exps.Add("CurrentWeight", nil, &dummyWeight)
+	exps.Add("FetchBodyFatForDate", 0.0, 0.0)
 	c := ConfigCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
 		Renderer:   &mock.Renderer{},
@@ -84,8 +89,10 @@ func TestExecuteConfigSetModeInvalid(t *testing.T) {
 }
 
 func TestExecuteConfigSetModeInvalidBirthday(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", &model.Config{}, errors.New("someError"))
 	c := ConfigCommand{
-		DataSource: &mock.DataSource{},
+		DataSource: &mock.DataSource{Expectations: exps},
 		Renderer:   &mock.Renderer{},
 		Mode:       2,
 		Weight:     85.0,
@@ -103,8 +110,9 @@ func TestExecuteConfigSetModeInvalidBirthday(t *testing.T) {
 
 func TestExecuteConfigSetModeSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
-	exps.Add("FetchConfig", nil, &dummyConfig)
+	exps.Add("FetchConfig", nil, &dummyConfig, &dummyConfig)
 	exps.Add("CurrentWeight", nil, &dummyWeight)
+	exps.Add("FetchBodyFatForDate", 0.0, 0.0)
 	exps.Add("SetConfig", nil, nil)
 	exps.Add("AddWeight", nil, nil)
 	c := ConfigCommand{
@@ -123,3 +131,355 @@ func TestExecuteConfigSetModeSuccess(t *testing.T) {
 		return
 	}
 }
+
+func TestExecuteConfigSetModeInvalidDateFormat(t *testing.T) {
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Weight:     85.0,
+		Height:     185.9,
+		Activity:   1.3,
+		Birthday:   "08.08.1985",
+		DateFormat: "yyyy/mm/dd",
+	}
+	_, err := c.Execute()
+	expected := "wrong date format: yyyy/mm/dd, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidTimezone(t *testing.T) {
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Weight:     85.0,
+		Height:     185.9,
+		Activity:   1.3,
+		Birthday:   "08.08.1985",
+		Timezone:   "Nowhere/Nothing",
+	}
+	_, err := c.Execute()
+	expected := "unknown timezone: Nowhere/Nothing, please use an IANA timezone name (e.g.: Europe/Vienna)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidDayRollover(t *testing.T) {
+	c := ConfigCommand{
+		DataSource:  &mock.DataSource{},
+		Renderer:    &mock.Renderer{},
+		Mode:        2,
+		Weight:      85.0,
+		Height:      185.9,
+		Activity:    1.3,
+		Birthday:    "08.08.1985",
+		DayRollover: 24,
+	}
+	_, err := c.Execute()
+	expected := "wrong day rollover: 24, please use an hour from 0 to 23"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigHistorySuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfigHistory", nil, []model.Config{dummyConfig})
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		History:    true,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteConfigHistoryFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfigHistory", []model.Config{}, errors.New("someError"))
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		History:    true,
+	}
+	_, err := c.Execute()
+	expected := "could not fetch config history: someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidEffectiveDate(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", &model.Config{}, errors.New("someError"))
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Weight:     85.0,
+		Height:     185.9,
+		Activity:   1.3,
+		Birthday:   "08.08.1985",
+		Date:       "bla",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidWaterTarget(t *testing.T) {
+	c := ConfigCommand{
+		DataSource:  &mock.DataSource{},
+		Renderer:    &mock.Renderer{},
+		Mode:        2,
+		Weight:      85.0,
+		Height:      185.9,
+		Activity:    1.3,
+		Birthday:    "08.08.1985",
+		WaterTarget: -5,
+	}
+	_, err := c.Execute()
+	expected := "wrong water target: -5.0, please use a positive amount"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidFormula(t *testing.T) {
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Weight:     85.0,
+		Height:     185.9,
+		Activity:   1.3,
+		Birthday:   "08.08.1985",
+		Formula:    "mifflin",
+	}
+	_, err := c.Execute()
+	expected := "wrong formula: mifflin, please use harris-benedict or katch-mcardle"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigOutputModeLeanMass(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{Activity: 1.5, Formula: util.KatchMcArdle})
+	exps.Add("CurrentWeight", nil, &model.Weight{Weight: 100})
+	exps.Add("FetchBodyFatForDate", 0.0, 20.0)
+	c := ConfigCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Mode:       0,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestCalculateBodyComposition(t *testing.T) {
+	composition := calculateBodyComposition(180, 81, 20)
+	res := fmt.Sprintf("%.1f %s %.1f-%.1f %.1f %.1f", composition.BMI, composition.BMICategory, composition.HealthyWeightMin, composition.HealthyWeightMax, composition.LeanMass, composition.FFMI)
+	expected := "25.0 overweight 59.9-80.7 64.8 20.0"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeInvalidEatingWindow(t *testing.T) {
+	c := ConfigCommand{
+		DataSource:   &mock.DataSource{},
+		Renderer:     &mock.Renderer{},
+		Mode:         2,
+		Weight:       85.0,
+		Height:       185.9,
+		Activity:     1.3,
+		Birthday:     "08.08.1985",
+		EatingWindow: "12-25",
+	}
+	_, err := c.Execute()
+	expected := "wrong eating window: 12-25, please use START-END with hours from 0 to 23 (e.g.: 12-20)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteConfigSetModeKeepsSettings(t *testing.T) {
+	ds := &recordingDataSource{DataSource: mock.DataSource{Expectations: make(mock.Expectations)}}
+	current := dummyConfig
+	current.DateFormat = "yyyy-mm-dd"
+	current.Timezone = "Europe/Vienna"
+	current.DayRollover = 4
+	ds.Expectations.Add("FetchConfig", nil, &current, &current)
+	ds.Expectations.Add("CurrentWeight", nil, &dummyWeight)
+	ds.Expectations.Add("FetchBodyFatForDate", 0.0, 0.0)
+	ds.Expectations.Add("AddWeight", nil, nil)
+	c := ConfigCommand{
+		DataSource:  ds,
+		Renderer:    &mock.Renderer{},
+		Mode:        2,
+		Weight:      85.0,
+		Height:      185.9,
+		Activity:    1.3,
+		Birthday:    "1985-08-08",
+		Date:        "2017-01-05",
+		DayRollover: -1,
+		YesMode:     true,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if ds.config.DateFormat != "yyyy-mm-dd" || ds.config.Birthday.Format(util.DateFormat) != "08.08.1985" || ds.config.Effective.Format(util.DateFormat) != "05.01.2017" ||
+		ds.config.Timezone != "Europe/Vienna" || ds.config.DayRollover != 4 {
+		t.Errorf("Error, actual: %v expected: %v", ds.config, "a config with the settings of the current config")
+		return
+	}
+}
+
+func TestExecuteConfigUpdateTwiceKeepsSettings(t *testing.T) {
+	clock := util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}
+	ds := &datasource.MemoryDataSource{Clock: clock}
+	if _, err := ds.Setup(datasource.MemoryConnection); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	first := ConfigCommand{
+		DataSource:   ds,
+		Renderer:     &mock.Renderer{},
+		Mode:         2,
+		Weight:       85.0,
+		Height:       185.9,
+		Activity:     1.3,
+		Birthday:     "08.08.1985",
+		Gender:       "male",
+		UnitSystem:   util.Imperial,
+		DayRollover:  -1,
+		Budget:       util.WeeklyBudget,
+		WaterTarget:  100,
+		Formula:      util.KatchMcArdle,
+		EatingWindow: "12-20",
+		YesMode:      true,
+		Clock:        clock,
+	}
+	second := first
+	second.Weight = 84.0
+	second.Budget = ""
+	second.WaterTarget = -1
+	second.Formula = ""
+	second.EatingWindow = ""
+	for _, c := range []ConfigCommand{first, second} {
+		if _, err := c.Execute(); err != nil {
+			t.Errorf("Error, actual: %v expected: %v", err, nil)
+			return
+		}
+	}
+	config, err := ds.FetchConfig()
+	if err != nil || config.Budget != util.WeeklyBudget || math.Abs(config.WaterTarget-util.ToMl(100)) > 0.001 || config.Formula != util.KatchMcArdle || config.EatingWindow != "12-20" {
+		t.Errorf("Error, actual: %v expected: %v", config, "the settings of the first update")
+		return
+	}
+	third := second
+	third.EatingWindow = "none"
+	if _, err = third.Execute(); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	config, err = ds.FetchConfig()
+	if err != nil || config.EatingWindow != "" || config.Budget != util.WeeklyBudget {
+		t.Errorf("Error, actual: %v expected: %v", config, "a config without an eating window")
+		return
+	}
+}
+
+func TestExecuteConfigCurrentDateFormat(t *testing.T) {
+	clock := util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}
+	ds := &datasource.MemoryDataSource{Clock: clock}
+	if _, err := ds.Setup(datasource.MemoryConnection); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	c := ConfigCommand{
+		DataSource:        ds,
+		Renderer:          &mock.Renderer{},
+		Mode:              2,
+		Weight:            85.0,
+		Height:            185.9,
+		Activity:          1.3,
+		Birthday:          "1985-08-08",
+		Gender:            "male",
+		UnitSystem:        util.Metric,
+		CurrentDateFormat: "yyyy-mm-dd",
+		DayRollover:       -1,
+		WaterTarget:       -1,
+		YesMode:           true,
+		Clock:             clock,
+	}
+	if _, err := c.Execute(); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	config, err := ds.FetchConfig()
+	if err != nil || config.DateFormat != "yyyy-mm-dd" || config.Birthday.Format(util.DateFormat) != "08.08.1985" {
+		t.Errorf("Error, actual: %v expected: %v", config, "a config with the current date format")
+		return
+	}
+}
+
+// recordingDataSource records the config, which is set
+type recordingDataSource struct {
+	mock.DataSource
+	config *model.Config
+}
+
+// SetConfig records the given config
+func (d *recordingDataSource) SetConfig(config *model.Config) error {
+	d.config = config
+	return nil
+}
+
+func TestExecuteConfigSetModeDefaultRollover(t *testing.T) {
+	ds := &recordingDataSource{DataSource: mock.DataSource{Expectations: make(mock.Expectations)}}
+	ds.Expectations.Add("FetchConfig", &model.Config{}, errors.New("someError"), &dummyConfig)
+	ds.Expectations.Add("CurrentWeight", nil, &dummyWeight)
+	ds.Expectations.Add("FetchBodyFatForDate", 0.0, 0.0)
+	ds.Expectations.Add("AddWeight", nil, nil)
+	c := ConfigCommand{
+		DataSource:  ds,
+		Renderer:    &mock.Renderer{},
+		Mode:        2,
+		Weight:      85.0,
+		Height:      185.9,
+		Activity:    1.3,
+		Birthday:    "08.08.1985",
+		DayRollover: -1,
+		YesMode:     true,
+	}
+	_, err := c.Execute()
+	if err != nil || ds.config.DayRollover != 0 || ds.config.Timezone != "" {
+		t.Errorf("Error, actual: %v, %v expected: %v", ds.config, err, "a config with the default timezone and rollover")
+		return
+	}
+}
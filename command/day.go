@@ -21,12 +21,20 @@ type DayCommand struct {
 	Month       bool
 	History     int
 	DefaultDate string
+	Tag         string
+	Budget      string
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
 }
 
 // Execute shows the current day, if no parameters are used,
 // otherwise shows the days for the given time span (day, week, month, history of days)
+// If a tag is given, only days and entries with this tag are shown
+// In the weekly budget mode, the budget of the week of the last shown day is shown as well
 func (c *DayCommand) Execute() (string, error) {
-	now := time.Now()
+	now := util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover)
 	fromDate := now
 	toDate := now
 	if c.Week {
@@ -40,9 +48,9 @@ func (c *DayCommand) Execute() (string, error) {
 		}
 		fromDate = now.AddDate(0, 0, -amount)
 	} else if c.DefaultDate != "" {
-		parsedDate, err := time.Parse(util.DateFormat, c.DefaultDate)
+		parsedDate, err := util.ParseDate(c.DefaultDate, c.DateFormat)
 		if err != nil {
-			return "", fmt.Errorf("wrong format for date: %v, please use dd.mm.yyyy", err)
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
 		}
 		fromDate = parsedDate
 		toDate = parsedDate
@@ -54,7 +62,101 @@ func (c *DayCommand) Execute() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return c.Renderer.Days(days, fromDate, toDate)
+	days, err = addNoteDays(c.DataSource, days, fromDate, toDate)
+	if err != nil {
+		return "", err
+	}
+	err = addDayDetails(c.DataSource, days)
+	if err != nil {
+		return "", err
+	}
+	if c.Tag != "" {
+		days = filterByTag(days, c.Tag)
+	}
+	var budget *model.WeeklyBudget
+	if c.Budget == util.WeeklyBudget {
+		budget, err = fetchWeeklyBudget(c.DataSource, toDate)
+		if err != nil {
+			return "", err
+		}
+	}
+	return c.Renderer.Days(days, fromDate, toDate, budget)
+}
+
+// addNoteDays adds the days in the given timespan, which have a day note, but no entries,
+// to the given days. After adding, the list of days is sorted by date.
+func addNoteDays(ds datasource.DataSource, days model.Days, from, to time.Time) (model.Days, error) {
+	notes, err := ds.FetchDayNotes()
+	if err != nil {
+		return nil, err
+	}
+	noteDates := map[string]bool{}
+	for _, note := range notes {
+		if !note.IsEmpty() {
+			noteDates[note.Date] = true
+		}
+	}
+	for _, day := range days {
+		delete(noteDates, day.Date.Format(util.DateFormat))
+	}
+	if len(noteDates) == 0 {
+		return days, nil
+	}
+	diffInDays := int(math.Ceil(to.Sub(from).Hours()/24)) + 1
+	for i := 0; i < diffInDays; i++ {
+		date := from.AddDate(0, 0, i)
+		if noteDates[date.Format(util.DateFormat)] {
+			days = append(days, newDay(model.Entries{}, date))
+		}
+	}
+	sort.Sort(days)
+	return days, nil
+}
+
+// addDayDetails adds the notes and the water to the given days, which have any
+func addDayDetails(ds datasource.DataSource, days model.Days) error {
+	for _, day := range days {
+		formattedDate := day.Date.Format(util.DateFormat)
+		note, err := ds.FetchDayNote(formattedDate)
+		if err != nil {
+			return err
+		}
+		if !note.IsEmpty() {
+			day.Note = note
+		}
+		water, err := ds.FetchWater(formattedDate)
+		if err != nil {
+			return err
+		}
+		if water.Amount > 0 {
+			day.Water = water
+		}
+	}
+	return nil
+}
+
+// filterByTag returns the days tagged with the given tag and the days with entries
+// tagged with the given tag, containing only these entries
+func filterByTag(days model.Days, tag string) model.Days {
+	filtered := model.Days{}
+	for _, day := range days {
+		if day.Note != nil && util.HasTag(day.Note.Tags, tag) {
+			filtered = append(filtered, day)
+			continue
+		}
+		entries := model.Entries{}
+		for _, entry := range day.Entries {
+			if util.HasTag(entry.Tags, tag) {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			filteredDay := newDay(entries, day.Date)
+			filteredDay.Note = day.Note
+			filtered = append(filtered, filteredDay)
+		}
+	}
+	return filtered
 }
 
 // fetchDuration fetches all days in the given timespan concurrently, with at most 20
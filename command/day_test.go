@@ -4,13 +4,18 @@ import (
 	"errors"
 	"github.com/zupzup/calories/mock"
 	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
 	"testing"
+	"time"
 )
 
 func TestExecuteDayWeekSuccessEmpty(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	for i := 0; i <= 31; i++ {
 		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
 	}
 	c := DayCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
@@ -44,8 +49,11 @@ func TestExecuteDayWeekFetchFail(t *testing.T) {
 
 func TestExecuteDayWeekSuccessEntries(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	for i := 0; i <= 31; i++ {
 		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
 	}
 	c := DayCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
@@ -61,8 +69,11 @@ func TestExecuteDayWeekSuccessEntries(t *testing.T) {
 
 func TestExecuteDayMonthSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	for i := 0; i <= 31; i++ {
 		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
 	}
 	c := DayCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
@@ -79,7 +90,10 @@ func TestExecuteDayMonthSuccess(t *testing.T) {
 
 func TestExecuteDayDateSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	exps.Add("FetchDayNote", nil, &model.DayNote{})
+	exps.Add("FetchWater", nil, &model.DayWater{})
 	c := DayCommand{
 		DataSource:  &mock.DataSource{Expectations: exps},
 		Renderer:    &mock.Renderer{},
@@ -98,6 +112,8 @@ func TestExecuteDayDateSuccess(t *testing.T) {
 func TestExecuteDayFalseDate(t *testing.T) {
 	exps := make(mock.Expectations)
 	exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	exps.Add("FetchDayNote", nil, &model.DayNote{})
+	exps.Add("FetchWater", nil, &model.DayWater{})
 	c := DayCommand{
 		DataSource:  &mock.DataSource{Expectations: exps},
 		Renderer:    &mock.Renderer{},
@@ -116,7 +132,10 @@ func TestExecuteDayFalseDate(t *testing.T) {
 
 func TestExecuteDayNoDateSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	exps.Add("FetchDayNote", nil, &model.DayNote{})
+	exps.Add("FetchWater", nil, &model.DayWater{})
 	c := DayCommand{
 		DataSource:  &mock.DataSource{Expectations: exps},
 		Renderer:    &mock.Renderer{},
@@ -134,8 +153,11 @@ func TestExecuteDayNoDateSuccess(t *testing.T) {
 
 func TestExecuteDayHistorySuccess(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	for i := 0; i <= 6; i++ {
 		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
 	}
 	c := DayCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
@@ -153,8 +175,11 @@ func TestExecuteDayHistorySuccess(t *testing.T) {
 
 func TestExecuteDayHistoryMinusSuccess(t *testing.T) {
 	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
 	for i := 0; i <= 6; i++ {
 		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
 	}
 	c := DayCommand{
 		DataSource: &mock.DataSource{Expectations: exps},
@@ -169,3 +194,136 @@ func TestExecuteDayHistoryMinusSuccess(t *testing.T) {
 		return
 	}
 }
+
+func TestExecuteDayWeekClock(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
+	for i := 0; i <= 31; i++ {
+		exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+		exps.Add("FetchDayNote", nil, &model.DayNote{})
+		exps.Add("FetchWater", nil, &model.DayWater{})
+	}
+	c := DayCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Week:       true,
+		Location:   time.UTC,
+		Clock:      util.FixedClock{Time: time.Date(2017, 1, 18, 12, 0, 0, 0, time.UTC)},
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected := 3
+	if exps["FetchEntries"].CallCount != expected {
+		t.Errorf("Error, actual: %v expected: %v", exps["FetchEntries"].CallCount, expected)
+		return
+	}
+}
+
+func TestExecuteDayNoteFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	exps.Add("FetchDayNote", &model.DayNote{}, errors.New("someError"))
+	c := DayCommand{
+		DataSource:  &mock.DataSource{Expectations: exps},
+		Renderer:    &mock.Renderer{},
+		DefaultDate: "02.01.2016",
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteDayNoteWithoutEntries(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{
+		model.DayNote{Date: "17.01.2017", Note: "rest day"},
+		model.DayNote{Date: "18.01.2017", Tags: []string{"travel"}},
+		model.DayNote{Date: "19.01.2017", Note: "outside of the week"},
+	})
+	exps.Add("FetchEntries", nil, model.Entries{}, model.Entries{}, model.Entries{})
+	exps.Add("FetchDayNote", nil, &model.DayNote{Note: "rest day"}, &model.DayNote{Tags: []string{"travel"}})
+	exps.Add("FetchWater", nil, &model.DayWater{}, &model.DayWater{})
+	r := &recordingRenderer{}
+	c := DayCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   r,
+		Week:       true,
+		Location:   time.UTC,
+		Clock:      util.FixedClock{Time: time.Date(2017, 1, 18, 12, 0, 0, 0, time.UTC)},
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if len(r.days) != 2 || r.days[0].Date.Day() != 17 || r.days[0].Note.Note != "rest day" || r.days[1].Date.Day() != 18 || len(r.days[1].Entries) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", r.days, "the days with notes on 17.01.2017 and 18.01.2017")
+		return
+	}
+}
+
+func TestExecuteDayDateWeeklyBudget(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNotes", nil, []model.DayNote{})
+	exps.Add("FetchDayNote", nil, &model.DayNote{})
+	exps.Add("FetchWater", nil, &model.DayWater{})
+	for i := 0; i < 7; i++ {
+		exps.Add("FetchEntries", nil, model.Entries{model.Entry{Calories: 1000}})
+		exps.Add("FetchConfigForDate", nil, &dummyConfig)
+		exps.Add("FetchWeightForDate", nil, &dummyWeight)
+	}
+	r := &recordingRenderer{}
+	c := DayCommand{
+		DataSource:  &mock.DataSource{Expectations: exps},
+		Renderer:    r,
+		DefaultDate: "02.01.2016",
+		Budget:      util.WeeklyBudget,
+		Location:    time.UTC,
+		Clock:       util.FixedClock{Time: time.Date(2017, 1, 18, 12, 0, 0, 0, time.UTC)},
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if r.budget == nil || r.budget.From.Format(util.DateFormat) != "28.12.2015" || r.budget.Used != 6000 || r.budget.DaysLeft != 2 {
+		t.Errorf("Error, actual: %v expected: %v", r.budget, "the budget of the week from 28.12.2015 as of 02.01.2016")
+		return
+	}
+}
+
+func TestFilterByTag(t *testing.T) {
+	date, _ := time.Parse(util.DateFormat, "02.01.2016")
+	days := model.Days{
+		newDay(model.Entries{model.Entry{Calories: 100, Tags: []string{"restaurant"}}, model.Entry{Calories: 200}}, date),
+		newDay(model.Entries{model.Entry{Calories: 300}}, date.AddDate(0, 0, 1)),
+		newDay(model.Entries{model.Entry{Calories: 400}}, date.AddDate(0, 0, 2)),
+	}
+	days[2].Note = &model.DayNote{Tags: []string{"restaurant"}}
+	res := filterByTag(days, "#restaurant")
+	if len(res) != 2 || res[0].Used != 100 || len(res[0].Entries) != 1 || res[1].Used != 400 {
+		t.Errorf("Error, actual: %v expected: %v", res, "two days with 100 and 400 calories")
+		return
+	}
+}
+
+// recordingRenderer records the days and the budget, which are rendered
+type recordingRenderer struct {
+	mock.Renderer
+	days   model.Days
+	budget *model.WeeklyBudget
+}
+
+// Days records the given days and budget
+func (r *recordingRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
+	r.days = days
+	r.budget = budget
+	return "", nil
+}
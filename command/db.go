@@ -0,0 +1,207 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"os"
+)
+
+// DBCommand is the command to manage the database file
+// It works on the file directly, so the database must not be opened before
+// Open is used to open the database for creating backups
+// Target is the backup to restore, or the new database for set and move
+// Config is the config file the database is set in, FromFlag is true, if it was set with --db or CALORIES_DB instead
+type DBCommand struct {
+	Renderer        renderer.Renderer
+	Connection      string
+	Config          *util.ConfigFile
+	FromFlag        bool
+	Action          string
+	Target          string
+	BackupRotations int
+	YesMode         bool
+	Passphrase      func(confirm bool) (string, error)
+	Open            func() (datasource.DataSource, func() error, error)
+	Clock           util.Clock
+}
+
+// Execute shows, sets, moves, compacts and checks the database, encrypts or decrypts it
+// with a passphrase, or creates, restores and lists backups of the database
+func (c *DBCommand) Execute() (string, error) {
+	switch c.Action {
+	case "path":
+		if c.FromFlag {
+			return c.Renderer.DBPath(c.Connection, "")
+		}
+		return c.Renderer.DBPath(c.Connection, c.Config.Path)
+	case "set":
+		if c.Target == "" {
+			return "", fmt.Errorf("usage: calories db set [string DATABASE]")
+		}
+		if c.Config.Path == "" {
+			return "", fmt.Errorf("the config file is not used with --db, please set the database without it")
+		}
+		connection, err := datasource.AbsConnection(c.Target)
+		if err != nil {
+			return "", err
+		}
+		if err = c.writeConfigFile(connection); err != nil {
+			return "", err
+		}
+		return c.Renderer.SetDB(connection, c.Config.Path)
+	case "move":
+		if c.Target == "" {
+			return "", fmt.Errorf("usage: calories db move [string PATH]")
+		}
+		connection, err := datasource.CopyDatabase(c.Connection, c.Target)
+		if err != nil {
+			return "", err
+		}
+		if !c.FromFlag {
+			if err = c.writeConfigFile(connection); err != nil {
+				return "", fmt.Errorf("%v, the database was copied to %s", err, connection)
+			}
+		}
+		if err = datasource.RemoveDatabase(c.Connection); err != nil {
+			return "", fmt.Errorf("%v, the database was copied to %s", err, connection)
+		}
+		return c.Renderer.MoveDB(c.Connection, connection)
+	case "compact":
+		compaction, err := datasource.Compact(c.Connection)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Compaction(compaction)
+	case "check":
+		check, err := datasource.Check(c.Connection)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.IntegrityCheck(check)
+	case "stats":
+		stats, err := datasource.Stats(c.Connection)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.DBStats(stats)
+	case "encrypt":
+		path, err := boltPath(c.Connection)
+		if err != nil {
+			return "", err
+		}
+		passphrase, err := c.Passphrase(true)
+		if err != nil {
+			return "", err
+		}
+		err = datasource.EncryptBolt(path, passphrase)
+		if err != nil {
+			return "", err
+		}
+		if err = c.encryptBackups(passphrase); err != nil {
+			return "", err
+		}
+		return c.Renderer.Encryption(path, true)
+	case "decrypt":
+		path, err := boltPath(c.Connection)
+		if err != nil {
+			return "", err
+		}
+		passphrase, err := c.Passphrase(false)
+		if err != nil {
+			return "", err
+		}
+		err = datasource.DecryptBolt(path, passphrase)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Encryption(path, false)
+	case "backup":
+		backups, err := datasource.NewBackups(c.Connection, c.BackupRotations, c.Clock)
+		if err != nil {
+			return "", err
+		}
+		ds, close, err := c.Open()
+		if err != nil {
+			return "", err
+		}
+		backup, err := backups.Create(ds)
+		if closeErr := close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Backup(backup)
+	case "restore":
+		backups, err := datasource.NewBackups(c.Connection, c.BackupRotations, c.Clock)
+		if err != nil {
+			return "", err
+		}
+		if !c.YesMode {
+			choice, askErr := util.AskConfirmation(fmt.Sprintf("Do you really want to restore the database %s from a backup? The current database will be backed up first.", backups.Path), os.Stdin)
+			if askErr != nil || !choice {
+				return "", askErr
+			}
+		}
+		backup, err := backups.Restore(c.Target)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Restore(backup)
+	case "list-backups":
+		backups, err := datasource.NewBackups(c.Connection, c.BackupRotations, c.Clock)
+		if err != nil {
+			return "", err
+		}
+		list, err := backups.List()
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Backups(list)
+	}
+	return "", fmt.Errorf("usage: calories db [path | set DATABASE | move PATH | compact | check | stats | encrypt | decrypt | backup | restore [BACKUP] | list-backups]")
+}
+
+// encryptBackups encrypts the backups of the encrypted database with the same passphrase,
+// so they don't keep a plain copy of it
+func (c *DBCommand) encryptBackups(passphrase string) error {
+	backups, err := datasource.NewBackups(c.Connection, c.BackupRotations, c.Clock)
+	if err == nil {
+		_, err = backups.Encrypt(passphrase)
+	}
+	if err != nil {
+		return fmt.Errorf("the database was encrypted, but its backups are not, %v, please remove them", err)
+	}
+	return nil
+}
+
+// writeConfigFile sets the given database in the config file
+func (c *DBCommand) writeConfigFile(connection string) error {
+	config := *c.Config
+	config.DB = connection
+	return config.Write()
+}
+
+// backupDatabase creates a backup using the given function before changing the database,
+// if backups are enabled
+func backupDatabase(backup func() error) error {
+	if backup == nil {
+		return nil
+	}
+	if err := backup(); err != nil {
+		return fmt.Errorf("could not back up the database, %v", err)
+	}
+	return nil
+}
+
+// boltPath returns the path of the Bolt database of the given connection, or an error, if
+// the connection uses another datasource
+func boltPath(connection string) (string, error) {
+	ds, path := datasource.New(connection)
+	if _, ok := ds.(*datasource.BoltDataSource); !ok {
+		return "", fmt.Errorf("encryption is only supported for Bolt databases, not for %s", connection)
+	}
+	return path, nil
+}
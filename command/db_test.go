@@ -0,0 +1,136 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/util"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteDB(t *testing.T) {
+	passphrase := func(confirm bool) (string, error) {
+		return "", errors.New("no passphrase")
+	}
+	var tests = []struct {
+		description string
+		action      string
+		connection  string
+		expected    string
+	}{
+		{description: "usage", action: "shred", connection: "calories.db", expected: "usage: calories db [path | set DATABASE | move PATH | compact | check | stats | encrypt | decrypt | backup | restore [BACKUP] | list-backups]"},
+		{description: "set without database", action: "set", connection: "calories.db", expected: "usage: calories db set [string DATABASE]"},
+		{description: "move without path", action: "move", connection: "calories.db", expected: "usage: calories db move [string PATH]"},
+		{description: "stats memory", action: "stats", connection: ":memory:", expected: "in-memory databases have no database file"},
+		{description: "check missing", action: "check", connection: "sqlite:///calories-missing.sqlite", expected: "there is no database at /calories-missing.sqlite, stat /calories-missing.sqlite: no such file or directory"},
+		{description: "encrypt sqlite", action: "encrypt", connection: "sqlite://calories.sqlite", expected: "encryption is only supported for Bolt databases, not for sqlite://calories.sqlite"},
+		{description: "decrypt memory", action: "decrypt", connection: ":memory:", expected: "encryption is only supported for Bolt databases, not for :memory:"},
+		{description: "passphrase fails", action: "encrypt", connection: "calories.db", expected: "no passphrase"},
+		{description: "backup memory", action: "backup", connection: ":memory:", expected: "in-memory databases can't be backed up"},
+		{description: "list backups memory", action: "list-backups", connection: ":memory:", expected: "in-memory databases can't be backed up"},
+		{description: "open fails", action: "backup", connection: "calories.db", expected: "could not connect"},
+		{description: "restore without backups", action: "restore", connection: "calories-missing.db", expected: "there are no backups of calories-missing.db"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			c := DBCommand{
+				Renderer:   &mock.Renderer{},
+				Connection: tc.connection,
+				Action:     tc.action,
+				Passphrase: passphrase,
+				YesMode:    true,
+				Open: func() (datasource.DataSource, func() error, error) {
+					return nil, nil, errors.New("could not connect")
+				},
+			}
+			_, err := c.Execute()
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", err, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestExecuteDBSet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Fatalf("could not create temporary directory, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, ".caloriesconf")
+	c := DBCommand{
+		Renderer:   &mock.Renderer{},
+		Connection: "calories.db",
+		Config:     &util.ConfigFile{Path: configFile, Output: "json"},
+		Action:     "set",
+		Target:     "sqlite:///tmp/calories.sqlite",
+	}
+	_, err = c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	config, err := util.ParseConfigFile(content)
+	expected := "sqlite:///tmp/calories.sqlite"
+	if err != nil || config.DB != expected || config.Output != "json" {
+		t.Errorf("Error, actual: %v expected: %v", config, expected)
+		return
+	}
+}
+
+func TestExecuteDBEncryptBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Fatalf("could not create temporary directory, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calories.db")
+	ds := &datasource.BoltDataSource{}
+	close, err := ds.Setup(path)
+	if err != nil {
+		t.Fatalf("could not set up data source, %v", err)
+	}
+	backups := datasource.Backups{Path: path, Keep: 5}
+	_, err = backups.Create(ds)
+	close()
+	if err != nil {
+		t.Fatalf("could not create backup, %v", err)
+	}
+	c := DBCommand{
+		Renderer:        &mock.Renderer{},
+		Connection:      path,
+		Action:          "encrypt",
+		BackupRotations: 5,
+		Passphrase: func(confirm bool) (string, error) {
+			return "secret", nil
+		},
+	}
+	if _, err = c.Execute(); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	list, err := backups.List()
+	if err != nil || len(list) != 1 {
+		t.Errorf("Error, actual: %v expected: %v", list, "one backup")
+		return
+	}
+	encrypted, err := datasource.IsEncrypted(list[0].Path)
+	if err != nil || !encrypted {
+		t.Errorf("Error, actual: %v expected: %v", encrypted, true)
+		return
+	}
+	if err = datasource.DecryptBolt(list[0].Path, "secret"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
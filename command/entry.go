@@ -12,34 +12,39 @@ import (
 
 // ClearEntriesCommand is the command to clear entries for a given day
 type ClearEntriesCommand struct {
-	DataSource datasource.DataSource
-	Renderer   renderer.Renderer
-	Date       string
-	Position   int
-	YesMode    bool
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	Date        string
+	Position    int
+	YesMode     bool
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+	Backup      func() error
 }
 
 // Execute removes all entries of the current day, if no parameters are given
 // otherwise it removes the entries of the given date.
-// Asks the user for confirmation
+// Asks the user for confirmation and backs up the database before removing the entries
 func (c *ClearEntriesCommand) Execute() (string, error) {
-	chosenDate := time.Now()
+	chosenDate := util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover)
 	if c.Date != "" {
-		parsedDate, err := time.Parse(util.DateFormat, c.Date)
+		parsedDate, err := util.ParseDate(c.Date, c.DateFormat)
 		if err != nil {
-			return "", fmt.Errorf("wrong format for date: %v, please use dd.mm.yyyy", err)
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
 		}
 		chosenDate = parsedDate
 	}
 	formattedDate := chosenDate.Format(util.DateFormat)
 	if c.Position >= 0 {
-		return clearSingleEntry(c.DataSource, c.Renderer, c.YesMode, formattedDate, c.Position)
+		return clearSingleEntry(c.DataSource, c.Renderer, c.YesMode, formattedDate, c.Position, c.Backup)
 	}
-	return clearAllEntries(c.DataSource, c.Renderer, c.YesMode, formattedDate)
+	return clearAllEntries(c.DataSource, c.Renderer, c.YesMode, formattedDate, c.Backup)
 }
 
 // clearSingleEntry deletes a single entry based on the given position from the database after asking the user, validating the given position
-func clearSingleEntry(ds datasource.DataSource, r renderer.Renderer, yesMode bool, formattedDate string, position int) (string, error) {
+func clearSingleEntry(ds datasource.DataSource, r renderer.Renderer, yesMode bool, formattedDate string, position int, backup func() error) (string, error) {
 	entries, err := ds.FetchEntries(formattedDate)
 	if err != nil {
 		return "", err
@@ -60,6 +65,9 @@ func clearSingleEntry(ds datasource.DataSource, r renderer.Renderer, yesMode boo
 			return "", nil
 		}
 	}
+	if err = backupDatabase(backup); err != nil {
+		return "", err
+	}
 	err = ds.RemoveEntry(formattedDate, entry.ID)
 	if err != nil {
 		return "", err
@@ -68,7 +76,7 @@ func clearSingleEntry(ds datasource.DataSource, r renderer.Renderer, yesMode boo
 }
 
 // clearAllEntries deletes all entries for a given day, after asking the user
-func clearAllEntries(ds datasource.DataSource, r renderer.Renderer, yesMode bool, formattedDate string) (string, error) {
+func clearAllEntries(ds datasource.DataSource, r renderer.Renderer, yesMode bool, formattedDate string, backup func() error) (string, error) {
 	if !yesMode {
 		choice, err := util.AskConfirmation(fmt.Sprintf("Do you really want to clear all entries for %s? The data will be lost.", formattedDate), os.Stdin)
 		if err != nil {
@@ -78,6 +86,9 @@ func clearAllEntries(ds datasource.DataSource, r renderer.Renderer, yesMode bool
 			return "", nil
 		}
 	}
+	if err := backupDatabase(backup); err != nil {
+		return "", err
+	}
 	err := ds.RemoveEntries(formattedDate)
 	if err != nil {
 		return "", err
@@ -87,36 +98,63 @@ func clearAllEntries(ds datasource.DataSource, r renderer.Renderer, yesMode bool
 
 // AddEntryCommand is the command to add an entry for a day
 type AddEntryCommand struct {
-	DataSource datasource.DataSource
-	Renderer   renderer.Renderer
-	Date       string
-	Food       string
-	Calories   string
-	Mode       int
+	DataSource   datasource.DataSource
+	Renderer     renderer.Renderer
+	Date         string
+	Food         string
+	Calories     string
+	Tags         string
+	EatingWindow string
+	Mode         int
+	DateFormat   string
+	Location     *time.Location
+	DayRollover  int
+	Clock        util.Clock
 }
 
 // Execute shows, if there is no date parameter given, the given calories and food are added to the current day,
-// otherwise to the given date
+// otherwise to the given date. The given tags are attached to the entry
+// If an entry for the current day is added outside of the eating window, a warning is shown
 func (c *AddEntryCommand) Execute() (string, error) {
 	if c.Mode < 2 {
-		return "", fmt.Errorf("usage: calories add [--d=DATE] [--o=FORMAT] CALORIES FOOD")
+		return "", fmt.Errorf("usage: calories add [--d=DATE] [--tag=TAGS] [--o=FORMAT] CALORIES FOOD")
 	}
-	chosenDate := time.Now()
+	chosenDate := util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover)
 	calories, err := strconv.Atoi(c.Calories)
 	if err != nil {
 		return "", fmt.Errorf("wrong format for calories: %s needs to be a number (e.g.: 600)", c.Calories)
 	}
 	if c.Date != "" {
-		parsedDate, parseErr := time.Parse(util.DateFormat, c.Date)
+		parsedDate, parseErr := util.ParseDate(c.Date, c.DateFormat)
 		if parseErr != nil {
-			return "", fmt.Errorf("wrong format for date: %v, please use dd.mm.yyyy", parseErr)
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", parseErr, util.NormalizeDateFormat(c.DateFormat))
 		}
 		chosenDate = parsedDate
 	}
 	formattedDate := chosenDate.Format(util.DateFormat)
-	err = c.DataSource.AddEntry(formattedDate, calories, c.Food)
+	err = c.DataSource.AddEntry(formattedDate, calories, c.Food, util.ParseTags(c.Tags))
 	if err != nil {
 		return "", err
 	}
-	return c.Renderer.AddEntry(formattedDate, calories, c.Food)
+	var warning string
+	if c.Date == "" {
+		now := util.Now(c.Clock)
+		if c.Location != nil {
+			now = now.In(c.Location)
+		}
+		warning = eatingWindowWarning(now, c.EatingWindow)
+	}
+	return c.Renderer.AddEntry(formattedDate, calories, c.Food, warning)
+}
+
+// eatingWindowWarning returns a warning, if the given time is outside of the given eating window
+func eatingWindowWarning(now time.Time, window string) string {
+	if window == "" {
+		return ""
+	}
+	start, end, err := util.ParseEatingWindow(window)
+	if err != nil || util.IsInEatingWindow(now, start, end) {
+		return ""
+	}
+	return fmt.Sprintf("logged outside of your eating window (%s)", util.FormatEatingWindow(window))
 }
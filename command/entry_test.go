@@ -2,9 +2,13 @@ package command
 
 import (
 	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
 	"github.com/zupzup/calories/mock"
 	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
 	"testing"
+	"time"
 )
 
 func TestExecuteEntriesClearWrongDate(t *testing.T) {
@@ -107,6 +111,25 @@ func TestExecuteEntriesClearAllSuccess(t *testing.T) {
 	}
 }
 
+func TestExecuteEntriesClearBackupFail(t *testing.T) {
+	c := ClearEntriesCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2015",
+		Position:   -1,
+		YesMode:    true,
+		Backup: func() error {
+			return errors.New("disk full")
+		},
+	}
+	_, err := c.Execute()
+	expected := "could not back up the database, disk full"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
 func TestExecuteEntryAddWrongUsage(t *testing.T) {
 	c := AddEntryCommand{
 		DataSource: &mock.DataSource{},
@@ -114,7 +137,7 @@ func TestExecuteEntryAddWrongUsage(t *testing.T) {
 		Mode:       0,
 	}
 	_, err := c.Execute()
-	expected := "usage: calories add [--d=DATE] [--o=FORMAT] CALORIES FOOD"
+	expected := "usage: calories add [--d=DATE] [--tag=TAGS] [--o=FORMAT] CALORIES FOOD"
 	if err == nil || err.Error() != expected {
 		t.Errorf("Error, actual: %v expected: %v", err.Error(), expected)
 		return
@@ -186,3 +209,115 @@ func TestExecuteEntrySuccess(t *testing.T) {
 		return
 	}
 }
+
+func TestExecuteEntryAddInvalidDateFormat(t *testing.T) {
+	c := AddEntryCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Calories:   "100",
+		Date:       "13.01.2016",
+		DateFormat: "mm/dd/yyyy",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for date: parsing time \"13.01.2016\": month out of range, please use mm/dd/yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteEntryDateFormatSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("AddEntry", nil, nil)
+	c := AddEntryCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Mode:       2,
+		Calories:   "100",
+		Date:       "2016-02-01",
+		DateFormat: "yyyy-mm-dd",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestEatingWindowWarning(t *testing.T) {
+	testCases := []struct {
+		description string
+		hour        int
+		window      string
+		expected    string
+	}{
+		{"no window", 23, "", ""},
+		{"inside", 13, "12-20", ""},
+		{"outside", 22, "12-20", "logged outside of your eating window (12:00 - 20:00)"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := eatingWindowWarning(time.Date(2017, 1, 1, tc.hour, 0, 0, 0, time.UTC), tc.window)
+			if res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestExecuteAddAndClearEntryWithMemoryDataSource(t *testing.T) {
+	clock := util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}
+	ds := &datasource.MemoryDataSource{Clock: clock}
+	if _, err := ds.Setup(datasource.MemoryConnection); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	err := ds.SetConfig(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		err = ds.AddWeight(80)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	for _, food := range []string{"Pizza", "Apple"} {
+		c := AddEntryCommand{
+			DataSource: ds,
+			Renderer:   &mock.Renderer{},
+			Food:       food,
+			Calories:   "500",
+			Tags:       "cheat",
+			Mode:       2,
+			Location:   time.UTC,
+			Clock:      clock,
+		}
+		if _, err = c.Execute(); err != nil {
+			t.Errorf("Error, actual: %v expected: %v", err, nil)
+			return
+		}
+	}
+	c := ClearEntriesCommand{
+		DataSource: ds,
+		Renderer:   &mock.Renderer{},
+		Position:   1,
+		YesMode:    true,
+		Location:   time.UTC,
+		Clock:      clock,
+	}
+	if _, err = c.Execute(); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Error, actual: %v expected: %v", entries, 1)
+		return
+	}
+	if entries[0].Food != "Apple" || entries[0].ID != 2 || entries[0].Tags[0] != "cheat" || entries[0].BMR == 0 {
+		t.Errorf("Error, actual: %v expected: %v", entries[0], "Apple with id 2")
+		return
+	}
+}
@@ -0,0 +1,138 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"time"
+)
+
+// FastCommand is the command to start and stop fasts and to show the fasting history
+type FastCommand struct {
+	DataSource datasource.DataSource
+	Renderer   renderer.Renderer
+	Action     string
+	Location   *time.Location
+	Clock      util.Clock
+}
+
+// Execute starts or stops a fast, depending on the given action
+// If no action is given, the fasting history is shown
+func (c *FastCommand) Execute() (string, error) {
+	now := util.Now(c.Clock)
+	switch c.Action {
+	case "start":
+		fasts, err := c.DataSource.FetchFasts()
+		if err != nil {
+			return "", err
+		}
+		for _, f := range fasts {
+			if f.IsRunning() {
+				return "", fmt.Errorf("you are already fasting since %s, use calories fast stop to end the fast", c.localTime(f.Start).Format("15:04"))
+			}
+		}
+		err = c.DataSource.StartFast(now)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.StartFast(&model.Fast{Start: c.localTime(now)})
+	case "stop":
+		config, err := c.DataSource.FetchConfig()
+		if err != nil {
+			return "", err
+		}
+		fast, err := c.DataSource.StopFast(now)
+		if err != nil {
+			return "", err
+		}
+		fast.Start = c.localTime(fast.Start)
+		fast.End = c.localTime(fast.End)
+		return c.Renderer.StopFast(fast, util.FastingGoal(config.EatingWindow))
+	case "":
+		config, err := c.DataSource.FetchConfig()
+		if err != nil {
+			return "", err
+		}
+		fasts, err := c.DataSource.FetchFasts()
+		if err != nil {
+			return "", err
+		}
+		for i := range fasts {
+			fasts[i].Start = c.localTime(fasts[i].Start)
+			if !fasts[i].IsRunning() {
+				fasts[i].End = c.localTime(fasts[i].End)
+			}
+		}
+		return c.Renderer.Fasting(calculateFastingHistory(fasts, util.FastingGoal(config.EatingWindow), c.localTime(now)))
+	}
+	return "", fmt.Errorf("usage: calories fast [start|stop]")
+}
+
+// localTime converts the given time to the configured location
+func (c *FastCommand) localTime(t time.Time) time.Time {
+	if c.Location == nil {
+		return t
+	}
+	return t.In(c.Location)
+}
+
+// calculateFastingHistory calculates the average duration of the completed fasts and the streaks of days
+// on which a fast reaching the goal ended. The current streak continues, if there is no such fast today yet
+func calculateFastingHistory(fasts []model.Fast, goal float64, now time.Time) *model.FastingHistory {
+	history := &model.FastingHistory{Goal: goal}
+	reached := map[string]bool{}
+	var first time.Time
+	var totalHours float64
+	for i := range fasts {
+		fast := fasts[i]
+		if fast.IsRunning() {
+			history.Running = &fast
+			history.RunningHours = fast.Hours(now)
+			continue
+		}
+		history.Fasts = append(history.Fasts, fast)
+		hours := fast.Hours(fast.End)
+		totalHours += hours
+		if hours < goal {
+			continue
+		}
+		end := time.Date(fast.End.Year(), fast.End.Month(), fast.End.Day(), 0, 0, 0, 0, time.UTC)
+		if first.IsZero() || end.Before(first) {
+			first = end
+		}
+		reached[end.Format(util.DateFormat)] = true
+	}
+	if len(history.Fasts) > 0 {
+		history.AverageHours = totalHours / float64(len(history.Fasts))
+	}
+	if first.IsZero() {
+		return history
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	streak := 0
+	for date := first; !date.After(today); date = date.AddDate(0, 0, 1) {
+		if !reached[date.Format(util.DateFormat)] {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > history.LongestStreak {
+			history.LongestStreak = streak
+		}
+	}
+
+	date := today
+	if !reached[date.Format(util.DateFormat)] {
+		date = date.AddDate(0, 0, -1)
+	}
+	for ; !date.Before(first); date = date.AddDate(0, 0, -1) {
+		if !reached[date.Format(util.DateFormat)] {
+			break
+		}
+		history.CurrentStreak++
+	}
+	return history
+}
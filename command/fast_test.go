@@ -0,0 +1,128 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"testing"
+	"time"
+)
+
+func TestExecuteFastStart(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchFasts", nil, []model.Fast{{Start: time.Now().AddDate(0, 0, -1), End: time.Now()}})
+	exps.Add("StartFast", nil, nil)
+	c := FastCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "start",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteFastStartRunning(t *testing.T) {
+	start := time.Date(2017, 1, 1, 20, 0, 0, 0, time.UTC)
+	exps := make(mock.Expectations)
+	exps.Add("FetchFasts", nil, []model.Fast{{Start: start}})
+	c := FastCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "start",
+		Location:   time.UTC,
+	}
+	_, err := c.Execute()
+	expected := "you are already fasting since 20:00, use calories fast stop to end the fast"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteFastStop(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{EatingWindow: "12-20"})
+	exps.Add("StopFast", nil, &model.Fast{Start: time.Now().Add(-16 * time.Hour), End: time.Now()})
+	c := FastCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "stop",
+		Location:   time.UTC,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteFastStopNotRunning(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{})
+	exps.Add("StopFast", &model.Fast{}, errors.New("there is no running fast"))
+	c := FastCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "stop",
+	}
+	_, err := c.Execute()
+	expected := "there is no running fast"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteFastHistory(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{})
+	exps.Add("FetchFasts", nil, []model.Fast{{Start: time.Now().Add(-3 * time.Hour)}})
+	c := FastCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Location:   time.UTC,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteFastWrongUsage(t *testing.T) {
+	c := FastCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Action:     "pause",
+	}
+	_, err := c.Execute()
+	expected := "usage: calories fast [start|stop]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestCalculateFastingHistory(t *testing.T) {
+	start := time.Date(2017, 1, 1, 20, 0, 0, 0, time.UTC)
+	fasts := []model.Fast{
+		{Start: start, End: start.Add(16 * time.Hour)},
+		{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 1).Add(17 * time.Hour)},
+		{Start: start.AddDate(0, 0, 2), End: start.AddDate(0, 0, 2).Add(10 * time.Hour)},
+		{Start: start.AddDate(0, 0, 3), End: start.AddDate(0, 0, 3).Add(18 * time.Hour)},
+		{Start: start.AddDate(0, 0, 4)},
+	}
+	now := start.AddDate(0, 0, 4).Add(4 * time.Hour)
+	history := calculateFastingHistory(fasts, util.DefaultFastingGoal, now)
+	res := fmt.Sprintf("%d %.2f %v %.1f %d %d", len(history.Fasts), history.AverageHours, history.Running != nil, history.RunningHours, history.CurrentStreak, history.LongestStreak)
+	expected := "4 15.25 true 4.0 1 2"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
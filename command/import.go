@@ -14,9 +14,10 @@ type ImportCommand struct {
 	DataSource datasource.DataSource
 	Renderer   renderer.Renderer
 	File       string
+	Backup     func() error
 }
 
-// Execute parses and imports the data from the given file
+// Execute parses and imports the data from the given file, backing up the database first
 func (c *ImportCommand) Execute() (string, error) {
 	if c.File == "" {
 		return "", fmt.Errorf("no import file provided")
@@ -30,6 +31,10 @@ func (c *ImportCommand) Execute() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("error parsing json, %v", err)
 	}
+	err = backupDatabase(c.Backup)
+	if err != nil {
+		return "", err
+	}
 	err = c.DataSource.Import(&impex)
 	if err != nil {
 		return "", err
@@ -0,0 +1,57 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+)
+
+// InitCommand is the command to set up calories without any prompts
+// It sets the database in the config file, creates the database and sets the initial config
+// and weight with Configure, if they are given
+type InitCommand struct {
+	Renderer  renderer.Renderer
+	Config    *util.ConfigFile
+	DB        string
+	DefaultDB string
+	Configure *ConfigCommand
+	Open      func(connection string) (datasource.DataSource, func() error, error)
+}
+
+// Execute writes the config file, creates the database and sets the initial config
+func (c *InitCommand) Execute() (string, error) {
+	db := c.DB
+	if db == "" {
+		db = c.DefaultDB
+	}
+	if db == datasource.MemoryConnection {
+		return "", fmt.Errorf("calories init needs a database file, please use --db=[string PATH]")
+	}
+	if c.Configure != nil && c.Configure.Mode < 2 {
+		return "", fmt.Errorf("usage: calories init --db=[string PATH] --w=0.0 --h=0.0 --a=0.0 --b=01.01.1970 --g=male --u=metric")
+	}
+	connection, err := datasource.AbsConnection(db)
+	if err != nil {
+		return "", err
+	}
+	config := *c.Config
+	config.DB = connection
+	if err = config.Write(); err != nil {
+		return "", err
+	}
+	ds, close, err := c.Open(connection)
+	if err != nil {
+		return "", err
+	}
+	defer close()
+	if c.Configure == nil {
+		return c.Renderer.Init(connection, config.Path, false)
+	}
+	c.Configure.DataSource = ds
+	c.Configure.YesMode = true
+	if _, err = c.Configure.Execute(); err != nil {
+		return "", fmt.Errorf("could not set the initial config, %v", err)
+	}
+	return c.Renderer.Init(connection, config.Path, true)
+}
@@ -0,0 +1,81 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/util"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteInit(t *testing.T) {
+	var tests = []struct {
+		description string
+		db          string
+		configure   *ConfigCommand
+		open        error
+		expected    string
+	}{
+		{description: "memory", db: ":memory:", expected: "calories init needs a database file, please use --db=[string PATH]"},
+		{description: "too few config flags", db: "calories.db", configure: &ConfigCommand{Mode: 1}, expected: "usage: calories init --db=[string PATH] --w=0.0 --h=0.0 --a=0.0 --b=01.01.1970 --g=male --u=metric"},
+		{description: "open fails", db: "calories.db", open: errors.New("locked"), expected: "locked"},
+		{description: "invalid config", db: "calories.db", configure: &ConfigCommand{Mode: 2, Weight: -1}, expected: "could not set the initial config, usage: calories config --w=0.0 --h=0.0 --a=0.0 --b=01.01.1970 --g=male --u=metric"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "calories")
+			if err != nil {
+				t.Fatalf("could not create temporary directory, %v", err)
+			}
+			defer os.RemoveAll(dir)
+			c := InitCommand{
+				Renderer:  &mock.Renderer{},
+				Config:    &util.ConfigFile{Path: filepath.Join(dir, "config.json")},
+				DB:        tc.db,
+				Configure: tc.configure,
+				Open: func(connection string) (datasource.DataSource, func() error, error) {
+					return &mock.DataSource{}, func() error { return nil }, tc.open
+				},
+			}
+			_, err = c.Execute()
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", err, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestExecuteInitSuccess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Fatalf("could not create temporary directory, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "calories", "config.json")
+	db := filepath.Join(dir, "calories.db")
+	var opened string
+	c := InitCommand{
+		Renderer:  &mock.Renderer{},
+		Config:    &util.ConfigFile{Path: configFile, Profile: "anna"},
+		DefaultDB: db,
+		Open: func(connection string) (datasource.DataSource, func() error, error) {
+			opened = connection
+			return &mock.DataSource{}, func() error { return nil }, nil
+		},
+	}
+	_, err = c.Execute()
+	if err != nil || opened != db {
+		t.Errorf("Error, actual: %v %v expected: %v", opened, err, db)
+		return
+	}
+	config, found, err := util.ReadConfigFile([]string{configFile})
+	if err != nil || !found || config.DB != db || config.Profile != "anna" {
+		t.Errorf("Error, actual: %v expected: %v", config, db)
+		return
+	}
+}
@@ -0,0 +1,75 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"time"
+)
+
+// MeasureCommand is the command to add and show body measurements
+type MeasureCommand struct {
+	DataSource datasource.DataSource
+	Renderer   renderer.Renderer
+	Waist      float64
+	Hip        float64
+	Chest      float64
+	Neck       float64
+	Arm        float64
+	Thigh      float64
+	BodyFat    float64
+	Location   *time.Location
+}
+
+// Execute shows the measurement timeline, if no measurements are given,
+// otherwise it adds the given measurements (cm or inches, depending on the unit system)
+func (c *MeasureCommand) Execute() (string, error) {
+	config, err := c.DataSource.FetchConfig()
+	if err != nil {
+		return "", err
+	}
+	measurement := &model.Measurement{
+		Waist:   c.Waist,
+		Hip:     c.Hip,
+		Chest:   c.Chest,
+		Neck:    c.Neck,
+		Arm:     c.Arm,
+		Thigh:   c.Thigh,
+		BodyFat: c.BodyFat,
+	}
+	if measurement.IsEmpty() {
+		measurements, fetchErr := c.DataSource.FetchMeasurements()
+		if fetchErr != nil {
+			return "", fetchErr
+		}
+		if c.Location != nil {
+			for i := range measurements {
+				measurements[i].Created = measurements[i].Created.In(c.Location)
+			}
+		}
+		return c.Renderer.Measurements(measurements, config)
+	}
+	for _, value := range []float64{c.Waist, c.Hip, c.Chest, c.Neck, c.Arm, c.Thigh} {
+		if value < 0 {
+			return "", fmt.Errorf("wrong measurement: %.1f, please use a positive number", value)
+		}
+	}
+	if c.BodyFat < 0 || c.BodyFat >= 100 {
+		return "", fmt.Errorf("wrong body fat: %.1f, please use a percentage from 0 to 100", c.BodyFat)
+	}
+	err = c.DataSource.AddMeasurement(measurement)
+	if err != nil {
+		return "", fmt.Errorf("could not save measurements: %v", err)
+	}
+	if config.UnitSystem == util.Imperial {
+		measurement.Waist = util.ToCm(measurement.Waist)
+		measurement.Hip = util.ToCm(measurement.Hip)
+		measurement.Chest = util.ToCm(measurement.Chest)
+		measurement.Neck = util.ToCm(measurement.Neck)
+		measurement.Arm = util.ToCm(measurement.Arm)
+		measurement.Thigh = util.ToCm(measurement.Thigh)
+	}
+	return c.Renderer.AddMeasurement(measurement, config)
+}
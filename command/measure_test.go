@@ -0,0 +1,75 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+	"time"
+)
+
+func TestExecuteMeasureHistory(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{UnitSystem: "metric"})
+	exps.Add("FetchMeasurements", nil, []model.Measurement{{Created: time.Now(), Waist: 80}})
+	c := MeasureCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Location:   time.UTC,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteMeasureAdd(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchConfig", nil, &model.Config{UnitSystem: "imperial"})
+	exps.Add("AddMeasurement", nil, nil)
+	c := MeasureCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Waist:      32,
+		BodyFat:    18.5,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteMeasureErrors(t *testing.T) {
+	testCases := []struct {
+		description string
+		waist       float64
+		bodyFat     float64
+		addErr      error
+		expected    string
+	}{
+		{"negative measurement", -5, 0, nil, "wrong measurement: -5.0, please use a positive number"},
+		{"invalid body fat", 0, 120, nil, "wrong body fat: 120.0, please use a percentage from 0 to 100"},
+		{"saving fails", 80, 0, errors.New("someError"), "could not save measurements: someError"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			exps := make(mock.Expectations)
+			exps.Add("FetchConfig", nil, &model.Config{UnitSystem: "metric"})
+			exps.Add("AddMeasurement", nil, tc.addErr)
+			c := MeasureCommand{
+				DataSource: &mock.DataSource{Expectations: exps},
+				Renderer:   &mock.Renderer{},
+				Waist:      tc.waist,
+				BodyFat:    tc.bodyFat,
+			}
+			_, err := c.Execute()
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", err, tc.expected)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"time"
+)
+
+// NoteCommand is the command to set the tags and the note of a day or of an entry
+type NoteCommand struct {
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	Date        string
+	Position    int
+	Note        string
+	Tags        string
+	Clear       bool
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+}
+
+// Execute sets the given note and tags for the current day, or for the given date
+// If a position is given, they are set for the entry at this position instead
+// Only the given values are replaced, Clear removes the tags and the note
+func (c *NoteCommand) Execute() (string, error) {
+	if c.Note == "" && c.Tags == "" && !c.Clear {
+		return "", errors.New("usage: calories note [--d=DATE] [--p=POSITION] [--tag=TAGS] [--clear] NOTE")
+	}
+	chosenDate := util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover)
+	if c.Date != "" {
+		parsedDate, err := util.ParseDate(c.Date, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		chosenDate = parsedDate
+	}
+	formattedDate := chosenDate.Format(util.DateFormat)
+	if c.Position >= 0 {
+		return c.setEntryNote(formattedDate)
+	}
+	dayNote, err := c.DataSource.FetchDayNote(formattedDate)
+	if err != nil {
+		return "", err
+	}
+	dayNote.Tags, dayNote.Note = c.apply(dayNote.Tags, dayNote.Note)
+	err = c.DataSource.SetDayNote(dayNote)
+	if err != nil {
+		return "", err
+	}
+	return c.Renderer.Note(formattedDate, dayNote.Tags, dayNote.Note, nil)
+}
+
+// setEntryNote sets the note and the tags of the entry at the given position, validating the position
+func (c *NoteCommand) setEntryNote(formattedDate string) (string, error) {
+	entries, err := c.DataSource.FetchEntries(formattedDate)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("could not set note for entry at position %d for %s, there are no entries", c.Position, formattedDate)
+	}
+	if c.Position == 0 || c.Position > len(entries) {
+		return "", fmt.Errorf("could not set note for entry at position %d for %s, value needs to be from %d to %d", c.Position, formattedDate, 1, len(entries))
+	}
+	entry := entries[c.Position-1]
+	entry.Tags, entry.Note = c.apply(entry.Tags, entry.Note)
+	err = c.DataSource.UpdateEntry(&entry)
+	if err != nil {
+		return "", err
+	}
+	return c.Renderer.Note(formattedDate, entry.Tags, entry.Note, &entry)
+}
+
+// apply returns the given tags and note, replaced with the values of the command
+func (c *NoteCommand) apply(tags []string, note string) ([]string, string) {
+	if c.Clear {
+		return nil, ""
+	}
+	if c.Tags != "" {
+		tags = util.ParseTags(c.Tags)
+	}
+	if c.Note != "" {
+		note = c.Note
+	}
+	return tags, note
+}
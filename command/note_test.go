@@ -0,0 +1,111 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+)
+
+func TestExecuteNoteWrongUsage(t *testing.T) {
+	c := NoteCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Position:   -1,
+	}
+	_, err := c.Execute()
+	expected := "usage: calories note [--d=DATE] [--p=POSITION] [--tag=TAGS] [--clear] NOTE"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteNoteWrongDate(t *testing.T) {
+	c := NoteCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Date:       "bla",
+		Note:       "party",
+		Position:   -1,
+	}
+	_, err := c.Execute()
+	expected := "wrong format for date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteNoteDaySuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNote", nil, &model.DayNote{Date: "01.02.2015", Note: "party"})
+	exps.Add("SetDayNote", nil, nil)
+	c := NoteCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2015",
+		Tags:       "#cheatday",
+		Position:   -1,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteNoteDayFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchDayNote", &model.DayNote{}, errors.New("someError"))
+	c := NoteCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2015",
+		Clear:      true,
+		Position:   -1,
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteNoteEntryWrongPosition(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{}})
+	c := NoteCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2015",
+		Note:       "too salty",
+		Position:   2,
+	}
+	_, err := c.Execute()
+	expected := "could not set note for entry at position 2 for 01.02.2015, value needs to be from 1 to 1"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteNoteEntrySuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{ID: 1}})
+	exps.Add("UpdateEntry", nil, nil)
+	c := NoteCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2015",
+		Note:       "too salty",
+		Tags:       "restaurant",
+		Position:   1,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+)
+
+// ProfileCommand is the command to add, list and switch profiles
+type ProfileCommand struct {
+	DataSource datasource.DataSource
+	Renderer   renderer.Renderer
+	Action     string
+	Name       string
+}
+
+// Execute lists all profiles, if no action is given, otherwise it adds the
+// given profile, or makes it the active profile
+func (c *ProfileCommand) Execute() (string, error) {
+	switch c.Action {
+	case "", "list":
+		profiles, err := c.DataSource.FetchProfiles()
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Profiles(profiles)
+	case "add":
+		if c.Name == "" {
+			return "", fmt.Errorf("usage: calories profile add NAME")
+		}
+		err := c.DataSource.AddProfile(c.Name)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.AddProfile(c.Name)
+	case "use":
+		if c.Name == "" {
+			return "", fmt.Errorf("usage: calories profile use NAME")
+		}
+		err := c.DataSource.UseProfile(c.Name)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.UseProfile(c.Name)
+	}
+	return "", fmt.Errorf("usage: calories profile [list | add NAME | use NAME]")
+}
@@ -0,0 +1,99 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+)
+
+func TestExecuteProfileList(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchProfiles", nil, []model.Profile{{Name: "default", Active: true}})
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteProfileListFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchProfiles", []model.Profile{}, errors.New("someError"))
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "list",
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteProfileAddNoName(t *testing.T) {
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Action:     "add",
+	}
+	_, err := c.Execute()
+	expected := "usage: calories profile add NAME"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteProfileAddSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("AddProfile", nil, nil)
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "add",
+		Name:       "anna",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteProfileUseFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("UseProfile", nil, errors.New("could not find profile anna"))
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Action:     "use",
+		Name:       "anna",
+	}
+	_, err := c.Execute()
+	expected := "could not find profile anna"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteProfileUnknownAction(t *testing.T) {
+	c := ProfileCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Action:     "remove",
+	}
+	_, err := c.Execute()
+	expected := "usage: calories profile [list | add NAME | use NAME]"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
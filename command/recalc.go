@@ -0,0 +1,101 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"math"
+	"time"
+)
+
+// RecalcCommand is the command to recalculate the metabolic rates of the entries
+// in a given time span
+type RecalcCommand struct {
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	From        string
+	To          string
+	DryRun      bool
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+}
+
+// Execute recalculates the metabolic rates of all entries from the given from-date
+// to the given to-date (both default to today), using the config and weight effective
+// on the day of each entry. In dry-run mode, the changes are only shown, not saved
+func (c *RecalcCommand) Execute() (string, error) {
+	now := util.TruncateToDate(util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover))
+	fromDate := now
+	toDate := now
+	if c.From != "" {
+		parsedDate, err := util.ParseDate(c.From, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for from-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		fromDate = parsedDate
+		if c.To == "" {
+			toDate = parsedDate
+		}
+	}
+	if c.To != "" {
+		parsedDate, err := util.ParseDate(c.To, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for to-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		toDate = parsedDate
+	}
+	if util.TruncateToDate(toDate).Before(util.TruncateToDate(fromDate)) {
+		return "", errors.New("from-date needs to be before to-date")
+	}
+	days, err := fetchDuration(c.DataSource, fromDate, toDate)
+	if err != nil {
+		return "", err
+	}
+	recalculations := []model.Recalculation{}
+	for _, day := range days {
+		dayRecalculations, recalcErr := recalculateDay(c.DataSource, day, c.DryRun)
+		if recalcErr != nil {
+			return "", recalcErr
+		}
+		recalculations = append(recalculations, dayRecalculations...)
+	}
+	return c.Renderer.Recalc(recalculations, c.DryRun)
+}
+
+// recalculateDay recalculates the metabolic rates of all entries of the given day and
+// returns the entries, whose rates changed. If dryRun is false, the changes are saved
+func recalculateDay(ds datasource.DataSource, day *model.Day, dryRun bool) ([]model.Recalculation, error) {
+	bmr, amr, err := fetchMetabolicRates(ds, day.Date)
+	if err != nil {
+		return nil, err
+	}
+	var res []model.Recalculation
+	for _, entry := range day.Entries {
+		// differences below half a calorie are not visible in the output and are ignored
+		if math.Abs(entry.BMR-bmr) < 0.5 && math.Abs(entry.AMR-amr) < 0.5 {
+			continue
+		}
+		recalculation := model.Recalculation{
+			OldBMR: entry.BMR,
+			OldAMR: entry.AMR,
+			NewBMR: bmr,
+			NewAMR: amr,
+		}
+		entry.BMR = bmr
+		entry.AMR = amr
+		recalculation.Entry = entry
+		if !dryRun {
+			err = ds.UpdateEntry(&entry)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res = append(res, recalculation)
+	}
+	return res, nil
+}
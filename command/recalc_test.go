@@ -0,0 +1,95 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"testing"
+	"time"
+)
+
+func TestExecuteRecalcWrongDate(t *testing.T) {
+	c := RecalcCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		From:       "bla",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for from-date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteRecalcWrongRange(t *testing.T) {
+	c := RecalcCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		From:       "02.01.2017",
+		To:         "01.01.2017",
+	}
+	_, err := c.Execute()
+	expected := "from-date needs to be before to-date"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteRecalcDryRun(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{ID: 1, AMR: 1000, BMR: 800}})
+	exps.Add("FetchConfigForDate", nil, &dummyConfig)
+	exps.Add("FetchWeightForDate", nil, &dummyWeight)
+	c := RecalcCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		From:       "01.01.2017",
+		DryRun:     true,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteRecalcUpdateFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{ID: 1, AMR: 1000, BMR: 800}})
+	exps.Add("FetchConfigForDate", nil, &dummyConfig)
+	exps.Add("FetchWeightForDate", nil, &dummyWeight)
+	exps.Add("UpdateEntry", nil, errors.New("someError"))
+	c := RecalcCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		From:       "01.01.2017",
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteRecalcToDateInTimezone(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Tokyo")
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{}, model.Entries{})
+	c := RecalcCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		To:         "06.01.2017",
+		DryRun:     true,
+		Location:   loc,
+		Clock:      util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+	}
+	_, err := c.Execute()
+	if err != nil || exps["FetchEntries"].CallCount != 2 {
+		t.Errorf("Error, actual: %v, %v expected: %v", err, exps["FetchEntries"].CallCount, 2)
+		return
+	}
+}
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"strings"
+	"time"
+)
+
+// SearchCommand is the command to search the food of all entries
+type SearchCommand struct {
+	DataSource datasource.DataSource
+	Renderer   renderer.Renderer
+	Query      string
+	Tag        string
+	From       string
+	To         string
+	DateFormat string
+}
+
+// Execute searches all entries for the given query, optionally limited by
+// the given tag, from-date and to-date
+func (c *SearchCommand) Execute() (string, error) {
+	query := strings.TrimSpace(c.Query)
+	tag := strings.TrimLeft(strings.TrimSpace(c.Tag), "#")
+	if query == "" && tag == "" {
+		return "", errors.New("usage: calories search [--from=DATE] [--to=DATE] [--tag=TAG] QUERY")
+	}
+	var fromDate, toDate time.Time
+	if c.From != "" {
+		parsedDate, err := util.ParseDate(c.From, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for from-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		fromDate = parsedDate
+	}
+	if c.To != "" {
+		parsedDate, err := util.ParseDate(c.To, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for to-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		toDate = parsedDate
+	}
+	if !fromDate.IsZero() && !toDate.IsZero() && toDate.Before(fromDate) {
+		return "", errors.New("from-date needs to be before to-date")
+	}
+	entries, err := c.DataSource.SearchEntries(query, tag, fromDate, toDate)
+	if err != nil {
+		return "", err
+	}
+	if tag != "" {
+		query = strings.TrimSpace(fmt.Sprintf("%s #%s", query, tag))
+	}
+	return c.Renderer.Search(query, entries)
+}
@@ -0,0 +1,85 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+)
+
+func TestExecuteSearchWrongUsage(t *testing.T) {
+	c := SearchCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Query:      " ",
+	}
+	_, err := c.Execute()
+	expected := "usage: calories search [--from=DATE] [--to=DATE] [--tag=TAG] QUERY"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteSearchWrongDate(t *testing.T) {
+	c := SearchCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Query:      "apple",
+		To:         "bla",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for to-date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteSearchWrongRange(t *testing.T) {
+	c := SearchCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Query:      "apple",
+		From:       "02.01.2017",
+		To:         "01.01.2017",
+	}
+	_, err := c.Execute()
+	expected := "from-date needs to be before to-date"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteSearchFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("SearchEntries", model.Entries{}, errors.New("someError"))
+	c := SearchCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Query:      "apple",
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteSearchSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("SearchEntries", nil, model.Entries{model.Entry{Food: "Apple"}})
+	c := SearchCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Query:      "apple",
+		From:       "01.01.2017",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
@@ -0,0 +1,183 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultStatsDays is the amount of days the statistics are calculated for,
+// if no from-date is given
+const defaultStatsDays = 30
+
+// numTopFoods is the amount of most frequent foods in the statistics
+const numTopFoods = 5
+
+// StatsCommand is the command to show statistics for a range of days
+type StatsCommand struct {
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	From        string
+	To          string
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+}
+
+// Execute shows the statistics from the given from-date to the given to-date
+// The to-date defaults to today, the from-date to 30 days before the to-date
+func (c *StatsCommand) Execute() (string, error) {
+	toDate := util.TruncateToDate(util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover))
+	if c.To != "" {
+		parsedDate, err := util.ParseDate(c.To, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for to-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		toDate = parsedDate
+	}
+	fromDate := toDate.AddDate(0, 0, -(defaultStatsDays - 1))
+	if c.From != "" {
+		parsedDate, err := util.ParseDate(c.From, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for from-date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		fromDate = parsedDate
+	}
+	if toDate.Before(fromDate) {
+		return "", errors.New("from-date needs to be before to-date")
+	}
+	days, err := fetchDuration(c.DataSource, fromDate, toDate)
+	if err != nil {
+		return "", err
+	}
+	stats := calculateStats(days, fromDate, toDate)
+	if err = extendLoggingStreak(c.DataSource, stats, days); err != nil {
+		return "", err
+	}
+	return c.Renderer.Stats(stats)
+}
+
+// extendLoggingStreak continues the current logging streak of the given stats before their from-date
+// until the first day without entries, if the streak reaches the from-date
+func extendLoggingStreak(ds datasource.DataSource, stats *model.Stats, days model.Days) error {
+	end := stats.To
+	if len(days) == 0 || !days[len(days)-1].Date.Equal(end) {
+		end = end.AddDate(0, 0, -1)
+	}
+	if stats.CurrentLoggingStreak == 0 || end.AddDate(0, 0, -(stats.CurrentLoggingStreak-1)).After(stats.From) {
+		return nil
+	}
+	for date := stats.From.AddDate(0, 0, -1); ; date = date.AddDate(0, 0, -1) {
+		entries, err := ds.FetchEntries(date.Format(util.DateFormat))
+		if err != nil {
+			return fmt.Errorf("could not fetch entries for %s, %v", date.Format(util.DateFormat), err)
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		stats.CurrentLoggingStreak++
+	}
+}
+
+// calculateStats calculates the statistics for the given days, which need to be sorted by date
+// A day is under budget, if the used calories are not above the AMR of the day
+// The current logging streak ends at the to-date, or the day before, if there are no
+// entries on the to-date yet and is only counted from the from-date on
+func calculateStats(days model.Days, from, to time.Time) *model.Stats {
+	stats := &model.Stats{From: from, To: to, LoggedDays: len(days), TopFoods: []model.FoodCount{}}
+	byDate := map[string]*model.Day{}
+	weekdays := map[time.Weekday]*model.WeekdayStats{}
+	foods := map[string]*model.FoodCount{}
+	sumIntake := 0
+	sumDeficit := 0.0
+	for _, day := range days {
+		byDate[day.Date.Format(util.DateFormat)] = day
+		deficit := day.AMR() - float64(day.Used)
+		sumIntake += day.Used
+		sumDeficit += deficit
+		if deficit >= 0 {
+			stats.DaysUnderBudget++
+		} else {
+			stats.DaysOverBudget++
+		}
+		weekday, ok := weekdays[day.Date.Weekday()]
+		if !ok {
+			weekday = &model.WeekdayStats{Weekday: day.Date.Weekday().String()}
+			weekdays[day.Date.Weekday()] = weekday
+		}
+		weekday.Days++
+		weekday.AverageIntake += float64(day.Used)
+		weekday.AverageDeficit += deficit
+		for _, entry := range day.Entries {
+			key := strings.ToLower(strings.TrimSpace(entry.Food))
+			food, ok := foods[key]
+			if !ok {
+				food = &model.FoodCount{Food: entry.Food}
+				foods[key] = food
+			}
+			food.Count++
+			food.Calories += entry.Calories
+		}
+	}
+	if len(days) > 0 {
+		stats.AverageIntake = float64(sumIntake) / float64(len(days))
+		stats.AverageDeficit = sumDeficit / float64(len(days))
+	}
+
+	streak := 0
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day, ok := byDate[date.Format(util.DateFormat)]
+		if !ok || float64(day.Used) > day.AMR() {
+			streak = 0
+			continue
+		}
+		streak++
+		if streak > stats.LongestUnderBudgetStreak {
+			stats.LongestUnderBudgetStreak = streak
+		}
+	}
+
+	date := to
+	if _, ok := byDate[date.Format(util.DateFormat)]; !ok {
+		date = date.AddDate(0, 0, -1)
+	}
+	for ; !date.Before(from); date = date.AddDate(0, 0, -1) {
+		if _, ok := byDate[date.Format(util.DateFormat)]; !ok {
+			break
+		}
+		stats.CurrentLoggingStreak++
+	}
+
+	for _, food := range foods {
+		stats.TopFoods = append(stats.TopFoods, *food)
+	}
+	sort.Slice(stats.TopFoods, func(i, j int) bool {
+		if stats.TopFoods[i].Count != stats.TopFoods[j].Count {
+			return stats.TopFoods[i].Count > stats.TopFoods[j].Count
+		}
+		return stats.TopFoods[i].Food < stats.TopFoods[j].Food
+	})
+	if len(stats.TopFoods) > numTopFoods {
+		stats.TopFoods = stats.TopFoods[:numTopFoods]
+	}
+
+	for i := 1; i <= 7; i++ {
+		weekday, ok := weekdays[time.Weekday(i%7)]
+		if !ok {
+			weekday = &model.WeekdayStats{Weekday: time.Weekday(i % 7).String()}
+		}
+		if weekday.Days > 0 {
+			weekday.AverageIntake = weekday.AverageIntake / float64(weekday.Days)
+			weekday.AverageDeficit = weekday.AverageDeficit / float64(weekday.Days)
+		}
+		stats.Weekdays = append(stats.Weekdays, *weekday)
+	}
+	return stats
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+	"testing"
+	"time"
+)
+
+func TestExecuteStatsWrongDate(t *testing.T) {
+	c := StatsCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		From:       "bla",
+		To:         "01.01.2017",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for from-date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteStatsWrongRange(t *testing.T) {
+	c := StatsCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		From:       "02.01.2017",
+		To:         "01.01.2017",
+	}
+	_, err := c.Execute()
+	expected := "from-date needs to be before to-date"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteStatsSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchEntries", nil, model.Entries{model.Entry{Calories: 100, AMR: 2000}}, model.Entries{})
+	c := StatsCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		From:       "01.01.2017",
+		To:         "01.01.2017",
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestCalculateStats(t *testing.T) {
+	from, _ := time.Parse(util.DateFormat, "02.01.2017")
+	to := from.AddDate(0, 0, 6)
+	day := func(offset, calories int, food string) *model.Day {
+		return newDay(model.Entries{model.Entry{Calories: calories, Food: food, AMR: 2000}}, from.AddDate(0, 0, offset))
+	}
+	days := model.Days{
+		day(0, 1500, "Apple"),
+		day(1, 1800, "apple"),
+		day(2, 2500, "Pizza"),
+		day(4, 1000, "Apple"),
+		day(5, 1900, "Pizza"),
+	}
+	stats := calculateStats(days, from, to)
+	if stats.LoggedDays != 5 || stats.AverageIntake != 1740 || stats.AverageDeficit != 260 {
+		t.Errorf("Error, actual: %v %v %v expected: %v %v %v", stats.LoggedDays, stats.AverageIntake, stats.AverageDeficit, 5, 1740, 260)
+		return
+	}
+	if stats.DaysUnderBudget != 4 || stats.DaysOverBudget != 1 {
+		t.Errorf("Error, actual: %v %v expected: %v %v", stats.DaysUnderBudget, stats.DaysOverBudget, 4, 1)
+		return
+	}
+	if stats.LongestUnderBudgetStreak != 2 || stats.CurrentLoggingStreak != 2 {
+		t.Errorf("Error, actual: %v %v expected: %v %v", stats.LongestUnderBudgetStreak, stats.CurrentLoggingStreak, 2, 2)
+		return
+	}
+	if len(stats.TopFoods) != 2 || stats.TopFoods[0].Food != "Apple" || stats.TopFoods[0].Count != 3 || stats.TopFoods[0].Calories != 4300 {
+		t.Errorf("Error, actual: %v expected: %v", stats.TopFoods, "3x Apple (4300 calories)")
+		return
+	}
+	if len(stats.Weekdays) != 7 || stats.Weekdays[0].Weekday != "Monday" || stats.Weekdays[0].Days != 1 || stats.Weekdays[3].Days != 0 {
+		t.Errorf("Error, actual: %v expected: %v", stats.Weekdays, "7 weekdays starting with Monday")
+		return
+	}
+}
+
+func TestExtendLoggingStreak(t *testing.T) {
+	from, _ := time.Parse(util.DateFormat, "02.01.2017")
+	to := from.AddDate(0, 0, 2)
+	entries := model.Entries{model.Entry{Calories: 100, AMR: 2000}}
+	testCases := []struct {
+		description string
+		days        model.Days
+		before      []interface{}
+		expected    int
+		err         string
+	}{
+		{
+			description: "streak reaches the from-date",
+			days:        model.Days{newDay(entries, from), newDay(entries, from.AddDate(0, 0, 1)), newDay(entries, to)},
+			before:      []interface{}{entries, entries, model.Entries{}},
+			expected:    5,
+		},
+		{
+			description: "streak without entries on the to-date",
+			days:        model.Days{newDay(entries, from), newDay(entries, from.AddDate(0, 0, 1))},
+			before:      []interface{}{entries, model.Entries{}},
+			expected:    3,
+		},
+		{
+			description: "streak within the window",
+			days:        model.Days{newDay(entries, from.AddDate(0, 0, 1)), newDay(entries, to)},
+			expected:    2,
+		},
+		{
+			description: "fetch error",
+			days:        model.Days{newDay(entries, from), newDay(entries, from.AddDate(0, 0, 1)), newDay(entries, to)},
+			before:      []interface{}{errors.New("someError")},
+			err:         "could not fetch entries for 01.01.2017, someError",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			exps := make(mock.Expectations)
+			exps.Add("FetchEntries", model.Entries{}, tc.before...)
+			stats := calculateStats(tc.days, from, to)
+			err := extendLoggingStreak(&mock.DataSource{Expectations: exps}, stats, tc.days)
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Errorf("Error, actual: %v expected: %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil || stats.CurrentLoggingStreak != tc.expected {
+				t.Errorf("Error, actual: %v, %v expected: %v", stats.CurrentLoggingStreak, err, tc.expected)
+				return
+			}
+		})
+	}
+}
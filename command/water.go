@@ -0,0 +1,60 @@
+package command
+
+import (
+	"fmt"
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
+	"strconv"
+	"time"
+)
+
+// WaterCommand is the command to add and show the water drunk on a day
+type WaterCommand struct {
+	DataSource  datasource.DataSource
+	Renderer    renderer.Renderer
+	Date        string
+	Amount      string
+	Mode        int
+	DateFormat  string
+	Location    *time.Location
+	DayRollover int
+	Clock       util.Clock
+}
+
+// Execute shows the water drunk on the current day, or on the given date, if no amount is given
+// Otherwise, the given amount (ml or fl oz, depending on the unit system) is added to the day
+func (c *WaterCommand) Execute() (string, error) {
+	chosenDate := util.CurrentDate(util.Now(c.Clock), c.Location, c.DayRollover)
+	if c.Date != "" {
+		parsedDate, err := util.ParseDate(c.Date, c.DateFormat)
+		if err != nil {
+			return "", fmt.Errorf("wrong format for date: %v, please use %s", err, util.NormalizeDateFormat(c.DateFormat))
+		}
+		chosenDate = parsedDate
+	}
+	formattedDate := chosenDate.Format(util.DateFormat)
+	if c.Mode == 0 {
+		water, err := c.DataSource.FetchWater(formattedDate)
+		if err != nil {
+			return "", err
+		}
+		return c.Renderer.Water(formattedDate, water)
+	}
+	amount, err := strconv.ParseFloat(c.Amount, 64)
+	if err != nil || amount <= 0 {
+		return "", fmt.Errorf("wrong format for water: %s needs to be a positive number (e.g.: 500)", c.Amount)
+	}
+	err = c.DataSource.AddWater(formattedDate, amount)
+	if err != nil {
+		return "", err
+	}
+	water, err := c.DataSource.FetchWater(formattedDate)
+	if err != nil {
+		return "", err
+	}
+	if water.UnitSystem == util.Imperial {
+		amount = util.ToMl(amount)
+	}
+	return c.Renderer.AddWater(formattedDate, amount, water)
+}
@@ -0,0 +1,86 @@
+package command
+
+import (
+	"errors"
+	"github.com/zupzup/calories/mock"
+	"github.com/zupzup/calories/model"
+	"testing"
+)
+
+func TestExecuteWaterWrongDate(t *testing.T) {
+	c := WaterCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Date:       "bla",
+	}
+	_, err := c.Execute()
+	expected := "wrong format for date: parsing time \"bla\" as \"02.01.2006\": cannot parse \"bla\" as \"02\", please use dd.mm.yyyy"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteWaterShow(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("FetchWater", nil, &model.DayWater{Amount: 500, Target: 2000})
+	c := WaterCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
+
+func TestExecuteWaterInvalidAmount(t *testing.T) {
+	c := WaterCommand{
+		DataSource: &mock.DataSource{},
+		Renderer:   &mock.Renderer{},
+		Amount:     "-5",
+		Mode:       1,
+	}
+	_, err := c.Execute()
+	expected := "wrong format for water: -5 needs to be a positive number (e.g.: 500)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteWaterAddFail(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("AddWater", nil, errors.New("someError"))
+	c := WaterCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Amount:     "500",
+		Mode:       1,
+	}
+	_, err := c.Execute()
+	expected := "someError"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestExecuteWaterAddSuccess(t *testing.T) {
+	exps := make(mock.Expectations)
+	exps.Add("AddWater", nil, nil)
+	exps.Add("FetchWater", nil, &model.DayWater{Amount: 500, Target: 2000})
+	c := WaterCommand{
+		DataSource: &mock.DataSource{Expectations: exps},
+		Renderer:   &mock.Renderer{},
+		Date:       "01.02.2016",
+		Amount:     "500",
+		Mode:       1,
+	}
+	_, err := c.Execute()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+}
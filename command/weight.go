@@ -5,6 +5,7 @@ import (
 	"github.com/zupzup/calories/datasource"
 	"github.com/zupzup/calories/renderer"
 	"strconv"
+	"time"
 )
 
 // WeightCommand is the command to save and show the configuration
@@ -13,6 +14,7 @@ type WeightCommand struct {
 	Renderer   renderer.Renderer
 	Weight     string
 	Mode       int
+	Location   *time.Location
 }
 
 // Execute shows the weight timeline, if no parameters are given,
@@ -38,5 +40,10 @@ func (c *WeightCommand) Execute() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	if c.Location != nil {
+		for i := range weights {
+			weights[i].Created = weights[i].Created.In(c.Location)
+		}
+	}
 	return c.Renderer.WeightHistory(weights, config)
 }
@@ -0,0 +1,185 @@
+package datasource
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// DefaultBackupRotations is the number of backups, which are kept next to the database file
+const DefaultBackupRotations = 5
+
+// backupSuffix is the suffix of all backup files
+const backupSuffix = ".bak"
+
+// backupTimeFormat is the format of the timestamp in the name of backup files
+const backupTimeFormat = "20060102-150405.000"
+
+// Backups manages the rotating backups, which are kept next to the database file at Path
+// Only the newest Keep backups are kept, all backups are kept, if Keep is 0 or less
+type Backups struct {
+	Path  string
+	Keep  int
+	Clock util.Clock
+}
+
+// NewBackups returns the backups of the database of the given connection, keeping the given number of backups
+func NewBackups(connection string, keep int, clock util.Clock) (*Backups, error) {
+	ds, path := New(connection)
+	if _, ok := ds.(*MemoryDataSource); ok {
+		return nil, fmt.Errorf("in-memory databases can't be backed up")
+	}
+	return &Backups{Path: path, Keep: keep, Clock: clock}, nil
+}
+
+// Create creates a consistent backup of the given data source and removes the oldest backups
+func (b *Backups) Create(ds DataSource) (*model.Backup, error) {
+	path := b.newBackupPath()
+	if err := ds.Backup(path); err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("could not create backup %s, %v", path, err)
+	}
+	return b.rotate(path)
+}
+
+// List returns all backups of the database file, the newest first
+func (b *Backups) List() ([]model.Backup, error) {
+	paths, err := filepath.Glob(b.Path + ".*" + backupSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("could not list backups, %v", err)
+	}
+	backups := []model.Backup{}
+	for _, path := range paths {
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(path, b.Path+"."), backupSuffix)
+		created, parseErr := time.ParseInLocation(backupTimeFormat, timestamp, time.Local)
+		if parseErr != nil {
+			continue
+		}
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			continue
+		}
+		backups = append(backups, model.Backup{Path: path, Created: created, Size: info.Size()})
+	}
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+	return backups, nil
+}
+
+// Restore replaces the database file with the backup with the given name or path, or with the newest
+// backup, if no name is given. The current database file is backed up first
+// The database must not be opened while restoring it
+func (b *Backups) Restore(name string) (*model.Backup, error) {
+	backups, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	var restore *model.Backup
+	for i := range backups {
+		if name == "" || backups[i].Path == name || filepath.Base(backups[i].Path) == name {
+			restore = &backups[i]
+			break
+		}
+	}
+	if restore == nil {
+		if name == "" {
+			return nil, fmt.Errorf("there are no backups of %s", b.Path)
+		}
+		return nil, fmt.Errorf("could not find backup %s of %s", name, b.Path)
+	}
+	if _, statErr := os.Stat(b.Path); statErr == nil {
+		current := b.newBackupPath()
+		if err = copyFile(b.Path, current); err != nil {
+			return nil, fmt.Errorf("could not back up the current database, %v", err)
+		}
+		if _, err = b.rotate(current); err != nil {
+			return nil, err
+		}
+	}
+	if err = copyFile(restore.Path, b.Path); err != nil {
+		return nil, fmt.Errorf("could not restore backup %s, %v", restore.Path, err)
+	}
+	// stale SQLite journals would otherwise be applied to the restored database
+	os.Remove(b.Path + "-wal")
+	os.Remove(b.Path + "-shm")
+	return restore, nil
+}
+
+// Encrypt encrypts all backups, which are not encrypted yet, with the given passphrase, so no
+// plain copies of an encrypted database remain next to it, and returns the encrypted backups
+func (b *Backups) Encrypt(passphrase string) ([]model.Backup, error) {
+	backups, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	encrypted := []model.Backup{}
+	for _, backup := range backups {
+		isEncrypted, checkErr := IsEncrypted(backup.Path)
+		if checkErr != nil {
+			return encrypted, fmt.Errorf("could not encrypt backup %s, %v", backup.Path, checkErr)
+		}
+		if isEncrypted {
+			continue
+		}
+		if err = EncryptBolt(backup.Path, passphrase); err != nil {
+			return encrypted, fmt.Errorf("could not encrypt backup %s, %v", backup.Path, err)
+		}
+		encrypted = append(encrypted, backup)
+	}
+	return encrypted, nil
+}
+
+// newBackupPath returns the path of a new backup created now
+func (b *Backups) newBackupPath() string {
+	return fmt.Sprintf("%s.%s%s", b.Path, util.Now(b.Clock).Local().Format(backupTimeFormat), backupSuffix)
+}
+
+// rotate removes the oldest backups, so only Keep backups remain and returns the backup at the given path
+func (b *Backups) rotate(path string) (*model.Backup, error) {
+	backups, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+	var created *model.Backup
+	for i := range backups {
+		if backups[i].Path == path {
+			created = &backups[i]
+			continue
+		}
+		if b.Keep > 0 && i >= b.Keep {
+			if err = os.Remove(backups[i].Path); err != nil {
+				return nil, fmt.Errorf("could not remove old backup %s, %v", backups[i].Path, err)
+			}
+		}
+	}
+	if created == nil {
+		return nil, fmt.Errorf("could not find backup %s", path)
+	}
+	return created, nil
+}
+
+// copyFile copies the file at src to dst, replacing dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
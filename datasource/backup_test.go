@@ -0,0 +1,158 @@
+package datasource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestBackups(t *testing.T) {
+	var tests = []struct {
+		description string
+		file        string
+		connection  string
+	}{
+		{description: "bolt", file: "calories.db", connection: ""},
+		{description: "sqlite", file: "calories.sqlite", connection: SQLitePrefix},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "calories")
+			if err != nil {
+				t.Fatalf("could not create temporary directory, %v", err)
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, tc.file)
+			ds, _ := New(tc.connection + path)
+			close, err := ds.Setup(path)
+			if err != nil {
+				t.Fatalf("could not set up data source, %v", err)
+			}
+			birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+			err = ds.SetConfig(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+			if err == nil {
+				err = ds.AddWeight(80)
+			}
+			if err == nil {
+				err = ds.AddEntry("05.01.2017", 100, "Apple", nil)
+			}
+			if err != nil {
+				t.Fatalf("could not add data, %v", err)
+			}
+			backups := Backups{Path: path, Keep: 2}
+			for i := 0; i < 3; i++ {
+				backups.Clock = util.FixedClock{Time: time.Date(2017, 1, 5, 12, i, 0, 0, time.Local)}
+				if _, err = backups.Create(ds); err != nil {
+					t.Errorf("Error, actual: %v expected: %v", err, nil)
+					return
+				}
+			}
+			list, err := backups.List()
+			if err != nil || len(list) != 2 {
+				t.Errorf("Error, actual: %v expected: %v", list, 2)
+				return
+			}
+			expected := path + ".20170105-120200.000.bak"
+			if list[0].Path != expected || list[1].Path != path+".20170105-120100.000.bak" {
+				t.Errorf("Error, actual: %v expected: %v", list[0].Path, expected)
+				return
+			}
+			if err = ds.RemoveEntries("05.01.2017"); err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			if err = close(); err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			backups.Clock = util.FixedClock{Time: time.Date(2017, 1, 5, 12, 3, 0, 0, time.Local)}
+			restored, err := backups.Restore("")
+			if err != nil || restored.Path != expected {
+				t.Errorf("Error, actual: %v %v expected: %v", restored, err, expected)
+				return
+			}
+			list, _ = backups.List()
+			if len(list) != 2 || list[0].Path != path+".20170105-120300.000.bak" {
+				t.Errorf("Error, actual: %v expected: %v", list, "backup before restoring")
+				return
+			}
+			if _, err = backups.Restore("calories.db.missing.bak"); err == nil || err.Error() != fmt.Sprintf("could not find backup calories.db.missing.bak of %s", path) {
+				t.Errorf("Error, actual: %v expected: %v", err, "missing backup")
+				return
+			}
+			ds, _ = New(tc.connection + path)
+			close, err = ds.Setup(path)
+			if err != nil {
+				t.Fatalf("could not set up data source, %v", err)
+			}
+			defer close()
+			entries, err := ds.FetchEntries("05.01.2017")
+			if err != nil || len(entries) != 1 || entries[0].Food != "Apple" {
+				t.Errorf("Error, actual: %v %v expected: %v", entries, err, "Apple")
+				return
+			}
+		})
+	}
+}
+
+func TestNewBackups(t *testing.T) {
+	if _, err := NewBackups(MemoryConnection, 5, nil); err == nil || err.Error() != "in-memory databases can't be backed up" {
+		t.Errorf("Error, actual: %v expected: %v", err, "in-memory databases can't be backed up")
+		return
+	}
+	backups, err := NewBackups("sqlite://calories.sqlite", 5, nil)
+	if err != nil || backups.Path != "calories.sqlite" || backups.Keep != 5 {
+		t.Errorf("Error, actual: %v expected: %v", backups, "calories.sqlite")
+		return
+	}
+}
+
+func TestEncryptBackups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Fatalf("could not create temporary directory, %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calories.db")
+	ds := &BoltDataSource{}
+	close, err := ds.Setup(path)
+	if err != nil {
+		t.Fatalf("could not set up data source, %v", err)
+	}
+	backups := Backups{Path: path}
+	for i := 0; i < 2; i++ {
+		backups.Clock = util.FixedClock{Time: time.Date(2017, 1, 5, 12, i, 0, 0, time.Local)}
+		if _, err = backups.Create(ds); err != nil {
+			close()
+			t.Fatalf("could not create backup, %v", err)
+		}
+	}
+	close()
+	if err = EncryptBolt(path+".20170105-120000.000.bak", "older"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	encrypted, err := backups.Encrypt("secret")
+	expected := path + ".20170105-120100.000.bak"
+	if err != nil || len(encrypted) != 1 || encrypted[0].Path != expected {
+		t.Errorf("Error, actual: %v %v expected: %v", encrypted, err, expected)
+		return
+	}
+	for _, tc := range []struct{ backup, passphrase string }{{expected, "secret"}, {path + ".20170105-120000.000.bak", "older"}} {
+		if err = DecryptBolt(tc.backup, tc.passphrase); err != nil {
+			t.Errorf("Error, actual: %v expected: %v", err, nil)
+			return
+		}
+	}
+	encrypted, err = backups.Encrypt("secret")
+	if err != nil || len(encrypted) != 2 {
+		t.Errorf("Error, actual: %v %v expected: %v", encrypted, err, "both backups")
+		return
+	}
+}
@@ -2,77 +2,304 @@ package datasource
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/asdine/storm"
 	"github.com/asdine/storm/q"
+	"github.com/boltdb/bolt"
 	"github.com/zupzup/calories/model"
 	"github.com/zupzup/calories/util"
 )
 
+// DefaultProfile is the name of the profile, which is created for
+// databases without any profiles
+const DefaultProfile = "default"
+
 // BoltDataSource is an implementation of the DataSource interface for boltdb
+// Clock is used for timestamping new weights and entries
+// Passphrase is used to get the passphrase, if the database is encrypted
+// All data is scoped by the selected profile
 type BoltDataSource struct {
-	DB *storm.DB
+	DB         *storm.DB
+	Clock      util.Clock
+	Passphrase func() (string, error)
+	profileID  int
 }
 
-// Setup creates the file and the table structure
+// Setup creates the file and the table structure, migrates data without a profile
+// into the default profile and selects the active profile
+// Encrypted databases are decrypted transparently using the passphrase
 func (ds *BoltDataSource) Setup(connection string) (func() error, error) {
-	db, err := storm.Open(connection)
+	boltDB, err := openBolt(connection)
+	if err != nil {
+		return nil, fmt.Errorf("error while connecting to database at %s, %v", connection, err)
+	}
+	codec, err := codecForPassphrase(boltDB, ds.Passphrase)
+	boltDB.Close()
+	if err != nil {
+		return nil, err
+	}
+	db, err := storm.Open(connection, storm.Codec(codec))
 	if err != nil {
 		return nil, fmt.Errorf("error while connecting to database at %s, %v", connection, err)
 	}
 	ds.DB = db
+	err = ds.migrateProfiles()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error while migrating data to profiles, %v", err)
+	}
+	profile, err := ds.CurrentProfile()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	ds.profileID = profile.ID
 	return db.Close, nil
 }
 
-// SetConfig overrides the current config with the given values
-// by deleting the old config and adding a new one
+// migrateProfiles assigns all configs, weights and entries without a profile to the default
+// profile, creating it, if there are no profiles yet
+// The migration runs in one transaction and looks for records without a profile on every start,
+// so no records are left behind, if it fails
+func (ds *BoltDataSource) migrateProfiles() error {
+	tx, err := ds.DB.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	profile, err := migrationProfile(tx)
+	if err != nil {
+		return err
+	}
+	var configs []model.Config
+	err = tx.Select(q.Eq("ProfileID", 0)).Find(&configs)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	for i := range configs {
+		if err = tx.UpdateField(&configs[i], "ProfileID", profile.ID); err != nil {
+			return err
+		}
+	}
+	var weights []model.Weight
+	err = tx.Select(q.Eq("ProfileID", 0)).Find(&weights)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	for i := range weights {
+		if err = tx.UpdateField(&weights[i], "ProfileID", profile.ID); err != nil {
+			return err
+		}
+	}
+	var entries []model.Entry
+	err = tx.Select(q.Eq("ProfileID", 0)).Find(&entries)
+	if err != nil && err != storm.ErrNotFound {
+		return err
+	}
+	for i := range entries {
+		if err = tx.UpdateField(&entries[i], "ProfileID", profile.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// migrationProfile returns the profile, to which records without a profile are migrated
+// This is the default profile, or the first profile, if there is no default profile
+// If there are no profiles yet, the default profile is created
+func migrationProfile(tx storm.Node) (*model.Profile, error) {
+	var profiles []model.Profile
+	if err := tx.All(&profiles); err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if profiles[i].Name == DefaultProfile {
+			return &profiles[i], nil
+		}
+	}
+	if len(profiles) > 0 {
+		return &profiles[0], nil
+	}
+	profile := model.Profile{Name: DefaultProfile, Active: true}
+	if err := tx.Save(&profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// AddProfile adds a new profile with the given name
+func (ds *BoltDataSource) AddProfile(name string) error {
+	err := ds.DB.Save(&model.Profile{Name: name})
+	if err == storm.ErrAlreadyExists {
+		return fmt.Errorf("profile %s already exists", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not add profile %s, %v", name, err)
+	}
+	return nil
+}
+
+// FetchProfiles fetches all profiles
+func (ds *BoltDataSource) FetchProfiles() ([]model.Profile, error) {
+	var profiles []model.Profile
+	err := ds.DB.All(&profiles)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve profiles: %v", err)
+	}
+	return profiles, nil
+}
+
+// CurrentProfile fetches and returns the active profile
+func (ds *BoltDataSource) CurrentProfile() (*model.Profile, error) {
+	var profile model.Profile
+	err := ds.DB.Select(q.Eq("Active", true)).First(&profile)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch active profile: %v", err)
+	}
+	return &profile, nil
+}
+
+// SelectProfile selects the profile with the given name for all following operations,
+// without making it the active profile
+func (ds *BoltDataSource) SelectProfile(name string) error {
+	var profile model.Profile
+	err := ds.DB.One("Name", name, &profile)
+	if err != nil {
+		return fmt.Errorf("could not find profile %s, %v", name, err)
+	}
+	ds.profileID = profile.ID
+	return nil
+}
+
+// UseProfile makes the profile with the given name the active profile
+func (ds *BoltDataSource) UseProfile(name string) error {
+	var profile model.Profile
+	err := ds.DB.One("Name", name, &profile)
+	if err != nil {
+		return fmt.Errorf("could not find profile %s, %v", name, err)
+	}
+	current, err := ds.CurrentProfile()
+	if err == nil {
+		err = ds.DB.UpdateField(current, "Active", false)
+		if err != nil {
+			return fmt.Errorf("could not deactivate profile %s, %v", current.Name, err)
+		}
+	}
+	err = ds.DB.UpdateField(&profile, "Active", true)
+	if err != nil {
+		return fmt.Errorf("could not activate profile %s, %v", name, err)
+	}
+	ds.profileID = profile.ID
+	return nil
+}
+
+// SetConfig adds a new version of the config with the given values, which is effective
+// from the given effective date on, or from today, if no effective date is given
+// A version with the same effective date is replaced
 func (ds *BoltDataSource) SetConfig(c *model.Config) error {
-	ds.DB.Drop(&model.Config{})
-	if c.UnitSystem != "metric" && c.UnitSystem != "imperial" {
-		return fmt.Errorf("unit system needs to be either metric or imperial: %s", c.UnitSystem)
+	if err := validateConfig(c); err != nil {
+		return err
 	}
-	height := c.Height
-	if c.UnitSystem == util.Imperial {
-		height = util.ToCm(height)
+	config := newConfigVersion(c, ds.profileID, util.Now(ds.Clock))
+	tx, err := ds.DB.Begin(true)
+	if err != nil {
+		return fmt.Errorf("could not replace config effective from %s, %v", config.Effective.Format(util.DateFormat), err)
 	}
-	config := model.Config{
-		Height:     height,
-		Activity:   c.Activity,
-		Birthday:   c.Birthday,
-		Gender:     c.Gender,
-		UnitSystem: c.UnitSystem,
+	defer tx.Rollback()
+	configs, err := ds.configHistory(tx)
+	if err != nil {
+		return err
 	}
-	err := ds.DB.Save(&config)
-	return err
+	for i := range configs {
+		if configs[i].Effective.Equal(config.Effective) {
+			err = tx.DeleteStruct(&configs[i])
+			if err != nil {
+				return fmt.Errorf("could not replace config effective from %s, %v", config.Effective.Format(util.DateFormat), err)
+			}
+		}
+	}
+	if err = tx.Save(&config); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// SetConfigFromImport overrides the current config with the given values
-// by deleting the old config and adding a new one
+// SetConfigFromImport overrides the config history with the given values
+// by deleting all old configs and adding a new one
 func (ds *BoltDataSource) SetConfigFromImport(c *model.Config) error {
-	ds.DB.Drop(&model.Config{})
+	tx, err := ds.DB.Begin(true)
+	if err != nil {
+		return fmt.Errorf("could not replace config, %v", err)
+	}
+	defer tx.Rollback()
+	if err = ds.removeConfigs(tx); err != nil {
+		return err
+	}
+	if err = ds.addConfigFromImport(tx, c); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// addConfigFromImport adds the given config version without converting any units
+func (ds *BoltDataSource) addConfigFromImport(node storm.Node, c *model.Config) error {
 	if c.UnitSystem != "metric" && c.UnitSystem != "imperial" {
 		return fmt.Errorf("unit system needs to be either metric or imperial: %s", c.UnitSystem)
 	}
-	config := model.Config{
-		Height:     c.Height,
-		Activity:   c.Activity,
-		Birthday:   c.Birthday,
-		Gender:     c.Gender,
-		UnitSystem: c.UnitSystem,
+	config := importedConfigVersion(c, ds.profileID)
+	return node.Save(&config)
+}
+
+// removeConfigs removes all configs of the selected profile
+func (ds *BoltDataSource) removeConfigs(node storm.Node) error {
+	err := node.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Config))
+	if err != nil && err != storm.ErrNotFound {
+		return err
 	}
-	err := ds.DB.Save(&config)
-	return err
+	return nil
 }
 
-// FetchConfig fetches and returns the current config
-func (ds *BoltDataSource) FetchConfig() (config *model.Config, err error) {
-	var configs []model.Config
-	err = ds.DB.All(&configs, storm.Limit(1), storm.Reverse())
+// FetchConfig fetches and returns the config, which is effective today in its timezone
+func (ds *BoltDataSource) FetchConfig() (*model.Config, error) {
+	configs, err := ds.FetchConfigHistory()
 	if err != nil || len(configs) == 0 {
 		return nil, fmt.Errorf("could not retrieve config: %v", err)
 	}
-	return &configs[0], nil
+	return configForToday(configs, util.Now(ds.Clock)), nil
+}
+
+// FetchConfigForDate fetches and returns the config, which is effective on the given date
+// If there is no config effective on that date, the oldest config is returned
+func (ds *BoltDataSource) FetchConfigForDate(date time.Time) (*model.Config, error) {
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) == 0 {
+		return nil, fmt.Errorf("could not retrieve config: %v", err)
+	}
+	return configForDate(configs, date), nil
+}
+
+// FetchConfigHistory fetches all config versions, sorted by their effective date
+func (ds *BoltDataSource) FetchConfigHistory() ([]model.Config, error) {
+	return ds.configHistory(ds.DB)
+}
+
+// configHistory fetches all config versions of the selected profile using the given node,
+// sorted by their effective date
+func (ds *BoltDataSource) configHistory(node storm.Node) ([]model.Config, error) {
+	var configs []model.Config
+	err := node.Select(q.Eq("ProfileID", ds.profileID)).Find(&configs)
+	if err != nil {
+		if err == storm.ErrNotFound {
+			return configs, nil
+		}
+		return nil, fmt.Errorf("could not retrieve config history: %v", err)
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return configs[i].Effective.Before(configs[j].Effective)
+	})
+	return configs, nil
 }
 
 // AddWeight adds the given weight for todays date
@@ -85,8 +312,9 @@ func (ds *BoltDataSource) AddWeight(weight float64) error {
 		weight = util.ToKg(weight)
 	}
 	weightObj := model.Weight{
-		Created: time.Now(),
-		Weight:  weight,
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		Weight:    weight,
 	}
 	err = ds.DB.Save(&weightObj)
 	return err
@@ -95,43 +323,63 @@ func (ds *BoltDataSource) AddWeight(weight float64) error {
 // CurrentWeight fetches and returns the current weight, which is the last entry in the table
 func (ds *BoltDataSource) CurrentWeight() (*model.Weight, error) {
 	var weights []model.Weight
-	err := ds.DB.All(&weights, storm.Limit(1), storm.Reverse())
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Limit(1).Reverse().Find(&weights)
 	if err != nil || len(weights) == 0 {
 		return nil, fmt.Errorf("could not fetch current weight: %v", err)
 	}
 	return &weights[0], nil
 }
 
+// FetchWeightForDate fetches and returns the weight on the given date, which is the last
+// weight added on or before that date. If there is no such weight, the oldest weight is returned
+func (ds *BoltDataSource) FetchWeightForDate(date time.Time) (*model.Weight, error) {
+	weights, err := ds.FetchWeights()
+	if err != nil || len(weights) == 0 {
+		return nil, fmt.Errorf("could not fetch weight for %s: %v", date.Format(util.DateFormat), err)
+	}
+	config, _ := ds.FetchConfigForDate(date)
+	return weightForDate(weights, date, config), nil
+}
+
 // FetchWeights fetches all weight entries
 func (ds *BoltDataSource) FetchWeights() ([]model.Weight, error) {
 	var weights []model.Weight
-	err := ds.DB.All(&weights)
-	if err != nil {
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&weights)
+	if err != nil && err != storm.ErrNotFound {
 		return nil, fmt.Errorf("could not retrieve weight history: %v", err)
 	}
 	return weights, nil
 }
 
-// AddEntry fetches the current config and weight to calculate the metabolic rate and adds the data
-// into the entry table
-func (ds *BoltDataSource) AddEntry(entryDate string, calories int, food string) error {
-	weight, err := ds.CurrentWeight()
+// AddEntry fetches the config, weight and body fat effective on the entry date to calculate the metabolic
+// rate and adds the data into the entry table
+func (ds *BoltDataSource) AddEntry(entryDate string, calories int, food string, tags []string) error {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	weight, err := ds.FetchWeightForDate(date)
 	if err != nil {
 		return err
 	}
-	config, err := ds.FetchConfig()
+	config, err := ds.FetchConfigForDate(date)
+	if err != nil {
+		return err
+	}
+	bodyFat, err := ds.FetchBodyFatForDate(date)
 	if err != nil {
 		return err
 	}
-	age := float64(util.CalculateAgeInYears(config.Birthday))
-	bmr, amr := util.CalculateHarrisBenedict(age, config.Height, weight.Weight, config.Activity, config.Gender)
+	bmr, amr := util.CalculateMetabolicRatesForFormula(config.Formula, date, config.Birthday, config.Height, weight.Weight, bodyFat, config.Activity, config.Gender)
 	entry := model.Entry{
-		Created:   time.Now(),
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
 		EntryDate: entryDate,
 		Calories:  calories,
 		Food:      food,
 		AMR:       amr,
 		BMR:       bmr,
+		Tags:      tags,
 	}
 	err = ds.DB.Save(&entry)
 	if err != nil {
@@ -140,10 +388,22 @@ func (ds *BoltDataSource) AddEntry(entryDate string, calories int, food string)
 	return nil
 }
 
+// UpdateEntry saves the given, already existing entry
+func (ds *BoltDataSource) UpdateEntry(entry *model.Entry) error {
+	if entry.ID == 0 || entry.ProfileID != ds.profileID {
+		return fmt.Errorf("could not update entry with id %d, it does not exist", entry.ID)
+	}
+	err := ds.DB.Save(entry)
+	if err != nil {
+		return fmt.Errorf("could not update entry with id %d, %v", entry.ID, err)
+	}
+	return nil
+}
+
 // FetchEntries fetches and returns all entries for a given date
 func (ds *BoltDataSource) FetchEntries(entryDate string) (model.Entries, error) {
 	var entries []model.Entry
-	err := ds.DB.Find("EntryDate", entryDate, &entries)
+	err := ds.DB.Select(q.And(q.Eq("EntryDate", entryDate), q.Eq("ProfileID", ds.profileID))).Find(&entries)
 	if err != nil {
 		if err == storm.ErrNotFound {
 			return entries, nil
@@ -156,7 +416,7 @@ func (ds *BoltDataSource) FetchEntries(entryDate string) (model.Entries, error)
 // FetchAllEntries fetches and returns all entries
 func (ds *BoltDataSource) FetchAllEntries() (model.Entries, error) {
 	var entries []model.Entry
-	err := ds.DB.All(&entries)
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&entries)
 	if err != nil {
 		if err == storm.ErrNotFound {
 			return entries, nil
@@ -166,9 +426,25 @@ func (ds *BoltDataSource) FetchAllEntries() (model.Entries, error) {
 	return entries, nil
 }
 
+// SearchEntries fetches all entries, whose food matches the given query and which are tagged
+// with the given tag, either directly or by their day, sorted by date
+// An empty query or tag is not used for filtering. A zero from-date or to-date means, that the
+// search is not limited in this direction
+func (ds *BoltDataSource) SearchEntries(query, tag string, from, to time.Time) (model.Entries, error) {
+	entries, err := ds.FetchAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	dayNotes, err := ds.FetchDayNotes()
+	if err != nil {
+		return nil, err
+	}
+	return searchEntries(entries, dayNotes, query, tag, from, to)
+}
+
 // RemoveEntries removes all entries for a given day from the database
 func (ds *BoltDataSource) RemoveEntries(entryDate string) error {
-	query := ds.DB.Select(q.Eq("EntryDate", entryDate))
+	query := ds.DB.Select(q.And(q.Eq("EntryDate", entryDate), q.Eq("ProfileID", ds.profileID)))
 	err := query.Delete(new(model.Entry))
 	if err != nil {
 		return fmt.Errorf("could not delete entries for %s", entryDate)
@@ -178,7 +454,7 @@ func (ds *BoltDataSource) RemoveEntries(entryDate string) error {
 
 // RemoveEntry removes the entry with the given id for a given day from the database
 func (ds *BoltDataSource) RemoveEntry(entryDate string, id int) error {
-	query := ds.DB.Select(q.And(q.Eq("EntryDate", entryDate), q.Eq("ID", id)))
+	query := ds.DB.Select(q.And(q.Eq("EntryDate", entryDate), q.Eq("ID", id), q.Eq("ProfileID", ds.profileID)))
 	err := query.Delete(new(model.Entry))
 	if err != nil {
 		return fmt.Errorf("could not delete entry with id %d on day %s", id, entryDate)
@@ -186,36 +462,316 @@ func (ds *BoltDataSource) RemoveEntry(entryDate string, id int) error {
 	return nil
 }
 
+// SetDayNote sets the tags and the note of a day, replacing the previous ones
+// If there are neither tags nor a note, the note of the day is removed
+func (ds *BoltDataSource) SetDayNote(note *model.DayNote) error {
+	existing, err := ds.FetchDayNote(note.Date)
+	if err != nil {
+		return err
+	}
+	if note.IsEmpty() {
+		if existing.ID == 0 {
+			return nil
+		}
+		err = ds.DB.DeleteStruct(existing)
+		if err != nil {
+			return fmt.Errorf("could not remove note for %s, %v", note.Date, err)
+		}
+		return nil
+	}
+	dayNote := model.DayNote{
+		ID:        existing.ID,
+		ProfileID: ds.profileID,
+		Date:      note.Date,
+		Tags:      note.Tags,
+		Note:      note.Note,
+	}
+	err = ds.DB.Save(&dayNote)
+	if err != nil {
+		return fmt.Errorf("could not set note for %s, %v", note.Date, err)
+	}
+	return nil
+}
+
+// FetchDayNote fetches the note of the given day, returning an empty note,
+// if there is none
+func (ds *BoltDataSource) FetchDayNote(entryDate string) (*model.DayNote, error) {
+	var note model.DayNote
+	err := ds.DB.Select(q.And(q.Eq("Date", entryDate), q.Eq("ProfileID", ds.profileID))).First(&note)
+	if err == storm.ErrNotFound {
+		return &model.DayNote{Date: entryDate}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch note for %s, %v", entryDate, err)
+	}
+	return &note, nil
+}
+
+// FetchDayNotes fetches the notes of all days
+func (ds *BoltDataSource) FetchDayNotes() ([]model.DayNote, error) {
+	var notes []model.DayNote
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&notes)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, fmt.Errorf("could not fetch notes, %v", err)
+	}
+	return notes, nil
+}
+
+// AddWater adds the given amount of water for the given date
+func (ds *BoltDataSource) AddWater(entryDate string, amount float64) error {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return err
+	}
+	if config.UnitSystem == util.Imperial {
+		amount = util.ToMl(amount)
+	}
+	water := model.Water{
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		EntryDate: entryDate,
+		Amount:    amount,
+	}
+	err = ds.DB.Save(&water)
+	if err != nil {
+		return fmt.Errorf("could not add water: %v", err)
+	}
+	return nil
+}
+
+// FetchWater fetches the water drunk on the given date and the water target of the
+// config effective on this date
+func (ds *BoltDataSource) FetchWater(entryDate string) (*model.DayWater, error) {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	config, err := ds.FetchConfigForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	var water []model.Water
+	err = ds.DB.Select(q.And(q.Eq("EntryDate", entryDate), q.Eq("ProfileID", ds.profileID))).Find(&water)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, fmt.Errorf("could not fetch water for %s, %v", entryDate, err)
+	}
+	return dayWater(water, config), nil
+}
+
+// FetchAllWater fetches all water entries
+func (ds *BoltDataSource) FetchAllWater() ([]model.Water, error) {
+	var water []model.Water
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&water)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, fmt.Errorf("could not fetch water, %v", err)
+	}
+	return water, nil
+}
+
+// AddMeasurement adds the given body measurements for todays date
+func (ds *BoltDataSource) AddMeasurement(measurement *model.Measurement) error {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return err
+	}
+	m := metricMeasurement(measurement, config.UnitSystem)
+	m.ProfileID = ds.profileID
+	m.Created = util.Now(ds.Clock).UTC()
+	err = ds.DB.Save(&m)
+	if err != nil {
+		return fmt.Errorf("could not add measurement: %v", err)
+	}
+	return nil
+}
+
+// FetchMeasurements fetches all measurements ordered by date
+func (ds *BoltDataSource) FetchMeasurements() ([]model.Measurement, error) {
+	var measurements []model.Measurement
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&measurements)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, fmt.Errorf("could not fetch measurements, %v", err)
+	}
+	sort.SliceStable(measurements, func(i, j int) bool {
+		return measurements[i].Created.Before(measurements[j].Created)
+	})
+	return measurements, nil
+}
+
+// FetchBodyFatForDate fetches the body fat percentage on the given date, which is the last
+// body fat measured on or before that date. If there is no such measurement, 0 is returned
+func (ds *BoltDataSource) FetchBodyFatForDate(date time.Time) (float64, error) {
+	measurements, err := ds.FetchMeasurements()
+	if err != nil {
+		return 0, err
+	}
+	config, _ := ds.FetchConfigForDate(date)
+	return bodyFatForDate(measurements, date, config), nil
+}
+
+// StartFast starts a fast at the given time, if there is no running fast
+func (ds *BoltDataSource) StartFast(start time.Time) error {
+	fasts, err := ds.FetchFasts()
+	if err != nil {
+		return err
+	}
+	for _, f := range fasts {
+		if f.IsRunning() {
+			return fmt.Errorf("there is already a fast running since %s", f.Start.Format(util.DateFormat))
+		}
+	}
+	fast := model.Fast{
+		ProfileID: ds.profileID,
+		Start:     start.UTC(),
+	}
+	err = ds.DB.Save(&fast)
+	if err != nil {
+		return fmt.Errorf("could not start fast: %v", err)
+	}
+	return nil
+}
+
+// StopFast stops the running fast at the given time and returns it
+func (ds *BoltDataSource) StopFast(end time.Time) (*model.Fast, error) {
+	fasts, err := ds.FetchFasts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range fasts {
+		if !fasts[i].IsRunning() {
+			continue
+		}
+		fast := fasts[i]
+		fast.End = end.UTC()
+		err = ds.DB.Save(&fast)
+		if err != nil {
+			return nil, fmt.Errorf("could not stop fast: %v", err)
+		}
+		return &fast, nil
+	}
+	return nil, fmt.Errorf("there is no running fast")
+}
+
+// FetchFasts fetches all fasts ordered by their start
+func (ds *BoltDataSource) FetchFasts() ([]model.Fast, error) {
+	var fasts []model.Fast
+	err := ds.DB.Select(q.Eq("ProfileID", ds.profileID)).Find(&fasts)
+	if err != nil && err != storm.ErrNotFound {
+		return nil, fmt.Errorf("could not fetch fasts, %v", err)
+	}
+	sort.SliceStable(fasts, func(i, j int) bool {
+		return fasts[i].Start.Before(fasts[j].Start)
+	})
+	return fasts, nil
+}
+
 // Import imports the given data to the database, overwriting the previous
 // data
 func (ds *BoltDataSource) Import(data *model.ImpEx) error {
+	tx, err := ds.DB.Begin(true)
+	if err != nil {
+		return fmt.Errorf("could not start import, %v", err)
+	}
+	defer tx.Rollback()
+	if err = ds.importData(tx, data); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// importData replaces the data of the selected profile with the given data
+func (ds *BoltDataSource) importData(tx storm.Node, data *model.ImpEx) error {
 	var zeroID int
-	err := ds.SetConfigFromImport(data.Config)
+	err := ds.removeConfigs(tx)
+	if err == nil {
+		err = ds.addConfigFromImport(tx, data.Config)
+	}
 	if err != nil {
 		return fmt.Errorf("could not replace config, %v", err)
 	}
-	err = ds.DB.Drop(&model.Weight{})
-	if err != nil {
+	if len(data.ConfigHistory) > 0 {
+		err = ds.removeConfigs(tx)
+		if err != nil {
+			return fmt.Errorf("could not replace config history, %v", err)
+		}
+		for i := range data.ConfigHistory {
+			err = ds.addConfigFromImport(tx, &data.ConfigHistory[i])
+			if err != nil {
+				return fmt.Errorf("could not replace config history, %v", err)
+			}
+		}
+	}
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Weight))
+	if err != nil && err != storm.ErrNotFound {
 		return fmt.Errorf("could not remove weights, %v", err)
 	}
 	for _, weight := range data.Weights {
 		weight.ID = zeroID
-		err = ds.DB.Save(&weight)
+		weight.ProfileID = ds.profileID
+		err = tx.Save(&weight)
 		if err != nil {
 			return fmt.Errorf("could not insert/update weight with id %d", weight.ID)
 		}
 	}
-	err = ds.DB.Drop(&model.Entry{})
-	if err != nil {
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Entry))
+	if err != nil && err != storm.ErrNotFound {
 		return fmt.Errorf("could not remove entries, %v", err)
 	}
 	for _, entry := range data.Entries {
 		entry.ID = zeroID
-		err = ds.DB.Save(&entry)
+		entry.ProfileID = ds.profileID
+		err = tx.Save(&entry)
 		if err != nil {
 			return fmt.Errorf("could not insert/update entry with id %d", entry.ID)
 		}
 	}
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.DayNote))
+	if err != nil && err != storm.ErrNotFound {
+		return fmt.Errorf("could not remove notes, %v", err)
+	}
+	for _, note := range data.DayNotes {
+		note.ID = zeroID
+		note.ProfileID = ds.profileID
+		err = tx.Save(&note)
+		if err != nil {
+			return fmt.Errorf("could not insert/update note for %s", note.Date)
+		}
+	}
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Water))
+	if err != nil && err != storm.ErrNotFound {
+		return fmt.Errorf("could not remove water, %v", err)
+	}
+	for _, water := range data.Water {
+		water.ID = zeroID
+		water.ProfileID = ds.profileID
+		err = tx.Save(&water)
+		if err != nil {
+			return fmt.Errorf("could not insert/update water for %s", water.EntryDate)
+		}
+	}
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Measurement))
+	if err != nil && err != storm.ErrNotFound {
+		return fmt.Errorf("could not remove measurements, %v", err)
+	}
+	for _, measurement := range data.Measurements {
+		measurement.ID = zeroID
+		measurement.ProfileID = ds.profileID
+		err = tx.Save(&measurement)
+		if err != nil {
+			return fmt.Errorf("could not insert/update measurement with id %d", measurement.ID)
+		}
+	}
+	err = tx.Select(q.Eq("ProfileID", ds.profileID)).Delete(new(model.Fast))
+	if err != nil && err != storm.ErrNotFound {
+		return fmt.Errorf("could not remove fasts, %v", err)
+	}
+	for _, fast := range data.Fasts {
+		fast.ID = zeroID
+		fast.ProfileID = ds.profileID
+		err = tx.Save(&fast)
+		if err != nil {
+			return fmt.Errorf("could not insert/update fast with id %d", fast.ID)
+		}
+	}
 	return nil
 }
 
@@ -233,10 +789,42 @@ func (ds *BoltDataSource) Export() (*model.ImpEx, error) {
 	if err != nil {
 		return nil, err
 	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil {
+		return nil, err
+	}
+	dayNotes, err := ds.FetchDayNotes()
+	if err != nil {
+		return nil, err
+	}
+	water, err := ds.FetchAllWater()
+	if err != nil {
+		return nil, err
+	}
+	measurements, err := ds.FetchMeasurements()
+	if err != nil {
+		return nil, err
+	}
+	fasts, err := ds.FetchFasts()
+	if err != nil {
+		return nil, err
+	}
 	impex := &model.ImpEx{
-		Config:  config,
-		Entries: entries,
-		Weights: weights,
+		Config:        config,
+		ConfigHistory: configs,
+		Entries:       entries,
+		Weights:       weights,
+		DayNotes:      dayNotes,
+		Water:         water,
+		Measurements:  measurements,
+		Fasts:         fasts,
 	}
 	return impex, nil
 }
+
+// Backup writes a consistent copy of the database to the given path
+func (ds *BoltDataSource) Backup(path string) error {
+	return ds.DB.Bolt.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(path, 0600)
+	})
+}
@@ -0,0 +1,147 @@
+package datasource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asdine/storm"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestBoltMigrateProfiles(t *testing.T) {
+	testCases := []struct {
+		description string
+		profiles    []model.Profile
+		expected    string
+	}{
+		{
+			description: "database without profiles",
+			expected:    DefaultProfile,
+		},
+		{
+			description: "interrupted migration",
+			profiles:    []model.Profile{{Name: DefaultProfile, Active: true}},
+			expected:    DefaultProfile,
+		},
+		{
+			description: "database without default profile",
+			profiles:    []model.Profile{{Name: "anna", Active: true}, {Name: "bob"}},
+			expected:    "anna",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "calories")
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "calories.db")
+			db, err := storm.Open(path)
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			for i := range tc.profiles {
+				db.Save(&tc.profiles[i])
+			}
+			birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+			db.Save(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+			db.Save(&model.Weight{Weight: 80, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)})
+			db.Save(&model.Entry{EntryDate: "05.01.2017", Calories: 100, Food: "Apple"})
+			db.Close()
+			ds := &BoltDataSource{Clock: util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}}
+			close, err := ds.Setup(path)
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			defer close()
+			if err = ds.SelectProfile(tc.expected); err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			config, err := ds.FetchConfig()
+			weights, _ := ds.FetchWeights()
+			entries, _ := ds.FetchAllEntries()
+			if err != nil || config.Height != 180 || len(weights) != 1 || len(entries) != 1 {
+				t.Errorf("Error, actual: %v, %v, %v, %v expected: %v", config, err, weights, entries, "the migrated config, weight and entry")
+				return
+			}
+			profiles, _ := ds.FetchProfiles()
+			expected := len(tc.profiles)
+			if expected == 0 {
+				expected = 1
+			}
+			if len(profiles) != expected {
+				t.Errorf("Error, actual: %v expected: %v", len(profiles), expected)
+				return
+			}
+		})
+	}
+}
+
+func TestBoltImportIsAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer os.RemoveAll(dir)
+	ds := &BoltDataSource{Clock: util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}}
+	close, err := ds.Setup(filepath.Join(dir, "calories.db"))
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer close()
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	config := model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric}
+	err = ds.SetConfig(&config)
+	if err == nil {
+		err = ds.AddWeight(80)
+	}
+	if err == nil {
+		err = ds.AddEntry("05.01.2017", 100, "Apple", nil)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	imported := config
+	imported.Height = 190
+	invalid := config
+	invalid.UnitSystem = "nautical"
+	err = ds.Import(&model.ImpEx{Config: &imported, ConfigHistory: []model.Config{imported, invalid}})
+	expected := "could not replace config history, unit system needs to be either metric or imperial: nautical"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) != 1 || configs[0].Height != 180 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "the config before the import")
+		return
+	}
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 1 || entries[0].Food != "Apple" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "the entries before the import")
+		return
+	}
+	config.Height = 185
+	if err = ds.SetConfig(&config); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	configs, err = ds.FetchConfigHistory()
+	if err != nil || len(configs) != 1 || configs[0].Height != 185 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "the replaced config")
+		return
+	}
+}
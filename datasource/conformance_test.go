@@ -0,0 +1,85 @@
+package datasource_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/datasource/datasourcetest"
+	"github.com/zupzup/calories/util"
+)
+
+// fileDataSource returns a factory for data sources, which store their data in a temporary file
+func fileDataSource(newDataSource func(clock util.Clock) datasource.DataSource, file string) datasourcetest.Factory {
+	return func(t *testing.T, clock util.Clock) (datasource.DataSource, func()) {
+		dir, err := ioutil.TempDir("", "calories")
+		if err != nil {
+			t.Fatalf("could not create temporary directory, %v", err)
+		}
+		ds := newDataSource(clock)
+		close, err := ds.Setup(filepath.Join(dir, file))
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("could not set up data source, %v", err)
+		}
+		return ds, func() {
+			close()
+			os.RemoveAll(dir)
+		}
+	}
+}
+
+func TestBoltConformance(t *testing.T) {
+	datasourcetest.Run(t, fileDataSource(func(clock util.Clock) datasource.DataSource {
+		return &datasource.BoltDataSource{Clock: clock}
+	}, "calories.db"))
+}
+
+func TestEncryptedBoltConformance(t *testing.T) {
+	datasourcetest.Run(t, func(t *testing.T, clock util.Clock) (datasource.DataSource, func()) {
+		dir, err := ioutil.TempDir("", "calories")
+		if err != nil {
+			t.Fatalf("could not create temporary directory, %v", err)
+		}
+		path := filepath.Join(dir, "calories.db")
+		ds := &datasource.BoltDataSource{}
+		close, err := ds.Setup(path)
+		if err == nil {
+			close()
+			err = datasource.EncryptBolt(path, "secret")
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("could not encrypt data source, %v", err)
+		}
+		ds = &datasource.BoltDataSource{Clock: clock, Passphrase: func() (string, error) { return "secret", nil }}
+		close, err = ds.Setup(path)
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatalf("could not set up data source, %v", err)
+		}
+		return ds, func() {
+			close()
+			os.RemoveAll(dir)
+		}
+	})
+}
+
+func TestSQLiteConformance(t *testing.T) {
+	datasourcetest.Run(t, fileDataSource(func(clock util.Clock) datasource.DataSource {
+		return &datasource.SQLiteDataSource{Clock: clock}
+	}, "calories.sqlite"))
+}
+
+func TestMemoryConformance(t *testing.T) {
+	datasourcetest.Run(t, func(t *testing.T, clock util.Clock) (datasource.DataSource, func()) {
+		ds := &datasource.MemoryDataSource{Clock: clock}
+		close, err := ds.Setup(datasource.MemoryConnection)
+		if err != nil {
+			t.Fatalf("could not set up data source, %v", err)
+		}
+		return ds, func() { close() }
+	})
+}
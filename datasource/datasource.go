@@ -2,22 +2,78 @@ package datasource
 
 import (
 	"github.com/zupzup/calories/model"
+	"path/filepath"
+	"strings"
+	"time"
 )
 
 // DataSource is the interface to the data layer
 type DataSource interface {
 	Setup(connection string) (func() error, error)
+	AddProfile(name string) error
+	FetchProfiles() ([]model.Profile, error)
+	CurrentProfile() (*model.Profile, error)
+	SelectProfile(name string) error
+	UseProfile(name string) error
 	SetConfig(*model.Config) error
 	SetConfigFromImport(*model.Config) error
 	FetchConfig() (*model.Config, error)
+	FetchConfigForDate(date time.Time) (*model.Config, error)
+	FetchConfigHistory() ([]model.Config, error)
 	AddWeight(weight float64) error
 	CurrentWeight() (*model.Weight, error)
+	FetchWeightForDate(date time.Time) (*model.Weight, error)
 	FetchWeights() ([]model.Weight, error)
-	AddEntry(entryDate string, calories int, food string) error
+	AddEntry(entryDate string, calories int, food string, tags []string) error
+	UpdateEntry(entry *model.Entry) error
 	FetchEntries(entryDate string) (model.Entries, error)
 	FetchAllEntries() (model.Entries, error)
+	SearchEntries(query, tag string, from, to time.Time) (model.Entries, error)
 	RemoveEntries(entryDate string) error
 	RemoveEntry(entryDate string, id int) error
+	SetDayNote(note *model.DayNote) error
+	FetchDayNote(entryDate string) (*model.DayNote, error)
+	FetchDayNotes() ([]model.DayNote, error)
+	AddWater(entryDate string, amount float64) error
+	FetchWater(entryDate string) (*model.DayWater, error)
+	FetchAllWater() ([]model.Water, error)
+	AddMeasurement(measurement *model.Measurement) error
+	FetchMeasurements() ([]model.Measurement, error)
+	FetchBodyFatForDate(date time.Time) (float64, error)
+	StartFast(start time.Time) error
+	StopFast(end time.Time) (*model.Fast, error)
+	FetchFasts() ([]model.Fast, error)
 	Import(data *model.ImpEx) error
 	Export() (*model.ImpEx, error)
+	Backup(path string) error
+}
+
+// New returns the DataSource for the given connection string and the connection to pass to Setup
+// Connections starting with SQLitePrefix use SQLite, MemoryConnection uses an in-memory
+// database and all others use Bolt
+func New(connection string) (DataSource, string) {
+	connection = strings.TrimSpace(connection)
+	if connection == MemoryConnection {
+		return &MemoryDataSource{}, connection
+	}
+	if strings.HasPrefix(connection, SQLitePrefix) {
+		return &SQLiteDataSource{}, strings.TrimPrefix(connection, SQLitePrefix)
+	}
+	return &BoltDataSource{}, connection
+}
+
+// AbsConnection returns the given connection string with an absolute path to the database file
+func AbsConnection(connection string) (string, error) {
+	ds, path := New(connection)
+	if _, ok := ds.(*MemoryDataSource); ok {
+		return path, nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := ds.(*SQLiteDataSource); ok {
+		return SQLitePrefix + abs, nil
+	}
+	return abs, nil
 }
@@ -0,0 +1,31 @@
+package datasource
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAbsConnection(t *testing.T) {
+	wd, _ := os.Getwd()
+	var tests = []struct {
+		description string
+		connection  string
+		expected    string
+	}{
+		{description: "memory", connection: MemoryConnection, expected: MemoryConnection},
+		{description: "bolt absolute", connection: "/tmp/calories.db", expected: "/tmp/calories.db"},
+		{description: "bolt relative", connection: "calories.db", expected: filepath.Join(wd, "calories.db")},
+		{description: "sqlite relative", connection: "sqlite://calories.sqlite", expected: SQLitePrefix + filepath.Join(wd, "calories.sqlite")},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res, err := AbsConnection(tc.connection)
+			if err != nil || res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
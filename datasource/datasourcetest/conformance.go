@@ -0,0 +1,791 @@
+// Package datasourcetest provides a conformance suite, which checks that an implementation
+// of datasource.DataSource behaves like the BoltDataSource
+package datasourcetest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// Now is the current time of the clock, which is passed to the factory
+var Now = time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)
+
+// Factory creates a new, empty and set up data source, which uses the given clock for timestamping
+// The returned function closes the data source and removes its data
+type Factory func(t *testing.T, clock util.Clock) (datasource.DataSource, func())
+
+// Run runs the conformance suite against fresh data sources created with the given factory
+func Run(t *testing.T, newDataSource Factory) {
+	var tests = []struct {
+		description string
+		test        func(t *testing.T, ds datasource.DataSource, newDataSource Factory)
+	}{
+		{description: "default profile", test: testDefaultProfile},
+		{description: "profiles", test: testProfiles},
+		{description: "missing config", test: testMissingConfig},
+		{description: "invalid config", test: testInvalidConfig},
+		{description: "config versions", test: testConfigVersions},
+		{description: "current config in timezone", test: testCurrentConfigInTimezone},
+		{description: "imperial config", test: testImperialConfig},
+		{description: "weights", test: testWeights},
+		{description: "weights in timezone", test: testWeightsInTimezone},
+		{description: "entries on missing date", test: testEntriesOnMissingDate},
+		{description: "entries", test: testEntries},
+		{description: "remove entries", test: testRemoveEntries},
+		{description: "search entries", test: testSearchEntries},
+		{description: "day notes", test: testDayNotes},
+		{description: "water", test: testWater},
+		{description: "measurements", test: testMeasurements},
+		{description: "fasts", test: testFasts},
+		{description: "import replaces data", test: testImportReplacesData},
+		{description: "export and import", test: testExportImport},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			ds, teardown := newDataSource(t, util.FixedClock{Time: Now})
+			defer teardown()
+			tc.test(t, ds, newDataSource)
+		})
+	}
+}
+
+// setup sets a config and adds a weight of 80 kg
+func setup(t *testing.T, ds datasource.DataSource) bool {
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	err := ds.SetConfig(&model.Config{
+		Height:     180,
+		Activity:   1.2,
+		Birthday:   birthday,
+		Gender:     "male",
+		UnitSystem: util.Metric,
+		Timezone:   "UTC",
+	})
+	if err == nil {
+		err = ds.AddWeight(80)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return false
+	}
+	return true
+}
+
+// date parses the given date in the util.DateFormat
+func date(value string) time.Time {
+	d, _ := time.Parse(util.DateFormat, value)
+	return d
+}
+
+func testDefaultProfile(t *testing.T, ds datasource.DataSource, _ Factory) {
+	profiles, err := ds.FetchProfiles()
+	if err != nil || len(profiles) != 1 || profiles[0].Name != datasource.DefaultProfile || !profiles[0].Active {
+		t.Errorf("Error, actual: %v expected: %v", profiles, "the active default profile")
+		return
+	}
+	profile, err := ds.CurrentProfile()
+	if err != nil || profile.Name != datasource.DefaultProfile || profile.ID == 0 {
+		t.Errorf("Error, actual: %v expected: %v", profile, datasource.DefaultProfile)
+		return
+	}
+}
+
+func testProfiles(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	if err := ds.AddProfile("anna"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected := "profile anna already exists"
+	if err := ds.AddProfile("anna"); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	if err := ds.SelectProfile("bob"); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	if err := ds.SelectProfile("anna"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if _, err := ds.FetchConfig(); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	profile, err := ds.CurrentProfile()
+	if err != nil || profile.Name != datasource.DefaultProfile {
+		t.Errorf("Error, actual: %v expected: %v", profile, datasource.DefaultProfile)
+		return
+	}
+	if !setup(t, ds) {
+		return
+	}
+	if err = ds.AddEntry("05.01.2017", 100, "Apple", nil); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = ds.UseProfile(datasource.DefaultProfile); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "no entries")
+		return
+	}
+	if err = ds.UseProfile("anna"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	profile, err = ds.CurrentProfile()
+	if err != nil || profile.Name != "anna" {
+		t.Errorf("Error, actual: %v expected: %v", profile, "anna")
+		return
+	}
+	entries, err = ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 1 || entries[0].ProfileID != profile.ID {
+		t.Errorf("Error, actual: %v expected: %v", entries, "one entry of anna")
+		return
+	}
+	profiles, err := ds.FetchProfiles()
+	if err != nil || len(profiles) != 2 || profiles[0].Active || !profiles[1].Active {
+		t.Errorf("Error, actual: %v expected: %v", profiles, "anna as the only active profile")
+		return
+	}
+}
+
+func testMissingConfig(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if _, err := ds.FetchConfig(); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	if _, err := ds.FetchConfigForDate(Now); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "no configs")
+		return
+	}
+	if err = ds.AddWeight(80); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+}
+
+func testInvalidConfig(t *testing.T, ds datasource.DataSource, _ Factory) {
+	expected := "unit system needs to be either metric or imperial: lightyears"
+	err := ds.SetConfig(&model.Config{UnitSystem: "lightyears"})
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	expected = "budget mode needs to be either daily or weekly: monthly"
+	err = ds.SetConfig(&model.Config{UnitSystem: util.Metric, Budget: "monthly"})
+	if err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "no configs")
+		return
+	}
+}
+
+func testConfigVersions(t *testing.T, ds datasource.DataSource, _ Factory) {
+	versions := []model.Config{
+		{Effective: date("04.01.2017"), Height: 185, Activity: 1.2, UnitSystem: util.Metric},
+		{Effective: date("01.01.2017"), Height: 180, Activity: 1.2, UnitSystem: util.Metric},
+		{Effective: date("04.01.2017"), Height: 185, Activity: 1.5, UnitSystem: util.Metric, Budget: util.WeeklyBudget},
+	}
+	for i := range versions {
+		if err := ds.SetConfig(&versions[i]); err != nil {
+			t.Errorf("Error, actual: %v expected: %v", err, nil)
+			return
+		}
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) != 2 || configs[0].Height != 180 || configs[1].Activity != 1.5 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "two versions sorted by their effective date")
+		return
+	}
+	var tests = []struct {
+		date     time.Time
+		expected float64
+	}{
+		{date: date("01.12.2016"), expected: 180},
+		{date: date("03.01.2017"), expected: 180},
+		{date: date("04.01.2017"), expected: 185},
+		{date: date("01.02.2017"), expected: 185},
+	}
+	for _, tc := range tests {
+		config, fetchErr := ds.FetchConfigForDate(tc.date)
+		if fetchErr != nil || config.Height != tc.expected {
+			t.Errorf("Error, actual: %v expected: %v", config, tc.expected)
+			return
+		}
+	}
+	config, err := ds.FetchConfig()
+	if err != nil || config.Budget != util.WeeklyBudget || config.ProfileID == 0 {
+		t.Errorf("Error, actual: %v expected: %v", config, util.WeeklyBudget)
+		return
+	}
+}
+
+func testCurrentConfigInTimezone(t *testing.T, _ datasource.DataSource, newDataSource Factory) {
+	var tests = []struct {
+		now         time.Time
+		timezone    string
+		dayRollover int
+		expected    float64
+	}{
+		{now: time.Date(2017, 1, 5, 23, 30, 0, 0, time.UTC), timezone: "UTC", expected: 180},
+		{now: time.Date(2017, 1, 5, 23, 30, 0, 0, time.UTC), timezone: "Europe/Vienna", expected: 185},
+		{now: time.Date(2017, 1, 6, 1, 30, 0, 0, time.UTC), timezone: "UTC", expected: 185},
+		{now: time.Date(2017, 1, 6, 1, 30, 0, 0, time.UTC), timezone: "UTC", dayRollover: 4, expected: 180},
+	}
+	for _, tc := range tests {
+		ds, teardown := newDataSource(t, util.FixedClock{Time: tc.now})
+		for _, version := range []model.Config{
+			{Effective: date("01.01.2017"), Height: 180, Activity: 1.2, UnitSystem: util.Metric, Timezone: tc.timezone, DayRollover: tc.dayRollover},
+			{Effective: date("06.01.2017"), Height: 185, Activity: 1.2, UnitSystem: util.Metric, Timezone: tc.timezone, DayRollover: tc.dayRollover},
+		} {
+			if err := ds.SetConfig(&version); err != nil {
+				teardown()
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+		}
+		config, err := ds.FetchConfig()
+		teardown()
+		if err != nil || config.Height != tc.expected {
+			t.Errorf("Error, actual: %v expected: %v", config, tc.expected)
+			return
+		}
+	}
+}
+
+func testImperialConfig(t *testing.T, ds datasource.DataSource, _ Factory) {
+	err := ds.SetConfig(&model.Config{Height: 72, Activity: 1.2, UnitSystem: util.Imperial, Timezone: "UTC", WaterTarget: 64})
+	if err == nil {
+		err = ds.AddWeight(176)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	config, err := ds.FetchConfig()
+	if err != nil || config.Height != util.ToCm(72) || config.WaterTarget != util.ToMl(64) || !config.Effective.Equal(date("05.01.2017")) {
+		t.Errorf("Error, actual: %v expected: %v", config, "a metric config effective from today")
+		return
+	}
+	weight, err := ds.CurrentWeight()
+	if err != nil || weight.Weight != util.ToKg(176) {
+		t.Errorf("Error, actual: %v expected: %v", weight, util.ToKg(176))
+		return
+	}
+}
+
+func testWeights(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if _, err := ds.CurrentWeight(); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	if !setup(t, ds) {
+		return
+	}
+	if err := ds.AddWeight(82); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	weight, err := ds.CurrentWeight()
+	if err != nil || weight.Weight != 82 || !weight.Created.Equal(Now) {
+		t.Errorf("Error, actual: %v expected: %v", weight, 82)
+		return
+	}
+	weights, err := ds.FetchWeights()
+	if err != nil || len(weights) != 2 || weights[0].Weight != 80 || weights[0].ID == weights[1].ID {
+		t.Errorf("Error, actual: %v expected: %v", weights, "two weights with different ids")
+		return
+	}
+	weight, err = ds.FetchWeightForDate(date("01.01.2017"))
+	if err != nil || weight.Weight != 80 {
+		t.Errorf("Error, actual: %v expected: %v", weight, 80)
+		return
+	}
+	weight, err = ds.FetchWeightForDate(Now)
+	if err != nil || weight.Weight != 82 {
+		t.Errorf("Error, actual: %v expected: %v", weight, 82)
+		return
+	}
+}
+
+func testWeightsInTimezone(t *testing.T, ds datasource.DataSource, _ Factory) {
+	config := model.Config{Effective: date("01.01.2017"), Height: 180, Activity: 1.2, UnitSystem: util.Metric, Timezone: "America/Los_Angeles"}
+	evening := time.Date(2017, 1, 5, 3, 0, 0, 0, time.UTC)
+	err := ds.Import(&model.ImpEx{
+		Config:       &config,
+		Weights:      []model.Weight{{Created: date("01.01.2017"), Weight: 80}, {Created: evening, Weight: 79}},
+		Measurements: []model.Measurement{{Created: date("01.01.2017"), BodyFat: 20}, {Created: evening, BodyFat: 19}},
+	})
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	weight, err := ds.FetchWeightForDate(date("04.01.2017"))
+	if err != nil || weight.Weight != 79 {
+		t.Errorf("Error, actual: %v expected: %v", weight, 79)
+		return
+	}
+	bodyFat, err := ds.FetchBodyFatForDate(date("04.01.2017"))
+	if err != nil || bodyFat != 19 {
+		t.Errorf("Error, actual: %v expected: %v", bodyFat, 19)
+		return
+	}
+	weight, err = ds.FetchWeightForDate(date("03.01.2017"))
+	if err != nil || weight.Weight != 80 {
+		t.Errorf("Error, actual: %v expected: %v", weight, 80)
+		return
+	}
+}
+
+func testEntriesOnMissingDate(t *testing.T, ds datasource.DataSource, _ Factory) {
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "no entries")
+		return
+	}
+	entries, err = ds.FetchAllEntries()
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "no entries")
+		return
+	}
+	if err = ds.AddEntry("05.01.2017", 100, "Apple", nil); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+}
+
+func testEntries(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	err := ds.AddEntry("05.01.2017", 500, "Pizza", []string{"cheat", "restaurant"})
+	if err == nil {
+		err = ds.AddEntry("05.01.2017", 300, "Apple", nil)
+	}
+	if err == nil {
+		err = ds.AddEntry("06.01.2017", 200, "Pasta", nil)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = ds.AddEntry("5.1.17", 200, "Pasta", nil); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 2 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "two entries")
+		return
+	}
+	pizza, apple := entries[0], entries[1]
+	if pizza.Food != "Pizza" || pizza.Calories != 500 || pizza.ID == 0 || pizza.ID == apple.ID || !pizza.Created.Equal(Now) {
+		t.Errorf("Error, actual: %v expected: %v", pizza, "Pizza with 500 calories")
+		return
+	}
+	if len(pizza.Tags) != 2 || pizza.Tags[1] != "restaurant" || len(apple.Tags) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", pizza.Tags, "cheat and restaurant")
+		return
+	}
+	bmr, amr := util.CalculateMetabolicRates(date("05.01.2017"), date("01.01.1990"), 180, 80, 1.2, "male")
+	if pizza.BMR != bmr || pizza.AMR != amr {
+		t.Errorf("Error, actual: %v expected: %v", pizza.BMR, bmr)
+		return
+	}
+	apple.Note = "tasty"
+	apple.Tags = []string{"fruit"}
+	if err = ds.UpdateEntry(&apple); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = ds.UpdateEntry(&model.Entry{Food: "Ghost"}); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	entries, err = ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 2 || entries[1].Note != "tasty" || len(entries[1].Tags) != 1 || entries[1].ID != apple.ID {
+		t.Errorf("Error, actual: %v expected: %v", entries, "the updated apple")
+		return
+	}
+	entries, err = ds.FetchAllEntries()
+	if err != nil || len(entries) != 3 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "three entries")
+		return
+	}
+}
+
+func testRemoveEntries(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	err := ds.AddEntry("05.01.2017", 500, "Pizza", nil)
+	if err == nil {
+		err = ds.AddEntry("05.01.2017", 300, "Apple", nil)
+	}
+	if err == nil {
+		err = ds.AddEntry("06.01.2017", 200, "Pasta", nil)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected := "could not delete entries for 07.01.2017"
+	if err = ds.RemoveEntries("07.01.2017"); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	entries, _ := ds.FetchEntries("05.01.2017")
+	if err = ds.RemoveEntry("05.01.2017", entries[0].ID); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected = fmt.Sprintf("could not delete entry with id %d on day 05.01.2017", entries[0].ID)
+	if err = ds.RemoveEntry("05.01.2017", entries[0].ID); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	entries, _ = ds.FetchEntries("05.01.2017")
+	if len(entries) != 1 || entries[0].Food != "Apple" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "Apple")
+		return
+	}
+	if err = ds.RemoveEntries("05.01.2017"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, _ = ds.FetchAllEntries()
+	if len(entries) != 1 || entries[0].Food != "Pasta" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "Pasta")
+		return
+	}
+}
+
+func testSearchEntries(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	err := ds.AddEntry("06.01.2017", 200, "Apple", nil)
+	if err == nil {
+		err = ds.AddEntry("05.01.2017", 500, "Pizza", []string{"cheat"})
+	}
+	if err == nil {
+		err = ds.SetDayNote(&model.DayNote{Date: "06.01.2017", Tags: []string{"fruitday"}})
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	var tests = []struct {
+		description string
+		query       string
+		tag         string
+		from        time.Time
+		to          time.Time
+		expected    []string
+	}{
+		{description: "everything", expected: []string{"Pizza", "Apple"}},
+		{description: "query", query: "izz", expected: []string{"Pizza"}},
+		{description: "entry tag", tag: "cheat", expected: []string{"Pizza"}},
+		{description: "day tag", tag: "fruitday", expected: []string{"Apple"}},
+		{description: "from", from: date("06.01.2017"), expected: []string{"Apple"}},
+		{description: "to", to: date("05.01.2017"), expected: []string{"Pizza"}},
+		{description: "nothing", query: "Pasta", expected: []string{}},
+	}
+	for _, tc := range tests {
+		entries, searchErr := ds.SearchEntries(tc.query, tc.tag, tc.from, tc.to)
+		if searchErr != nil || len(entries) != len(tc.expected) {
+			t.Errorf("Error, %s actual: %v expected: %v", tc.description, entries, tc.expected)
+			return
+		}
+		for i := range entries {
+			if entries[i].Food != tc.expected[i] {
+				t.Errorf("Error, %s actual: %v expected: %v", tc.description, entries, tc.expected)
+				return
+			}
+		}
+	}
+}
+
+func testDayNotes(t *testing.T, ds datasource.DataSource, _ Factory) {
+	note, err := ds.FetchDayNote("05.01.2017")
+	if err != nil || note.ID != 0 || note.Date != "05.01.2017" || !note.IsEmpty() {
+		t.Errorf("Error, actual: %v expected: %v", note, "an empty note")
+		return
+	}
+	err = ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Tags: []string{"cheatday"}, Note: "party"})
+	if err == nil {
+		err = ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Note: "big party"})
+	}
+	if err == nil {
+		err = ds.SetDayNote(&model.DayNote{Date: "06.01.2017", Note: "hangover"})
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	note, err = ds.FetchDayNote("05.01.2017")
+	if err != nil || note.ID == 0 || note.Note != "big party" || len(note.Tags) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", note, "big party")
+		return
+	}
+	if err = ds.SetDayNote(&model.DayNote{Date: "05.01.2017"}); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = ds.SetDayNote(&model.DayNote{Date: "07.01.2017"}); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	notes, err := ds.FetchDayNotes()
+	if err != nil || len(notes) != 1 || notes[0].Note != "hangover" {
+		t.Errorf("Error, actual: %v expected: %v", notes, "hangover")
+		return
+	}
+}
+
+func testWater(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	water, err := ds.FetchWater("05.01.2017")
+	if err != nil || water.Amount != 0 || water.Target != util.DefaultWaterTarget || water.UnitSystem != util.Metric {
+		t.Errorf("Error, actual: %v expected: %v", water, "no water")
+		return
+	}
+	err = ds.AddWater("05.01.2017", 250)
+	if err == nil {
+		err = ds.AddWater("05.01.2017", 500)
+	}
+	if err == nil {
+		err = ds.AddWater("06.01.2017", 100)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	water, err = ds.FetchWater("05.01.2017")
+	if err != nil || water.Amount != 750 {
+		t.Errorf("Error, actual: %v expected: %v", water, 750)
+		return
+	}
+	all, err := ds.FetchAllWater()
+	if err != nil || len(all) != 3 || all[2].EntryDate != "06.01.2017" || !all[0].Created.Equal(Now) {
+		t.Errorf("Error, actual: %v expected: %v", all, "three water entries")
+		return
+	}
+}
+
+func testMeasurements(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	bodyFat, err := ds.FetchBodyFatForDate(Now)
+	if err != nil || bodyFat != 0 {
+		t.Errorf("Error, actual: %v expected: %v", bodyFat, 0)
+		return
+	}
+	err = ds.AddMeasurement(&model.Measurement{Waist: 80, BodyFat: 20})
+	if err == nil {
+		err = ds.AddMeasurement(&model.Measurement{Hip: 95})
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	measurements, err := ds.FetchMeasurements()
+	if err != nil || len(measurements) != 2 || measurements[0].Waist != 80 || measurements[1].Hip != 95 || !measurements[0].Created.Equal(Now) {
+		t.Errorf("Error, actual: %v expected: %v", measurements, "two measurements")
+		return
+	}
+	bodyFat, err = ds.FetchBodyFatForDate(Now.AddDate(0, 0, 1))
+	if err != nil || bodyFat != 20 {
+		t.Errorf("Error, actual: %v expected: %v", bodyFat, 20)
+		return
+	}
+	bodyFat, err = ds.FetchBodyFatForDate(date("01.01.2017"))
+	if err != nil || bodyFat != 0 {
+		t.Errorf("Error, actual: %v expected: %v", bodyFat, 0)
+		return
+	}
+}
+
+func testFasts(t *testing.T, ds datasource.DataSource, _ Factory) {
+	expected := "there is no running fast"
+	if _, err := ds.StopFast(Now); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	if err := ds.StartFast(Now); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected = "there is already a fast running since 05.01.2017"
+	if err := ds.StartFast(Now); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	fasts, err := ds.FetchFasts()
+	if err != nil || len(fasts) != 1 || !fasts[0].IsRunning() || !fasts[0].Start.Equal(Now) {
+		t.Errorf("Error, actual: %v expected: %v", fasts, "a running fast")
+		return
+	}
+	fast, err := ds.StopFast(Now.Add(16 * time.Hour))
+	if err != nil || fast.IsRunning() || fast.Hours(Now) != 16 {
+		t.Errorf("Error, actual: %v expected: %v", fast, "a fast of 16 hours")
+		return
+	}
+	fasts, err = ds.FetchFasts()
+	if err != nil || len(fasts) != 1 || fasts[0].IsRunning() || fasts[0].ID != fast.ID {
+		t.Errorf("Error, actual: %v expected: %v", fasts, "a stopped fast")
+		return
+	}
+}
+
+func testImportReplacesData(t *testing.T, ds datasource.DataSource, _ Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	err := ds.AddEntry("05.01.2017", 500, "Pizza", nil)
+	if err == nil {
+		err = ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Note: "party"})
+	}
+	if err == nil {
+		err = ds.StartFast(Now)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	data := &model.ImpEx{
+		Config: &model.Config{ID: 42, Effective: date("01.01.2017"), Height: 170, Activity: 1.5, UnitSystem: util.Metric},
+		ConfigHistory: []model.Config{
+			{ID: 42, Effective: date("01.01.2017"), Height: 170, Activity: 1.5, UnitSystem: util.Metric},
+			{ID: 43, Effective: date("03.01.2017"), Height: 171, Activity: 1.5, UnitSystem: util.Metric},
+		},
+		Weights:      []model.Weight{{ID: 99, Created: date("01.01.2017"), Weight: 70}},
+		Entries:      model.Entries{{ID: 99, Created: date("01.01.2017"), EntryDate: "01.01.2017", Calories: 100, Food: "Imported", BMR: 1500, AMR: 1800, Tags: []string{"old"}}},
+		DayNotes:     []model.DayNote{{ID: 99, Date: "01.01.2017", Note: "imported"}},
+		Water:        []model.Water{{ID: 99, Created: date("01.01.2017"), EntryDate: "01.01.2017", Amount: 300}},
+		Measurements: []model.Measurement{{ID: 99, Created: date("01.01.2017"), BodyFat: 25}},
+	}
+	if err = ds.Import(data); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, err := ds.FetchAllEntries()
+	if err != nil || len(entries) != 1 || entries[0].Food != "Imported" || entries[0].BMR != 1500 || entries[0].ID == 0 || entries[0].Tags[0] != "old" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "the imported entry")
+		return
+	}
+	profile, _ := ds.CurrentProfile()
+	if entries[0].ProfileID != profile.ID {
+		t.Errorf("Error, actual: %v expected: %v", entries[0].ProfileID, profile.ID)
+		return
+	}
+	weight, err := ds.CurrentWeight()
+	if err != nil || weight.Weight != 70 {
+		t.Errorf("Error, actual: %v expected: %v", weight, 70)
+		return
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) != 2 || configs[1].Height != 171 {
+		t.Errorf("Error, actual: %v expected: %v", configs, "the imported config history")
+		return
+	}
+	notes, err := ds.FetchDayNotes()
+	if err != nil || len(notes) != 1 || notes[0].Note != "imported" {
+		t.Errorf("Error, actual: %v expected: %v", notes, "the imported note")
+		return
+	}
+	water, err := ds.FetchWater("01.01.2017")
+	if err != nil || water.Amount != 300 {
+		t.Errorf("Error, actual: %v expected: %v", water, 300)
+		return
+	}
+	bodyFat, err := ds.FetchBodyFatForDate(Now)
+	if err != nil || bodyFat != 25 {
+		t.Errorf("Error, actual: %v expected: %v", bodyFat, 25)
+		return
+	}
+	fasts, err := ds.FetchFasts()
+	if err != nil || len(fasts) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", fasts, "no fasts")
+		return
+	}
+}
+
+func testExportImport(t *testing.T, ds datasource.DataSource, newDataSource Factory) {
+	if !setup(t, ds) {
+		return
+	}
+	err := ds.AddEntry("05.01.2017", 500, "Pizza", []string{"cheat"})
+	if err == nil {
+		err = ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Note: "party"})
+	}
+	if err == nil {
+		err = ds.AddWater("05.01.2017", 250)
+	}
+	if err == nil {
+		err = ds.AddMeasurement(&model.Measurement{Waist: 80})
+	}
+	if err == nil {
+		err = ds.StartFast(Now)
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	data, err := ds.Export()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if data.Config == nil || len(data.ConfigHistory) != 1 || len(data.Entries) != 1 || len(data.Weights) != 1 ||
+		len(data.DayNotes) != 1 || len(data.Water) != 1 || len(data.Measurements) != 1 || len(data.Fasts) != 1 {
+		t.Errorf("Error, actual: %v expected: %v", data, "all data")
+		return
+	}
+	other, teardown := newDataSource(t, util.FixedClock{Time: Now})
+	defer teardown()
+	if !setup(t, other) {
+		return
+	}
+	if err = other.Import(data); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	exported, err := other.Export()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if len(exported.Weights) != 1 || len(exported.Entries) != 1 || exported.Entries[0].Food != "Pizza" ||
+		exported.Entries[0].Tags[0] != "cheat" || !exported.Entries[0].Created.Equal(Now) ||
+		exported.DayNotes[0].Note != "party" || exported.Water[0].Amount != 250 ||
+		exported.Measurements[0].Waist != 80 || !exported.Fasts[0].IsRunning() ||
+		!exported.Config.Effective.Equal(data.Config.Effective) || !exported.Config.Birthday.Equal(data.Config.Birthday) {
+		t.Errorf("Error, actual: %v expected: %v", exported, data)
+		return
+	}
+}
@@ -0,0 +1,298 @@
+package datasource
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/asdine/storm/codec"
+	stormjson "github.com/asdine/storm/codec/json"
+	"github.com/boltdb/bolt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// encryptionBucket holds the salt and the passphrase check of encrypted Bolt databases
+const encryptionBucket = "__calories_encryption"
+
+// stormMetadataBucket is the bucket, in which storm saves the codec used for a bucket
+const stormMetadataBucket = "__storm_metadata"
+
+// encryptionCheck is encrypted with the key to check the passphrase when opening the database
+const encryptionCheck = "calories"
+
+// encryptedCodec encodes values as JSON and encrypts them with AES-GCM
+type encryptedCodec struct {
+	aead cipher.AEAD
+}
+
+// Marshal encodes the given value as JSON and encrypts it
+func (c *encryptedCodec) Marshal(v interface{}) ([]byte, error) {
+	plain, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return c.seal(plain)
+}
+
+// Unmarshal decrypts the given value and decodes it from JSON
+func (c *encryptedCodec) Unmarshal(b []byte, v interface{}) error {
+	plain, err := c.open(b)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, v)
+}
+
+// Name returns the name of the codec, which storm saves for every bucket
+func (c *encryptedCodec) Name() string {
+	return "aes-gcm-json"
+}
+
+// seal encrypts the given bytes, prefixing them with a random nonce
+func (c *encryptedCodec) seal(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// open decrypts the given bytes, which are prefixed with their nonce
+func (c *encryptedCodec) open(b []byte) ([]byte, error) {
+	size := c.aead.NonceSize()
+	if len(b) < size {
+		return nil, fmt.Errorf("could not decrypt value, it is too short")
+	}
+	return c.aead.Open(nil, b[:size], b[size:], nil)
+}
+
+// newEncryptedCodec derives a key from the given passphrase and salt and creates the codec
+func newEncryptedCodec(passphrase string, salt []byte) (*encryptedCodec, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedCodec{aead: aead}, nil
+}
+
+// openBolt opens the Bolt database at the given path with the same options as storm
+func openBolt(path string) (*bolt.DB, error) {
+	return bolt.Open(path, 0600, &bolt.Options{Timeout: 1 * time.Second})
+}
+
+// IsEncrypted checks, whether the Bolt database at the given path is encrypted
+func IsEncrypted(path string) (bool, error) {
+	db, err := openBolt(path)
+	if err != nil {
+		return false, fmt.Errorf("error while connecting to database at %s, %v", path, err)
+	}
+	defer db.Close()
+	var encrypted bool
+	err = db.View(func(tx *bolt.Tx) error {
+		encrypted = tx.Bucket([]byte(encryptionBucket)) != nil
+		return nil
+	})
+	return encrypted, err
+}
+
+// codecForPassphrase returns the codec of the database, asking for the passphrase
+// using the given function, if it is encrypted
+func codecForPassphrase(db *bolt.DB, passphrase func() (string, error)) (codec.MarshalUnmarshaler, error) {
+	var salt, check []byte
+	db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(encryptionBucket))
+		if b != nil {
+			salt = append(salt, b.Get([]byte("salt"))...)
+			check = append(check, b.Get([]byte("check"))...)
+		}
+		return nil
+	})
+	if salt == nil {
+		return stormjson.Codec, nil
+	}
+	if passphrase == nil {
+		return nil, fmt.Errorf("the database is encrypted, but no passphrase was given")
+	}
+	pass, err := passphrase()
+	if err != nil {
+		return nil, err
+	}
+	return verifiedCodec(pass, salt, check)
+}
+
+// verifiedCodec creates the codec for the given passphrase and checks, that it is the right one
+func verifiedCodec(passphrase string, salt, check []byte) (*encryptedCodec, error) {
+	c, err := newEncryptedCodec(passphrase, salt)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key from passphrase, %v", err)
+	}
+	plain, err := c.open(check)
+	if err != nil || string(plain) != encryptionCheck {
+		return nil, fmt.Errorf("wrong passphrase for the encrypted database")
+	}
+	return c, nil
+}
+
+// EncryptBolt encrypts all values stored in the Bolt database at the given path with a key
+// derived from the given passphrase
+// The keys of storm's indexes are not encrypted, because storm looks them up by their plain value,
+// so the names of the profiles, which are indexed as unique, stay readable in the database file
+func EncryptBolt(path, passphrase string) error {
+	if passphrase == "" {
+		return fmt.Errorf("the passphrase must not be empty")
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("could not create salt, %v", err)
+	}
+	c, err := newEncryptedCodec(passphrase, salt)
+	if err != nil {
+		return fmt.Errorf("could not derive key from passphrase, %v", err)
+	}
+	check, err := c.seal([]byte(encryptionCheck))
+	if err != nil {
+		return fmt.Errorf("could not encrypt database, %v", err)
+	}
+	return rewriteBolt(path, func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(encryptionBucket)) != nil {
+			return fmt.Errorf("the database at %s is already encrypted", path)
+		}
+		err := convertBuckets(tx, stormjson.Codec.Name(), c.Name(), c.seal)
+		if err != nil {
+			return fmt.Errorf("could not encrypt database, %v", err)
+		}
+		b, err := tx.CreateBucket([]byte(encryptionBucket))
+		if err == nil {
+			err = b.Put([]byte("salt"), salt)
+		}
+		if err == nil {
+			err = b.Put([]byte("check"), check)
+		}
+		return err
+	})
+}
+
+// DecryptBolt decrypts all values stored in the encrypted Bolt database at the given path
+func DecryptBolt(path, passphrase string) error {
+	return rewriteBolt(path, func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(encryptionBucket))
+		if b == nil {
+			return fmt.Errorf("the database at %s is not encrypted", path)
+		}
+		c, err := verifiedCodec(passphrase, b.Get([]byte("salt")), b.Get([]byte("check")))
+		if err != nil {
+			return err
+		}
+		err = convertBuckets(tx, c.Name(), stormjson.Codec.Name(), c.open)
+		if err != nil {
+			return fmt.Errorf("could not decrypt database, %v", err)
+		}
+		return tx.DeleteBucket([]byte(encryptionBucket))
+	})
+}
+
+// convertBuckets converts all values of the storm buckets, which use the codec with the given name,
+// using the given function and sets the codec of the buckets to the new codec
+// Nested buckets like storm's indexes and metadata are left as they are
+func convertBuckets(tx *bolt.Tx, from, to string, convert func([]byte) ([]byte, error)) error {
+	return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		meta := b.Bucket([]byte(stormMetadataBucket))
+		if meta == nil {
+			return nil
+		}
+		if !bytes.Equal(meta.Get([]byte("codec")), []byte(from)) {
+			return fmt.Errorf("bucket %s uses the codec %s instead of %s", name, meta.Get([]byte("codec")), from)
+		}
+		values := map[string][]byte{}
+		err := b.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+			converted, convErr := convert(v)
+			if convErr != nil {
+				return fmt.Errorf("could not convert value of bucket %s, %v", name, convErr)
+			}
+			values[string(k)] = converted
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for k, v := range values {
+			if err = b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return meta.Put([]byte("codec"), []byte(to))
+	})
+}
+
+// rewriteBolt runs the given update on the Bolt database at the given path and rewrites it into
+// a new file, so no old values remain in the free pages of the file
+func rewriteBolt(path string, update func(tx *bolt.Tx) error) error {
+	db, err := openBolt(path)
+	if err != nil {
+		return fmt.Errorf("error while connecting to database at %s, %v", path, err)
+	}
+	err = db.Update(update)
+	if err == nil {
+		err = copyBolt(db, path+".tmp")
+	}
+	db.Close()
+	if err != nil {
+		os.Remove(path + ".tmp")
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+// copyBolt copies all buckets of the given database into a new database at the given path
+func copyBolt(db *bolt.DB, path string) error {
+	os.Remove(path)
+	dst, err := openBolt(path)
+	if err != nil {
+		return fmt.Errorf("could not create database at %s, %v", path, err)
+	}
+	defer dst.Close()
+	return db.View(func(tx *bolt.Tx) error {
+		return dst.Update(func(dstTx *bolt.Tx) error {
+			return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+				dstBucket, err := dstTx.CreateBucket(name)
+				if err != nil {
+					return err
+				}
+				return copyBucket(b, dstBucket)
+			})
+		})
+	})
+}
+
+// copyBucket copies all values and nested buckets of the given bucket
+func copyBucket(src, dst *bolt.Bucket) error {
+	if err := dst.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+	return src.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return dst.Put(k, v)
+		}
+		nested, err := dst.CreateBucket(k)
+		if err != nil {
+			return err
+		}
+		return copyBucket(src.Bucket(k), nested)
+	})
+}
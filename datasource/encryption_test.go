@@ -0,0 +1,137 @@
+package datasource
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func passphrase(value string) func() (string, error) {
+	return func() (string, error) {
+		return value, nil
+	}
+}
+
+func TestEncryptAndDecryptBolt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calories.db")
+	ds := &BoltDataSource{}
+	close, err := ds.Setup(path)
+	if err == nil {
+		err = ds.AddProfile("Secret Agent")
+	}
+	close()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = EncryptBolt(path, "secret"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	expected := "the database at " + path + " is already encrypted"
+	if err = EncryptBolt(path, "secret"); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	content, _ := ioutil.ReadFile(path)
+	if bytes.Contains(content, []byte("\"name\":\"Secret Agent\"")) {
+		t.Errorf("Error, actual: %v expected: %v", "the plain profile", "only encrypted values")
+		return
+	}
+	expected = "the database is encrypted, but no passphrase was given"
+	if _, err = (&BoltDataSource{}).Setup(path); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	expected = "wrong passphrase for the encrypted database"
+	if _, err = (&BoltDataSource{Passphrase: passphrase("wrong")}).Setup(path); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	ds = &BoltDataSource{Passphrase: passphrase("secret")}
+	close, err = ds.Setup(path)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	err = ds.AddProfile("anna")
+	profiles, _ := ds.FetchProfiles()
+	close()
+	if err != nil || len(profiles) != 3 || profiles[1].Name != "Secret Agent" || profiles[2].ID != 3 {
+		t.Errorf("Error, actual: %v expected: %v", profiles, "three profiles")
+		return
+	}
+	if err = DecryptBolt(path, "wrong"); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	if err = DecryptBolt(path, "secret"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	encrypted, err := IsEncrypted(path)
+	if err != nil || encrypted {
+		t.Errorf("Error, actual: %v expected: %v", encrypted, false)
+		return
+	}
+	ds = &BoltDataSource{}
+	close, err = ds.Setup(path)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer close()
+	profiles, _ = ds.FetchProfiles()
+	if len(profiles) != 3 || profiles[2].Name != "anna" {
+		t.Errorf("Error, actual: %v expected: %v", profiles, "three profiles")
+		return
+	}
+}
+
+func TestEncryptBoltKeepsProfileIndex(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "calories.db")
+	ds := &BoltDataSource{}
+	close, err := ds.Setup(path)
+	if err == nil {
+		err = ds.AddProfile("Secret Agent")
+	}
+	close()
+	if err == nil {
+		err = EncryptBolt(path, "secret")
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	content, _ := ioutil.ReadFile(path)
+	if !bytes.Contains(content, []byte("Secret Agent")) {
+		t.Errorf("Error, actual: %v expected: %v", "no plain profile name", "the plain profile name in the index")
+		return
+	}
+	ds = &BoltDataSource{Passphrase: passphrase("secret")}
+	close, err = ds.Setup(path)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer close()
+	expected := "profile Secret Agent already exists"
+	if err = ds.AddProfile("Secret Agent"); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
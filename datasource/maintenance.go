@@ -0,0 +1,261 @@
+package datasource
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/zupzup/calories/model"
+)
+
+// databaseFile returns the data source and the path of the existing database file of the given connection
+func databaseFile(connection string) (DataSource, string, error) {
+	ds, path := New(connection)
+	if _, ok := ds.(*MemoryDataSource); ok {
+		return nil, "", fmt.Errorf("in-memory databases have no database file")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, "", fmt.Errorf("there is no database at %s, %v", path, err)
+	}
+	return ds, path, nil
+}
+
+// fileSize returns the size of the file at the given path
+func fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Compact rewrites the database of the given connection without its unused space
+func Compact(connection string) (*model.Compaction, error) {
+	ds, path, err := databaseFile(connection)
+	if err != nil {
+		return nil, err
+	}
+	before, err := fileSize(path)
+	if err != nil {
+		return nil, err
+	}
+	switch ds.(type) {
+	case *BoltDataSource:
+		err = rewriteBolt(path, func(tx *bolt.Tx) error {
+			return nil
+		})
+	case *SQLiteDataSource:
+		err = withSQLite(path, func(db *sql.DB) error {
+			_, execErr := db.Exec("VACUUM")
+			return execErr
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not compact database at %s, %v", path, err)
+	}
+	after, err := fileSize(path)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Compaction{Path: path, Before: before, After: after}, nil
+}
+
+// Check runs an integrity check on the database of the given connection
+func Check(connection string) (*model.IntegrityCheck, error) {
+	ds, path, err := databaseFile(connection)
+	if err != nil {
+		return nil, err
+	}
+	check := &model.IntegrityCheck{Path: path, Problems: []string{}}
+	switch ds.(type) {
+	case *BoltDataSource:
+		err = withBolt(path, func(db *bolt.DB) error {
+			return db.View(func(tx *bolt.Tx) error {
+				for problem := range tx.Check() {
+					check.Problems = append(check.Problems, problem.Error())
+				}
+				return nil
+			})
+		})
+	case *SQLiteDataSource:
+		err = withSQLite(path, func(db *sql.DB) error {
+			rows, queryErr := db.Query("PRAGMA integrity_check")
+			if queryErr != nil {
+				return queryErr
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var problem string
+				if scanErr := rows.Scan(&problem); scanErr != nil {
+					return scanErr
+				}
+				if problem != "ok" {
+					check.Problems = append(check.Problems, problem)
+				}
+			}
+			return rows.Err()
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not check database at %s, %v", path, err)
+	}
+	return check, nil
+}
+
+// Stats returns the size of the database of the given connection and the number of
+// records of all buckets or tables
+func Stats(connection string) (*model.DBStats, error) {
+	ds, path, err := databaseFile(connection)
+	if err != nil {
+		return nil, err
+	}
+	size, err := fileSize(path)
+	if err != nil {
+		return nil, err
+	}
+	stats := &model.DBStats{Path: path, Size: size, Buckets: []model.BucketStats{}}
+	switch ds.(type) {
+	case *BoltDataSource:
+		err = withBolt(path, func(db *bolt.DB) error {
+			return db.View(func(tx *bolt.Tx) error {
+				return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+					bucket := model.BucketStats{Name: string(name)}
+					b.ForEach(func(k, v []byte) error {
+						if v != nil {
+							bucket.Records++
+						}
+						return nil
+					})
+					s := b.Stats()
+					bucket.Size = int64(s.BranchInuse + s.LeafInuse + s.InlineBucketInuse)
+					stats.Buckets = append(stats.Buckets, bucket)
+					return nil
+				})
+			})
+		})
+	case *SQLiteDataSource:
+		err = withSQLite(path, func(db *sql.DB) error {
+			tables, queryErr := sqliteTables(db)
+			if queryErr != nil {
+				return queryErr
+			}
+			for _, table := range tables {
+				bucket := model.BucketStats{Name: table}
+				if queryErr = db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %q", table)).Scan(&bucket.Records); queryErr != nil {
+					return queryErr
+				}
+				stats.Buckets = append(stats.Buckets, bucket)
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read statistics of database at %s, %v", path, err)
+	}
+	return stats, nil
+}
+
+// CopyDatabase copies the database of the given connection consistently to the given path,
+// checks the copy and returns the connection of the copy
+func CopyDatabase(connection, to string) (string, error) {
+	ds, path, err := databaseFile(connection)
+	if err != nil {
+		return "", err
+	}
+	to, err = filepath.Abs(to)
+	if err != nil {
+		return "", err
+	}
+	if _, statErr := os.Stat(to); statErr == nil {
+		return "", fmt.Errorf("there is already a file at %s", to)
+	}
+	newConnection := to
+	switch ds.(type) {
+	case *BoltDataSource:
+		err = withBolt(path, func(db *bolt.DB) error {
+			return db.View(func(tx *bolt.Tx) error {
+				return tx.CopyFile(to, 0600)
+			})
+		})
+	case *SQLiteDataSource:
+		newConnection = SQLitePrefix + to
+		err = withSQLite(path, func(db *sql.DB) error {
+			_, execErr := db.Exec("VACUUM INTO ?", to)
+			return execErr
+		})
+	}
+	if err != nil {
+		os.Remove(to)
+		return "", fmt.Errorf("could not copy database to %s, %v", to, err)
+	}
+	check, err := Check(newConnection)
+	if err == nil && len(check.Problems) > 0 {
+		err = fmt.Errorf("the copy has %d problems, %s", len(check.Problems), strings.Join(check.Problems, ", "))
+	}
+	if err != nil {
+		os.Remove(to)
+		return "", fmt.Errorf("could not verify the copy at %s, %v", to, err)
+	}
+	return newConnection, nil
+}
+
+// RemoveDatabase removes the database file of the given connection
+func RemoveDatabase(connection string) error {
+	_, path, err := databaseFile(connection)
+	if err != nil {
+		return err
+	}
+	if err = os.Remove(path); err != nil {
+		return fmt.Errorf("could not remove database at %s, %v", path, err)
+	}
+	os.Remove(path + "-wal")
+	os.Remove(path + "-shm")
+	return nil
+}
+
+// withBolt opens the Bolt database at the given path for the given function
+func withBolt(path string, fn func(db *bolt.DB) error) error {
+	db, err := openBolt(path)
+	if err != nil {
+		return err
+	}
+	err = fn(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// withSQLite opens the SQLite database at the given path for the given function
+func withSQLite(path string, fn func(db *sql.DB) error) error {
+	db, err := openSQLite(path)
+	if err != nil {
+		return err
+	}
+	err = fn(db)
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// sqliteTables returns the names of all tables of the given SQLite database
+func sqliteTables(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		if err = rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
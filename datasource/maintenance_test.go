@@ -0,0 +1,123 @@
+package datasource
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestMaintenance(t *testing.T) {
+	var tests = []struct {
+		description string
+		prefix      string
+		bucket      string
+	}{
+		{description: "bolt", prefix: "", bucket: "Entry"},
+		{description: "sqlite", prefix: SQLitePrefix, bucket: "entries"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "calories")
+			if err != nil {
+				t.Fatalf("could not create temporary directory, %v", err)
+			}
+			defer os.RemoveAll(dir)
+			connection := tc.prefix + filepath.Join(dir, "calories.db")
+			ds, path := New(connection)
+			close, err := ds.Setup(path)
+			if err != nil {
+				t.Fatalf("could not set up data source, %v", err)
+			}
+			birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+			err = ds.SetConfig(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+			if err == nil {
+				err = ds.AddWeight(80)
+			}
+			for i := 0; err == nil && i < 50; i++ {
+				err = ds.AddEntry("05.01.2017", 100, "Apple", nil)
+			}
+			if err == nil {
+				err = ds.RemoveEntries("05.01.2017")
+			}
+			if err == nil {
+				err = ds.AddEntry("06.01.2017", 200, "Pizza", nil)
+			}
+			close()
+			if err != nil {
+				t.Fatalf("could not add data, %v", err)
+			}
+			compaction, err := Compact(connection)
+			if err != nil || compaction.After > compaction.Before {
+				t.Errorf("Error, actual: %v %v expected: %v", compaction, err, "smaller file")
+				return
+			}
+			check, err := Check(connection)
+			if err != nil || len(check.Problems) != 0 {
+				t.Errorf("Error, actual: %v %v expected: %v", check, err, "no problems")
+				return
+			}
+			stats, err := Stats(connection)
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			records := -1
+			for _, bucket := range stats.Buckets {
+				if bucket.Name == tc.bucket {
+					records = bucket.Records
+				}
+			}
+			if records != 1 {
+				t.Errorf("Error, actual: %v expected: %v", stats.Buckets, 1)
+				return
+			}
+			moved := filepath.Join(dir, "moved.db")
+			newConnection, err := CopyDatabase(connection, moved)
+			if err != nil || newConnection != tc.prefix+moved {
+				t.Errorf("Error, actual: %v %v expected: %v", newConnection, err, tc.prefix+moved)
+				return
+			}
+			if _, err = CopyDatabase(connection, moved); err == nil || err.Error() != fmt.Sprintf("there is already a file at %s", moved) {
+				t.Errorf("Error, actual: %v expected: %v", err, "file exists")
+				return
+			}
+			if err = RemoveDatabase(connection); err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			if _, err = Check(connection); err == nil {
+				t.Errorf("Error, actual: %v expected: %v", err, "missing database")
+				return
+			}
+			ds, path = New(newConnection)
+			close, err = ds.Setup(path)
+			if err != nil {
+				t.Fatalf("could not set up data source, %v", err)
+			}
+			defer close()
+			entries, err := ds.FetchEntries("06.01.2017")
+			if err != nil || len(entries) != 1 || entries[0].Food != "Pizza" {
+				t.Errorf("Error, actual: %v %v expected: %v", entries, err, "Pizza")
+				return
+			}
+		})
+	}
+}
+
+func TestMaintenanceMemory(t *testing.T) {
+	expected := "in-memory databases have no database file"
+	if _, err := Stats(MemoryConnection); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+	if _, err := Compact(MemoryConnection); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
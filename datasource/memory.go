@@ -0,0 +1,776 @@
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// MemoryConnection is the connection string for an ephemeral in-memory database
+const MemoryConnection = ":memory:"
+
+// MemoryDataSource is an in-memory implementation of the DataSource interface, which behaves
+// like the BoltDataSource, but loses all data, when the program exits
+// It is safe for concurrent use, e.g. as a fixture in tests
+// Clock is used for timestamping new weights and entries
+// All data is scoped by the selected profile
+type MemoryDataSource struct {
+	Clock        util.Clock
+	mu           sync.Mutex
+	profileID    int
+	ids          map[string]int
+	profiles     []model.Profile
+	configs      []model.Config
+	weights      []model.Weight
+	entries      []model.Entry
+	dayNotes     []model.DayNote
+	water        []model.Water
+	measurements []model.Measurement
+	fasts        []model.Fast
+}
+
+// Setup resets the database and creates and selects the default profile
+// The connection is ignored
+func (ds *MemoryDataSource) Setup(connection string) (func() error, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.ids = map[string]int{}
+	ds.profiles = []model.Profile{{ID: ds.nextID("profile"), Name: DefaultProfile, Active: true}}
+	ds.configs = nil
+	ds.weights = nil
+	ds.entries = nil
+	ds.dayNotes = nil
+	ds.water = nil
+	ds.measurements = nil
+	ds.fasts = nil
+	ds.profileID = ds.profiles[0].ID
+	return func() error { return nil }, nil
+}
+
+// nextID returns the next incrementing id for the given kind of data
+func (ds *MemoryDataSource) nextID(kind string) int {
+	if ds.ids == nil {
+		ds.ids = map[string]int{}
+	}
+	ds.ids[kind]++
+	return ds.ids[kind]
+}
+
+// AddProfile adds a new profile with the given name
+func (ds *MemoryDataSource) AddProfile(name string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if ds.profileIndex(name) != -1 {
+		return fmt.Errorf("profile %s already exists", name)
+	}
+	ds.profiles = append(ds.profiles, model.Profile{ID: ds.nextID("profile"), Name: name})
+	return nil
+}
+
+// FetchProfiles fetches all profiles
+func (ds *MemoryDataSource) FetchProfiles() ([]model.Profile, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return append([]model.Profile(nil), ds.profiles...), nil
+}
+
+// CurrentProfile fetches and returns the active profile
+func (ds *MemoryDataSource) CurrentProfile() (*model.Profile, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for _, profile := range ds.profiles {
+		if profile.Active {
+			return &profile, nil
+		}
+	}
+	return nil, fmt.Errorf("could not fetch active profile: not found")
+}
+
+// profileIndex returns the index of the profile with the given name or -1, if there is none
+func (ds *MemoryDataSource) profileIndex(name string) int {
+	for i := range ds.profiles {
+		if ds.profiles[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectProfile selects the profile with the given name for all following operations,
+// without making it the active profile
+func (ds *MemoryDataSource) SelectProfile(name string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	i := ds.profileIndex(name)
+	if i == -1 {
+		return fmt.Errorf("could not find profile %s, not found", name)
+	}
+	ds.profileID = ds.profiles[i].ID
+	return nil
+}
+
+// UseProfile makes the profile with the given name the active profile
+func (ds *MemoryDataSource) UseProfile(name string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	i := ds.profileIndex(name)
+	if i == -1 {
+		return fmt.Errorf("could not find profile %s, not found", name)
+	}
+	for j := range ds.profiles {
+		ds.profiles[j].Active = j == i
+	}
+	ds.profileID = ds.profiles[i].ID
+	return nil
+}
+
+// SetConfig adds a new version of the config with the given values, which is effective
+// from the given effective date on, or from today, if no effective date is given
+// A version with the same effective date is replaced
+func (ds *MemoryDataSource) SetConfig(c *model.Config) error {
+	if err := validateConfig(c); err != nil {
+		return err
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config := newConfigVersion(c, ds.profileID, util.Now(ds.Clock))
+	configs := ds.configs[:0]
+	for _, existing := range ds.configs {
+		if existing.ProfileID != ds.profileID || !existing.Effective.Equal(config.Effective) {
+			configs = append(configs, existing)
+		}
+	}
+	config.ID = ds.nextID("config")
+	ds.configs = append(configs, config)
+	return nil
+}
+
+// SetConfigFromImport overrides the config history with the given values
+// by deleting all old configs and adding a new one
+func (ds *MemoryDataSource) SetConfigFromImport(c *model.Config) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if err := ds.removeConfigs(); err != nil {
+		return err
+	}
+	return ds.addConfigFromImport(c)
+}
+
+// addConfigFromImport adds the given config version without converting any units
+func (ds *MemoryDataSource) addConfigFromImport(c *model.Config) error {
+	if c.UnitSystem != "metric" && c.UnitSystem != "imperial" {
+		return fmt.Errorf("unit system needs to be either metric or imperial: %s", c.UnitSystem)
+	}
+	config := importedConfigVersion(c, ds.profileID)
+	config.ID = ds.nextID("config")
+	ds.configs = append(ds.configs, config)
+	return nil
+}
+
+// removeConfigs removes all configs of the selected profile
+func (ds *MemoryDataSource) removeConfigs() error {
+	configs := ds.configs[:0]
+	for _, config := range ds.configs {
+		if config.ProfileID != ds.profileID {
+			configs = append(configs, config)
+		}
+	}
+	ds.configs = configs
+	return nil
+}
+
+// FetchConfig fetches and returns the config, which is effective today in its timezone
+func (ds *MemoryDataSource) FetchConfig() (*model.Config, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.currentConfig()
+}
+
+// FetchConfigForDate fetches and returns the config, which is effective on the given date
+// If there is no config effective on that date, the oldest config is returned
+func (ds *MemoryDataSource) FetchConfigForDate(date time.Time) (*model.Config, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.configForDate(date)
+}
+
+// configForDate returns the config, which is effective on the given date
+func (ds *MemoryDataSource) configForDate(date time.Time) (*model.Config, error) {
+	configs := ds.configHistory()
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("could not retrieve config: not found")
+	}
+	return configForDate(configs, date), nil
+}
+
+// currentConfig returns the config, which is effective today in its timezone
+func (ds *MemoryDataSource) currentConfig() (*model.Config, error) {
+	configs := ds.configHistory()
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("could not retrieve config: not found")
+	}
+	return configForToday(configs, util.Now(ds.Clock)), nil
+}
+
+// FetchConfigHistory fetches all config versions, sorted by their effective date
+func (ds *MemoryDataSource) FetchConfigHistory() ([]model.Config, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.configHistory(), nil
+}
+
+// configHistory returns all config versions of the selected profile, sorted by their effective date
+func (ds *MemoryDataSource) configHistory() []model.Config {
+	var configs []model.Config
+	for _, config := range ds.configs {
+		if config.ProfileID == ds.profileID {
+			configs = append(configs, config)
+		}
+	}
+	sort.SliceStable(configs, func(i, j int) bool {
+		return configs[i].Effective.Before(configs[j].Effective)
+	})
+	return configs
+}
+
+// AddWeight adds the given weight for todays date
+func (ds *MemoryDataSource) AddWeight(weight float64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config, err := ds.currentConfig()
+	if err != nil {
+		return err
+	}
+	if config.UnitSystem == util.Imperial {
+		weight = util.ToKg(weight)
+	}
+	ds.weights = append(ds.weights, model.Weight{
+		ID:        ds.nextID("weight"),
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		Weight:    weight,
+	})
+	return nil
+}
+
+// CurrentWeight fetches and returns the current weight, which is the last added weight
+func (ds *MemoryDataSource) CurrentWeight() (*model.Weight, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	weights := ds.profileWeights()
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("could not fetch current weight: not found")
+	}
+	return &weights[len(weights)-1], nil
+}
+
+// FetchWeightForDate fetches and returns the weight on the given date, which is the last
+// weight added on or before that date. If there is no such weight, the oldest weight is returned
+func (ds *MemoryDataSource) FetchWeightForDate(date time.Time) (*model.Weight, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.weightForDate(date)
+}
+
+// weightForDate returns the weight on the given date
+func (ds *MemoryDataSource) weightForDate(date time.Time) (*model.Weight, error) {
+	weights := ds.profileWeights()
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("could not fetch weight for %s: not found", date.Format(util.DateFormat))
+	}
+	config, _ := ds.configForDate(date)
+	return weightForDate(weights, date, config), nil
+}
+
+// FetchWeights fetches all weight entries
+func (ds *MemoryDataSource) FetchWeights() ([]model.Weight, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.profileWeights(), nil
+}
+
+// profileWeights returns all weights of the selected profile
+func (ds *MemoryDataSource) profileWeights() []model.Weight {
+	var weights []model.Weight
+	for _, weight := range ds.weights {
+		if weight.ProfileID == ds.profileID {
+			weights = append(weights, weight)
+		}
+	}
+	return weights
+}
+
+// AddEntry fetches the config, weight and body fat effective on the entry date to calculate the metabolic
+// rate and adds the data into the entries
+func (ds *MemoryDataSource) AddEntry(entryDate string, calories int, food string, tags []string) error {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	weight, err := ds.weightForDate(date)
+	if err != nil {
+		return err
+	}
+	config, err := ds.configForDate(date)
+	if err != nil {
+		return err
+	}
+	bodyFat := bodyFatForDate(ds.profileMeasurements(), date, config)
+	bmr, amr := util.CalculateMetabolicRatesForFormula(config.Formula, date, config.Birthday, config.Height, weight.Weight, bodyFat, config.Activity, config.Gender)
+	ds.entries = append(ds.entries, model.Entry{
+		ID:        ds.nextID("entry"),
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		EntryDate: entryDate,
+		Calories:  calories,
+		Food:      food,
+		AMR:       amr,
+		BMR:       bmr,
+		Tags:      copyTags(tags),
+	})
+	return nil
+}
+
+// UpdateEntry saves the given, already existing entry
+func (ds *MemoryDataSource) UpdateEntry(entry *model.Entry) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	if entry.ID == 0 || entry.ProfileID != ds.profileID {
+		return fmt.Errorf("could not update entry with id %d, it does not exist", entry.ID)
+	}
+	updated := *entry
+	updated.Tags = copyTags(entry.Tags)
+	for i := range ds.entries {
+		if ds.entries[i].ID == entry.ID {
+			ds.entries[i] = updated
+			return nil
+		}
+	}
+	ds.entries = append(ds.entries, updated)
+	return nil
+}
+
+// FetchEntries fetches and returns all entries for a given date
+func (ds *MemoryDataSource) FetchEntries(entryDate string) (model.Entries, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	var entries model.Entries
+	for _, entry := range ds.entries {
+		if entry.ProfileID == ds.profileID && entry.EntryDate == entryDate {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// FetchAllEntries fetches and returns all entries
+func (ds *MemoryDataSource) FetchAllEntries() (model.Entries, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.profileEntries(), nil
+}
+
+// profileEntries returns all entries of the selected profile
+func (ds *MemoryDataSource) profileEntries() model.Entries {
+	var entries model.Entries
+	for _, entry := range ds.entries {
+		if entry.ProfileID == ds.profileID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// SearchEntries fetches all entries, whose food matches the given query and which are tagged
+// with the given tag, either directly or by their day, sorted by date
+// An empty query or tag is not used for filtering. A zero from-date or to-date means, that the
+// search is not limited in this direction
+func (ds *MemoryDataSource) SearchEntries(query, tag string, from, to time.Time) (model.Entries, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return searchEntries(ds.profileEntries(), ds.profileDayNotes(), query, tag, from, to)
+}
+
+// RemoveEntries removes all entries for a given day from the database
+func (ds *MemoryDataSource) RemoveEntries(entryDate string) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	removed := ds.removeEntries(func(entry *model.Entry) bool {
+		return entry.EntryDate == entryDate
+	})
+	if removed == 0 {
+		return fmt.Errorf("could not delete entries for %s", entryDate)
+	}
+	return nil
+}
+
+// RemoveEntry removes the entry with the given id for a given day from the database
+func (ds *MemoryDataSource) RemoveEntry(entryDate string, id int) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	removed := ds.removeEntries(func(entry *model.Entry) bool {
+		return entry.EntryDate == entryDate && entry.ID == id
+	})
+	if removed == 0 {
+		return fmt.Errorf("could not delete entry with id %d on day %s", id, entryDate)
+	}
+	return nil
+}
+
+// removeEntries removes all entries of the selected profile, which match the given function
+// and returns how many were removed
+func (ds *MemoryDataSource) removeEntries(match func(entry *model.Entry) bool) int {
+	entries := ds.entries[:0]
+	for i := range ds.entries {
+		if ds.entries[i].ProfileID != ds.profileID || !match(&ds.entries[i]) {
+			entries = append(entries, ds.entries[i])
+		}
+	}
+	removed := len(ds.entries) - len(entries)
+	ds.entries = entries
+	return removed
+}
+
+// SetDayNote sets the tags and the note of a day, replacing the previous ones
+// If there are neither tags nor a note, the note of the day is removed
+func (ds *MemoryDataSource) SetDayNote(note *model.DayNote) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for i := range ds.dayNotes {
+		if ds.dayNotes[i].ProfileID != ds.profileID || ds.dayNotes[i].Date != note.Date {
+			continue
+		}
+		if note.IsEmpty() {
+			ds.dayNotes = append(ds.dayNotes[:i], ds.dayNotes[i+1:]...)
+			return nil
+		}
+		ds.dayNotes[i].Tags = copyTags(note.Tags)
+		ds.dayNotes[i].Note = note.Note
+		return nil
+	}
+	if note.IsEmpty() {
+		return nil
+	}
+	ds.dayNotes = append(ds.dayNotes, model.DayNote{
+		ID:        ds.nextID("daynote"),
+		ProfileID: ds.profileID,
+		Date:      note.Date,
+		Tags:      copyTags(note.Tags),
+		Note:      note.Note,
+	})
+	return nil
+}
+
+// FetchDayNote fetches the note of the given day, returning an empty note,
+// if there is none
+func (ds *MemoryDataSource) FetchDayNote(entryDate string) (*model.DayNote, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for _, note := range ds.dayNotes {
+		if note.ProfileID == ds.profileID && note.Date == entryDate {
+			return &note, nil
+		}
+	}
+	return &model.DayNote{Date: entryDate}, nil
+}
+
+// FetchDayNotes fetches the notes of all days
+func (ds *MemoryDataSource) FetchDayNotes() ([]model.DayNote, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.profileDayNotes(), nil
+}
+
+// profileDayNotes returns all day notes of the selected profile
+func (ds *MemoryDataSource) profileDayNotes() []model.DayNote {
+	var notes []model.DayNote
+	for _, note := range ds.dayNotes {
+		if note.ProfileID == ds.profileID {
+			notes = append(notes, note)
+		}
+	}
+	return notes
+}
+
+// AddWater adds the given amount of water for the given date
+func (ds *MemoryDataSource) AddWater(entryDate string, amount float64) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config, err := ds.currentConfig()
+	if err != nil {
+		return err
+	}
+	if config.UnitSystem == util.Imperial {
+		amount = util.ToMl(amount)
+	}
+	ds.water = append(ds.water, model.Water{
+		ID:        ds.nextID("water"),
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		EntryDate: entryDate,
+		Amount:    amount,
+	})
+	return nil
+}
+
+// FetchWater fetches the water drunk on the given date and the water target of the
+// config effective on this date
+func (ds *MemoryDataSource) FetchWater(entryDate string) (*model.DayWater, error) {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config, err := ds.configForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	var water []model.Water
+	for _, w := range ds.water {
+		if w.ProfileID == ds.profileID && w.EntryDate == entryDate {
+			water = append(water, w)
+		}
+	}
+	return dayWater(water, config), nil
+}
+
+// FetchAllWater fetches all water entries
+func (ds *MemoryDataSource) FetchAllWater() ([]model.Water, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	var water []model.Water
+	for _, w := range ds.water {
+		if w.ProfileID == ds.profileID {
+			water = append(water, w)
+		}
+	}
+	return water, nil
+}
+
+// AddMeasurement adds the given body measurements for todays date
+func (ds *MemoryDataSource) AddMeasurement(measurement *model.Measurement) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config, err := ds.currentConfig()
+	if err != nil {
+		return err
+	}
+	m := metricMeasurement(measurement, config.UnitSystem)
+	m.ID = ds.nextID("measurement")
+	m.ProfileID = ds.profileID
+	m.Created = util.Now(ds.Clock).UTC()
+	ds.measurements = append(ds.measurements, m)
+	return nil
+}
+
+// FetchMeasurements fetches all measurements ordered by date
+func (ds *MemoryDataSource) FetchMeasurements() ([]model.Measurement, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.profileMeasurements(), nil
+}
+
+// profileMeasurements returns all measurements of the selected profile ordered by date
+func (ds *MemoryDataSource) profileMeasurements() []model.Measurement {
+	var measurements []model.Measurement
+	for _, m := range ds.measurements {
+		if m.ProfileID == ds.profileID {
+			measurements = append(measurements, m)
+		}
+	}
+	sort.SliceStable(measurements, func(i, j int) bool {
+		return measurements[i].Created.Before(measurements[j].Created)
+	})
+	return measurements
+}
+
+// FetchBodyFatForDate fetches the body fat percentage on the given date, which is the last
+// body fat measured on or before that date. If there is no such measurement, 0 is returned
+func (ds *MemoryDataSource) FetchBodyFatForDate(date time.Time) (float64, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	config, _ := ds.configForDate(date)
+	return bodyFatForDate(ds.profileMeasurements(), date, config), nil
+}
+
+// StartFast starts a fast at the given time, if there is no running fast
+func (ds *MemoryDataSource) StartFast(start time.Time) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for _, f := range ds.fasts {
+		if f.ProfileID == ds.profileID && f.IsRunning() {
+			return fmt.Errorf("there is already a fast running since %s", f.Start.Format(util.DateFormat))
+		}
+	}
+	ds.fasts = append(ds.fasts, model.Fast{
+		ID:        ds.nextID("fast"),
+		ProfileID: ds.profileID,
+		Start:     start.UTC(),
+	})
+	return nil
+}
+
+// StopFast stops the running fast at the given time and returns it
+func (ds *MemoryDataSource) StopFast(end time.Time) (*model.Fast, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	for i := range ds.fasts {
+		if ds.fasts[i].ProfileID != ds.profileID || !ds.fasts[i].IsRunning() {
+			continue
+		}
+		ds.fasts[i].End = end.UTC()
+		fast := ds.fasts[i]
+		return &fast, nil
+	}
+	return nil, fmt.Errorf("there is no running fast")
+}
+
+// FetchFasts fetches all fasts ordered by their start
+func (ds *MemoryDataSource) FetchFasts() ([]model.Fast, error) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	var fasts []model.Fast
+	for _, f := range ds.fasts {
+		if f.ProfileID == ds.profileID {
+			fasts = append(fasts, f)
+		}
+	}
+	sort.SliceStable(fasts, func(i, j int) bool {
+		return fasts[i].Start.Before(fasts[j].Start)
+	})
+	return fasts, nil
+}
+
+// Import imports the given data to the database, overwriting the previous
+// data and assigning new ids
+func (ds *MemoryDataSource) Import(data *model.ImpEx) error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	configs := []model.Config{*data.Config}
+	if len(data.ConfigHistory) > 0 {
+		configs = data.ConfigHistory
+	}
+	if err := ds.removeConfigs(); err != nil {
+		return fmt.Errorf("could not replace config, %v", err)
+	}
+	for i := range configs {
+		if err := ds.addConfigFromImport(&configs[i]); err != nil {
+			return fmt.Errorf("could not replace config history, %v", err)
+		}
+	}
+	weights := ds.weights[:0]
+	for _, weight := range ds.weights {
+		if weight.ProfileID != ds.profileID {
+			weights = append(weights, weight)
+		}
+	}
+	for _, weight := range data.Weights {
+		weight.ID = ds.nextID("weight")
+		weight.ProfileID = ds.profileID
+		weights = append(weights, weight)
+	}
+	ds.weights = weights
+	ds.removeEntries(func(entry *model.Entry) bool { return true })
+	for _, entry := range data.Entries {
+		entry.ID = ds.nextID("entry")
+		entry.ProfileID = ds.profileID
+		entry.Tags = copyTags(entry.Tags)
+		ds.entries = append(ds.entries, entry)
+	}
+	notes := ds.dayNotes[:0]
+	for _, note := range ds.dayNotes {
+		if note.ProfileID != ds.profileID {
+			notes = append(notes, note)
+		}
+	}
+	for _, note := range data.DayNotes {
+		note.ID = ds.nextID("daynote")
+		note.ProfileID = ds.profileID
+		note.Tags = copyTags(note.Tags)
+		notes = append(notes, note)
+	}
+	ds.dayNotes = notes
+	water := ds.water[:0]
+	for _, w := range ds.water {
+		if w.ProfileID != ds.profileID {
+			water = append(water, w)
+		}
+	}
+	for _, w := range data.Water {
+		w.ID = ds.nextID("water")
+		w.ProfileID = ds.profileID
+		water = append(water, w)
+	}
+	ds.water = water
+	measurements := ds.measurements[:0]
+	for _, m := range ds.measurements {
+		if m.ProfileID != ds.profileID {
+			measurements = append(measurements, m)
+		}
+	}
+	for _, m := range data.Measurements {
+		m.ID = ds.nextID("measurement")
+		m.ProfileID = ds.profileID
+		measurements = append(measurements, m)
+	}
+	ds.measurements = measurements
+	fasts := ds.fasts[:0]
+	for _, f := range ds.fasts {
+		if f.ProfileID != ds.profileID {
+			fasts = append(fasts, f)
+		}
+	}
+	for _, f := range data.Fasts {
+		f.ID = ds.nextID("fast")
+		f.ProfileID = ds.profileID
+		fasts = append(fasts, f)
+	}
+	ds.fasts = fasts
+	return nil
+}
+
+// Export creates a JSON representation of the database
+func (ds *MemoryDataSource) Export() (*model.ImpEx, error) {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return nil, err
+	}
+	entries, _ := ds.FetchAllEntries()
+	weights, _ := ds.FetchWeights()
+	configs, _ := ds.FetchConfigHistory()
+	dayNotes, _ := ds.FetchDayNotes()
+	water, _ := ds.FetchAllWater()
+	measurements, _ := ds.FetchMeasurements()
+	fasts, _ := ds.FetchFasts()
+	impex := &model.ImpEx{
+		Config:        config,
+		ConfigHistory: configs,
+		Entries:       entries,
+		Weights:       weights,
+		DayNotes:      dayNotes,
+		Water:         water,
+		Measurements:  measurements,
+		Fasts:         fasts,
+	}
+	return impex, nil
+}
+
+// Backup fails, because in-memory databases have no file to back up
+func (ds *MemoryDataSource) Backup(path string) error {
+	return fmt.Errorf("in-memory databases can't be backed up")
+}
+
+// copyTags copies the given tags, so the stored data can't be changed from the outside
+func copyTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	return append([]string(nil), tags...)
+}
@@ -0,0 +1,118 @@
+package datasource
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func setupMemory(t *testing.T) *MemoryDataSource {
+	ds := &MemoryDataSource{Clock: util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}}
+	if _, err := ds.Setup(MemoryConnection); err != nil {
+		t.Fatalf("Error, actual: %v expected: %v", err, nil)
+	}
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	err := ds.SetConfig(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		err = ds.AddWeight(80)
+	}
+	if err != nil {
+		t.Fatalf("Error, actual: %v expected: %v", err, nil)
+	}
+	return ds
+}
+
+func TestMemoryIDsAndProfiles(t *testing.T) {
+	ds := setupMemory(t)
+	ds.AddEntry("05.01.2017", 100, "Apple", nil)
+	ds.AddEntry("05.01.2017", 200, "Pizza", nil)
+	if err := ds.AddProfile("anna"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err := ds.AddProfile("anna"); err == nil || err.Error() != "profile anna already exists" {
+		t.Errorf("Error, actual: %v expected: %v", err, "profile anna already exists")
+		return
+	}
+	if err := ds.SelectProfile("anna"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, _ := ds.FetchEntries("05.01.2017")
+	if len(entries) != 0 {
+		t.Errorf("Error, actual: %v expected: %v", len(entries), 0)
+		return
+	}
+	ds.SelectProfile(DefaultProfile)
+	entries, _ = ds.FetchEntries("05.01.2017")
+	if len(entries) != 2 || entries[0].ID != 1 || entries[1].ID != 2 {
+		t.Errorf("Error, actual: %v expected: %v", entries, "entries with id 1 and 2")
+		return
+	}
+	if err := ds.RemoveEntry("05.01.2017", 1); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err := ds.RemoveEntry("05.01.2017", 1); err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+}
+
+func TestMemoryImportExport(t *testing.T) {
+	ds := setupMemory(t)
+	ds.AddEntry("05.01.2017", 100, "Apple", []string{"fruit"})
+	ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Note: "party"})
+	data, err := ds.Export()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	other := setupMemory(t)
+	other.AddEntry("06.01.2017", 300, "Pasta", nil)
+	if err = other.Import(data); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, _ := other.FetchAllEntries()
+	if len(entries) != 1 || entries[0].Food != "Apple" || entries[0].ID != 2 || entries[0].Tags[0] != "fruit" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "Apple with id 2")
+		return
+	}
+	note, _ := other.FetchDayNote("05.01.2017")
+	if note.Note != "party" {
+		t.Errorf("Error, actual: %v expected: %v", note.Note, "party")
+		return
+	}
+}
+
+func TestMemoryConcurrentEntries(t *testing.T) {
+	ds := setupMemory(t)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ds.AddEntry("05.01.2017", i, fmt.Sprintf("Food %d", i), nil)
+			ds.FetchEntries("05.01.2017")
+		}(i)
+	}
+	wg.Wait()
+	entries, _ := ds.FetchEntries("05.01.2017")
+	if len(entries) != 50 {
+		t.Errorf("Error, actual: %v expected: %v", len(entries), 50)
+		return
+	}
+	ids := map[int]bool{}
+	for _, entry := range entries {
+		ids[entry.ID] = true
+	}
+	if len(ids) != 50 {
+		t.Errorf("Error, actual: %v expected: %v", len(ids), 50)
+		return
+	}
+}
@@ -0,0 +1,224 @@
+package datasource
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// validateConfig checks the values of the given config, before a new version is added
+func validateConfig(c *model.Config) error {
+	if c.UnitSystem != "metric" && c.UnitSystem != "imperial" {
+		return fmt.Errorf("unit system needs to be either metric or imperial: %s", c.UnitSystem)
+	}
+	if c.DateFormat != "" && !util.IsValidDateFormat(c.DateFormat) {
+		return fmt.Errorf("date format needs to be either dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd: %s", c.DateFormat)
+	}
+	if _, err := util.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("unknown timezone: %s, %v", c.Timezone, err)
+	}
+	if c.DayRollover < 0 || c.DayRollover > 23 {
+		return fmt.Errorf("day rollover needs to be an hour from 0 to 23: %d", c.DayRollover)
+	}
+	if c.Budget != "" && c.Budget != util.DailyBudget && c.Budget != util.WeeklyBudget {
+		return fmt.Errorf("budget mode needs to be either daily or weekly: %s", c.Budget)
+	}
+	if !util.IsValidFormula(c.Formula) {
+		return fmt.Errorf("formula needs to be either harris-benedict or katch-mcardle: %s", c.Formula)
+	}
+	if c.EatingWindow != "" {
+		if _, _, err := util.ParseEatingWindow(c.EatingWindow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newConfigVersion creates the config version to store for the given config, which is effective from the
+// given effective date on, or from the current date at the given time, converting imperial units to metric
+func newConfigVersion(c *model.Config, profileID int, now time.Time) model.Config {
+	effective := c.Effective
+	if effective.IsZero() {
+		loc, _ := util.LoadLocation(c.Timezone)
+		effective = util.CurrentDate(now, loc, c.DayRollover)
+	}
+	height := c.Height
+	waterTarget := c.WaterTarget
+	if c.UnitSystem == util.Imperial {
+		height = util.ToCm(height)
+		waterTarget = util.ToMl(waterTarget)
+	}
+	config := importedConfigVersion(c, profileID)
+	config.Effective = util.TruncateToDate(effective)
+	config.Height = height
+	config.WaterTarget = waterTarget
+	return config
+}
+
+// importedConfigVersion creates the config version to store for the given config without converting any units
+func importedConfigVersion(c *model.Config, profileID int) model.Config {
+	return model.Config{
+		ProfileID:    profileID,
+		Effective:    util.TruncateToDate(c.Effective),
+		Height:       c.Height,
+		Activity:     c.Activity,
+		Birthday:     c.Birthday,
+		Gender:       c.Gender,
+		UnitSystem:   c.UnitSystem,
+		DateFormat:   util.NormalizeDateFormat(c.DateFormat),
+		Timezone:     c.Timezone,
+		DayRollover:  c.DayRollover,
+		Budget:       c.Budget,
+		WaterTarget:  c.WaterTarget,
+		Formula:      c.Formula,
+		EatingWindow: c.EatingWindow,
+	}
+}
+
+// configForDate returns the config of the given configs sorted by their effective date, which is
+// effective on the given date. If there is no config effective on that date, the oldest config is returned
+func configForDate(configs []model.Config, date time.Time) *model.Config {
+	day := util.TruncateToDate(date)
+	config := configs[0]
+	for _, c := range configs {
+		if c.Effective.After(day) {
+			break
+		}
+		config = c
+	}
+	return &config
+}
+
+// configForToday returns the config of the given configs sorted by their effective date, which is
+// effective today. Today is determined at the given point in time using the timezone and the day
+// rollover of the config, which is effective on the calendar date of that point in time
+func configForToday(configs []model.Config, now time.Time) *model.Config {
+	config := configForDate(configs, now)
+	loc, err := util.LoadLocation(config.Timezone)
+	if err != nil {
+		return config
+	}
+	return configForDate(configs, util.CurrentDate(now, loc, config.DayRollover))
+}
+
+// weightForDate returns the last of the given weights added on or before the given date
+// in the timezone of the given config. If there is no such weight, the oldest weight is returned
+// and if there are no weights at all, nil is returned. The given weights are not reordered
+func weightForDate(weights []model.Weight, date time.Time, config *model.Config) *model.Weight {
+	if len(weights) == 0 {
+		return nil
+	}
+	sorted := make([]model.Weight, len(weights))
+	copy(sorted, weights)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created.Before(sorted[j].Created)
+	})
+	day := util.TruncateToDate(date)
+	weight := sorted[0]
+	for _, w := range sorted {
+		if localDate(w.Created, config).After(day) {
+			break
+		}
+		weight = w
+	}
+	return &weight
+}
+
+// bodyFatForDate returns the last body fat of the given measurements sorted by date, which has been
+// measured on or before the given date in the timezone of the given config. If there is no such
+// measurement, 0 is returned
+func bodyFatForDate(measurements []model.Measurement, date time.Time, config *model.Config) float64 {
+	day := util.TruncateToDate(date)
+	var bodyFat float64
+	for _, m := range measurements {
+		if localDate(m.Created, config).After(day) {
+			break
+		}
+		if m.BodyFat > 0 {
+			bodyFat = m.BodyFat
+		}
+	}
+	return bodyFat
+}
+
+// localDate returns the date of the given timestamp in the timezone and with the day rollover of
+// the given config, or in the local timezone, if there is no config
+// The date is truncated to midnight UTC, so it can be compared to other dates
+func localDate(t time.Time, config *model.Config) time.Time {
+	loc := time.Local
+	dayRollover := 0
+	if config != nil {
+		if configLoc, err := util.LoadLocation(config.Timezone); err == nil {
+			loc = configLoc
+		}
+		dayRollover = config.DayRollover
+	}
+	return util.TruncateToDate(util.CurrentDate(t, loc, dayRollover))
+}
+
+// metricMeasurement converts the lengths of the given measurement to cm, if the unit system is imperial
+func metricMeasurement(m *model.Measurement, unitSystem string) model.Measurement {
+	res := *m
+	if unitSystem == util.Imperial {
+		res.Waist = util.ToCm(res.Waist)
+		res.Hip = util.ToCm(res.Hip)
+		res.Chest = util.ToCm(res.Chest)
+		res.Neck = util.ToCm(res.Neck)
+		res.Arm = util.ToCm(res.Arm)
+		res.Thigh = util.ToCm(res.Thigh)
+	}
+	return res
+}
+
+// dayWater sums up the given water of a day and compares it to the target of the given config
+func dayWater(water []model.Water, config *model.Config) *model.DayWater {
+	res := &model.DayWater{Target: config.WaterTarget, UnitSystem: config.UnitSystem}
+	if res.Target <= 0 {
+		res.Target = util.DefaultWaterTarget
+	}
+	for _, w := range water {
+		res.Amount += w.Amount
+	}
+	return res
+}
+
+// searchEntries filters the given entries by the given query, tag and date range and sorts them by date
+// The tags of the days are taken from the given day notes
+func searchEntries(entries model.Entries, dayNotes []model.DayNote, query, tag string, from, to time.Time) (model.Entries, error) {
+	dayTags := map[string][]string{}
+	for _, note := range dayNotes {
+		dayTags[note.Date] = note.Tags
+	}
+	type datedEntry struct {
+		date  time.Time
+		entry model.Entry
+	}
+	var matches []datedEntry
+	for _, entry := range entries {
+		date, err := time.Parse(util.DateFormat, entry.EntryDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse date of entry %d, %v", entry.ID, err)
+		}
+		if (!from.IsZero() && date.Before(from)) || (!to.IsZero() && date.After(to)) {
+			continue
+		}
+		if query != "" && !util.MatchesFood(entry.Food, query) {
+			continue
+		}
+		if tag != "" && !util.HasTag(entry.Tags, tag) && !util.HasTag(dayTags[entry.EntryDate], tag) {
+			continue
+		}
+		matches = append(matches, datedEntry{date: date, entry: entry})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].date.Before(matches[j].date)
+	})
+	res := model.Entries{}
+	for _, match := range matches {
+		res = append(res, match.entry)
+	}
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package datasource
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+)
+
+func TestWeightForDate(t *testing.T) {
+	if res := weightForDate([]model.Weight{}, time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC), nil); res != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, nil)
+		return
+	}
+	weights := []model.Weight{
+		{Weight: 82, Created: time.Date(2017, 1, 7, 12, 0, 0, 0, time.UTC)},
+		{Weight: 80, Created: time.Date(2017, 1, 3, 12, 0, 0, 0, time.UTC)},
+		{Weight: 81, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+	}
+	var tests = []struct {
+		date     time.Time
+		expected float64
+	}{
+		{date: time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC), expected: 80},
+		{date: time.Date(2017, 1, 6, 12, 0, 0, 0, time.UTC), expected: 81},
+		{date: time.Date(2017, 1, 9, 12, 0, 0, 0, time.UTC), expected: 82},
+	}
+	for _, tc := range tests {
+		res := weightForDate(weights, tc.date, nil)
+		if res == nil || res.Weight != tc.expected {
+			t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+			return
+		}
+	}
+	if weights[0].Weight != 82 || weights[1].Weight != 80 || weights[2].Weight != 81 {
+		t.Errorf("Error, actual: %v expected: %v", weights, "the weights in their original order")
+		return
+	}
+}
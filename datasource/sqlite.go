@@ -0,0 +1,1065 @@
+package datasource
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+
+	// registers the pure Go sqlite driver, so no cgo is needed
+	_ "modernc.org/sqlite"
+)
+
+// SQLitePrefix is the prefix of connection strings, which point to a SQLite database
+const SQLitePrefix = "sqlite://"
+
+// sqliteDateFormat is the format of all dates stored in SQLite, so they can be compared and sorted
+const sqliteDateFormat = "2006-01-02"
+
+// sqliteTimeFormat is the format of all timestamps stored in SQLite, which are always in UTC
+const sqliteTimeFormat = "2006-01-02 15:04:05.000000000"
+
+// sqliteSchema creates all tables and their date indexes, if they don't exist yet
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS profiles (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE,
+	active INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS configs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	effective TEXT NOT NULL,
+	height REAL NOT NULL,
+	activity REAL NOT NULL,
+	birthday TEXT NOT NULL,
+	gender TEXT NOT NULL,
+	unit_system TEXT NOT NULL,
+	date_format TEXT NOT NULL,
+	timezone TEXT NOT NULL,
+	day_rollover INTEGER NOT NULL,
+	budget TEXT NOT NULL,
+	water_target REAL NOT NULL,
+	formula TEXT NOT NULL,
+	eating_window TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS configs_effective ON configs (profile_id, effective);
+CREATE TABLE IF NOT EXISTS weights (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	created TEXT NOT NULL,
+	weight REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS weights_created ON weights (profile_id, created);
+CREATE TABLE IF NOT EXISTS entries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	created TEXT NOT NULL,
+	entry_date TEXT NOT NULL,
+	calories INTEGER NOT NULL,
+	food TEXT NOT NULL,
+	bmr REAL NOT NULL,
+	amr REAL NOT NULL,
+	tags TEXT NOT NULL,
+	note TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS entries_entry_date ON entries (profile_id, entry_date);
+CREATE TABLE IF NOT EXISTS day_notes (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	date TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	note TEXT NOT NULL,
+	UNIQUE (profile_id, date)
+);
+CREATE TABLE IF NOT EXISTS water (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	created TEXT NOT NULL,
+	entry_date TEXT NOT NULL,
+	amount REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS water_entry_date ON water (profile_id, entry_date);
+CREATE TABLE IF NOT EXISTS measurements (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	created TEXT NOT NULL,
+	waist REAL NOT NULL,
+	hip REAL NOT NULL,
+	chest REAL NOT NULL,
+	neck REAL NOT NULL,
+	arm REAL NOT NULL,
+	thigh REAL NOT NULL,
+	body_fat REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS measurements_created ON measurements (profile_id, created);
+CREATE TABLE IF NOT EXISTS fasts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	profile_id INTEGER NOT NULL REFERENCES profiles(id),
+	start TEXT NOT NULL,
+	end TEXT
+);
+CREATE INDEX IF NOT EXISTS fasts_start ON fasts (profile_id, start);
+`
+
+const configColumns = "id, profile_id, effective, height, activity, birthday, gender, unit_system, date_format, timezone, day_rollover, budget, water_target, formula, eating_window"
+const entryColumns = "id, profile_id, created, entry_date, calories, food, bmr, amr, tags, note"
+const measurementColumns = "id, profile_id, created, waist, hip, chest, neck, arm, thigh, body_fat"
+
+// SQLiteDataSource is an implementation of the DataSource interface for SQLite
+// Dates are stored as yyyy-mm-dd and timestamps in UTC, so the data can be queried with SQL tools
+// Clock is used for timestamping new weights and entries
+// All data is scoped by the selected profile
+type SQLiteDataSource struct {
+	DB        *sql.DB
+	Clock     util.Clock
+	profileID int
+}
+
+// execer is implemented by the database and by transactions
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// scanner is implemented by sql.Row and sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// Setup opens the database file, creates the schema and the default profile, if there
+// are no profiles yet and selects the active profile
+func (ds *SQLiteDataSource) Setup(connection string) (func() error, error) {
+	db, err := openSQLite(connection)
+	if err != nil {
+		return nil, fmt.Errorf("error while connecting to database at %s, %v", connection, err)
+	}
+	ds.DB = db
+	_, err = db.Exec(sqliteSchema)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error while creating the schema at %s, %v", connection, err)
+	}
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM profiles").Scan(&count)
+	if err == nil && count == 0 {
+		_, err = db.Exec("INSERT INTO profiles (name, active) VALUES (?, 1)", DefaultProfile)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error while creating the default profile, %v", err)
+	}
+	profile, err := ds.CurrentProfile()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	ds.profileID = profile.ID
+	return db.Close, nil
+}
+
+// AddProfile adds a new profile with the given name
+func (ds *SQLiteDataSource) AddProfile(name string) error {
+	var count int
+	err := ds.DB.QueryRow("SELECT COUNT(*) FROM profiles WHERE name = ?", name).Scan(&count)
+	if err == nil && count > 0 {
+		return fmt.Errorf("profile %s already exists", name)
+	}
+	if err == nil {
+		_, err = ds.DB.Exec("INSERT INTO profiles (name, active) VALUES (?, 0)", name)
+	}
+	if err != nil {
+		return fmt.Errorf("could not add profile %s, %v", name, err)
+	}
+	return nil
+}
+
+// FetchProfiles fetches all profiles
+func (ds *SQLiteDataSource) FetchProfiles() ([]model.Profile, error) {
+	rows, err := ds.DB.Query("SELECT id, name, active FROM profiles ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve profiles: %v", err)
+	}
+	defer rows.Close()
+	var profiles []model.Profile
+	for rows.Next() {
+		var profile model.Profile
+		if err = rows.Scan(&profile.ID, &profile.Name, &profile.Active); err != nil {
+			return nil, fmt.Errorf("could not retrieve profiles: %v", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, rows.Err()
+}
+
+// CurrentProfile fetches and returns the active profile
+func (ds *SQLiteDataSource) CurrentProfile() (*model.Profile, error) {
+	var profile model.Profile
+	err := ds.DB.QueryRow("SELECT id, name, active FROM profiles WHERE active = 1 ORDER BY id LIMIT 1").Scan(&profile.ID, &profile.Name, &profile.Active)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch active profile: %v", err)
+	}
+	return &profile, nil
+}
+
+// fetchProfile fetches the profile with the given name
+func (ds *SQLiteDataSource) fetchProfile(name string) (*model.Profile, error) {
+	var profile model.Profile
+	err := ds.DB.QueryRow("SELECT id, name, active FROM profiles WHERE name = ?", name).Scan(&profile.ID, &profile.Name, &profile.Active)
+	if err != nil {
+		return nil, fmt.Errorf("could not find profile %s, %v", name, err)
+	}
+	return &profile, nil
+}
+
+// SelectProfile selects the profile with the given name for all following operations,
+// without making it the active profile
+func (ds *SQLiteDataSource) SelectProfile(name string) error {
+	profile, err := ds.fetchProfile(name)
+	if err != nil {
+		return err
+	}
+	ds.profileID = profile.ID
+	return nil
+}
+
+// UseProfile makes the profile with the given name the active profile
+func (ds *SQLiteDataSource) UseProfile(name string) error {
+	profile, err := ds.fetchProfile(name)
+	if err != nil {
+		return err
+	}
+	tx, err := ds.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("could not activate profile %s, %v", name, err)
+	}
+	_, err = tx.Exec("UPDATE profiles SET active = 0 WHERE active = 1")
+	if err == nil {
+		_, err = tx.Exec("UPDATE profiles SET active = 1 WHERE id = ?", profile.ID)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("could not activate profile %s, %v", name, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("could not activate profile %s, %v", name, err)
+	}
+	ds.profileID = profile.ID
+	return nil
+}
+
+// SetConfig adds a new version of the config with the given values, which is effective
+// from the given effective date on, or from today, if no effective date is given
+// A version with the same effective date is replaced
+func (ds *SQLiteDataSource) SetConfig(c *model.Config) error {
+	if err := validateConfig(c); err != nil {
+		return err
+	}
+	config := newConfigVersion(c, ds.profileID, util.Now(ds.Clock))
+	tx, err := ds.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("could not replace config effective from %s, %v", config.Effective.Format(util.DateFormat), err)
+	}
+	_, err = tx.Exec("DELETE FROM configs WHERE profile_id = ? AND effective = ?", ds.profileID, config.Effective.Format(sqliteDateFormat))
+	if err == nil {
+		err = insertConfig(tx, &config)
+	}
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("could not replace config effective from %s, %v", config.Effective.Format(util.DateFormat), err)
+	}
+	return tx.Commit()
+}
+
+// SetConfigFromImport overrides the config history with the given values
+// by deleting all old configs and adding a new one
+func (ds *SQLiteDataSource) SetConfigFromImport(c *model.Config) error {
+	tx, err := ds.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("could not replace config, %v", err)
+	}
+	err = ds.removeConfigs(tx)
+	if err == nil {
+		err = ds.addConfigFromImport(tx, c)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// addConfigFromImport adds the given config version without converting any units
+func (ds *SQLiteDataSource) addConfigFromImport(e execer, c *model.Config) error {
+	if c.UnitSystem != "metric" && c.UnitSystem != "imperial" {
+		return fmt.Errorf("unit system needs to be either metric or imperial: %s", c.UnitSystem)
+	}
+	config := importedConfigVersion(c, ds.profileID)
+	return insertConfig(e, &config)
+}
+
+// removeConfigs removes all configs of the selected profile
+func (ds *SQLiteDataSource) removeConfigs(e execer) error {
+	_, err := e.Exec("DELETE FROM configs WHERE profile_id = ?", ds.profileID)
+	return err
+}
+
+// FetchConfig fetches and returns the config, which is effective today in its timezone
+func (ds *SQLiteDataSource) FetchConfig() (*model.Config, error) {
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) == 0 {
+		return nil, fmt.Errorf("could not retrieve config: %v", err)
+	}
+	return configForToday(configs, util.Now(ds.Clock)), nil
+}
+
+// FetchConfigForDate fetches and returns the config, which is effective on the given date
+// If there is no config effective on that date, the oldest config is returned
+func (ds *SQLiteDataSource) FetchConfigForDate(date time.Time) (*model.Config, error) {
+	configs, err := ds.FetchConfigHistory()
+	if err != nil || len(configs) == 0 {
+		return nil, fmt.Errorf("could not retrieve config: %v", err)
+	}
+	return configForDate(configs, date), nil
+}
+
+// FetchConfigHistory fetches all config versions, sorted by their effective date
+func (ds *SQLiteDataSource) FetchConfigHistory() ([]model.Config, error) {
+	rows, err := ds.DB.Query("SELECT "+configColumns+" FROM configs WHERE profile_id = ? ORDER BY effective, id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve config history: %v", err)
+	}
+	defer rows.Close()
+	var configs []model.Config
+	for rows.Next() {
+		config, scanErr := scanConfig(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("could not retrieve config history: %v", scanErr)
+		}
+		configs = append(configs, *config)
+	}
+	return configs, rows.Err()
+}
+
+// AddWeight adds the given weight for todays date
+func (ds *SQLiteDataSource) AddWeight(weight float64) error {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return err
+	}
+	if config.UnitSystem == util.Imperial {
+		weight = util.ToKg(weight)
+	}
+	return insertWeight(ds.DB, &model.Weight{
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		Weight:    weight,
+	})
+}
+
+// CurrentWeight fetches and returns the current weight, which is the last added weight
+func (ds *SQLiteDataSource) CurrentWeight() (*model.Weight, error) {
+	weight, err := scanWeight(ds.DB.QueryRow("SELECT id, profile_id, created, weight FROM weights WHERE profile_id = ? ORDER BY id DESC LIMIT 1", ds.profileID))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current weight: %v", err)
+	}
+	return weight, nil
+}
+
+// FetchWeightForDate fetches and returns the weight on the given date, which is the last
+// weight added on or before that date. If there is no such weight, the oldest weight is returned
+func (ds *SQLiteDataSource) FetchWeightForDate(date time.Time) (*model.Weight, error) {
+	weights, err := ds.FetchWeights()
+	if err != nil || len(weights) == 0 {
+		return nil, fmt.Errorf("could not fetch weight for %s: %v", date.Format(util.DateFormat), err)
+	}
+	config, _ := ds.FetchConfigForDate(date)
+	return weightForDate(weights, date, config), nil
+}
+
+// FetchWeights fetches all weight entries
+func (ds *SQLiteDataSource) FetchWeights() ([]model.Weight, error) {
+	rows, err := ds.DB.Query("SELECT id, profile_id, created, weight FROM weights WHERE profile_id = ? ORDER BY id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve weight history: %v", err)
+	}
+	defer rows.Close()
+	var weights []model.Weight
+	for rows.Next() {
+		weight, scanErr := scanWeight(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("could not retrieve weight history: %v", scanErr)
+		}
+		weights = append(weights, *weight)
+	}
+	return weights, rows.Err()
+}
+
+// AddEntry fetches the config, weight and body fat effective on the entry date to calculate the metabolic
+// rate and adds the data into the entry table
+func (ds *SQLiteDataSource) AddEntry(entryDate string, calories int, food string, tags []string) error {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	weight, err := ds.FetchWeightForDate(date)
+	if err != nil {
+		return err
+	}
+	config, err := ds.FetchConfigForDate(date)
+	if err != nil {
+		return err
+	}
+	bodyFat, err := ds.FetchBodyFatForDate(date)
+	if err != nil {
+		return err
+	}
+	bmr, amr := util.CalculateMetabolicRatesForFormula(config.Formula, date, config.Birthday, config.Height, weight.Weight, bodyFat, config.Activity, config.Gender)
+	err = insertEntry(ds.DB, &model.Entry{
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		EntryDate: entryDate,
+		Calories:  calories,
+		Food:      food,
+		AMR:       amr,
+		BMR:       bmr,
+		Tags:      tags,
+	})
+	if err != nil {
+		return fmt.Errorf("could not add entry: %v", err)
+	}
+	return nil
+}
+
+// UpdateEntry saves the given, already existing entry
+func (ds *SQLiteDataSource) UpdateEntry(entry *model.Entry) error {
+	if entry.ID == 0 || entry.ProfileID != ds.profileID {
+		return fmt.Errorf("could not update entry with id %d, it does not exist", entry.ID)
+	}
+	date, err := toSQLiteDate(entry.EntryDate)
+	if err != nil {
+		return fmt.Errorf("could not update entry with id %d, %v", entry.ID, err)
+	}
+	res, err := ds.DB.Exec("UPDATE entries SET created = ?, entry_date = ?, calories = ?, food = ?, bmr = ?, amr = ?, tags = ?, note = ? WHERE id = ? AND profile_id = ?",
+		formatSQLiteTime(entry.Created), date, entry.Calories, entry.Food, entry.BMR, entry.AMR, strings.Join(entry.Tags, ","), entry.Note, entry.ID, ds.profileID)
+	if err != nil {
+		return fmt.Errorf("could not update entry with id %d, %v", entry.ID, err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("could not update entry with id %d, it does not exist", entry.ID)
+	}
+	return nil
+}
+
+// FetchEntries fetches and returns all entries for a given date
+func (ds *SQLiteDataSource) FetchEntries(entryDate string) (model.Entries, error) {
+	date, err := toSQLiteDate(entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch entries for the given date: %s, %v", entryDate, err)
+	}
+	entries, err := ds.queryEntries("SELECT "+entryColumns+" FROM entries WHERE profile_id = ? AND entry_date = ? ORDER BY id", ds.profileID, date)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch entries for the given date: %s, %v", entryDate, err)
+	}
+	return entries, nil
+}
+
+// FetchAllEntries fetches and returns all entries
+func (ds *SQLiteDataSource) FetchAllEntries() (model.Entries, error) {
+	entries, err := ds.queryEntries("SELECT "+entryColumns+" FROM entries WHERE profile_id = ? ORDER BY id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch all entries, %v", err)
+	}
+	return entries, nil
+}
+
+// queryEntries runs the given query and scans the resulting entries
+func (ds *SQLiteDataSource) queryEntries(query string, args ...interface{}) (model.Entries, error) {
+	rows, err := ds.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries model.Entries
+	for rows.Next() {
+		entry, scanErr := scanEntry(rows)
+		if scanErr != nil {
+			return nil, scanErr
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, rows.Err()
+}
+
+// SearchEntries fetches all entries, whose food matches the given query and which are tagged
+// with the given tag, either directly or by their day, sorted by date
+// An empty query or tag is not used for filtering. A zero from-date or to-date means, that the
+// search is not limited in this direction
+func (ds *SQLiteDataSource) SearchEntries(query, tag string, from, to time.Time) (model.Entries, error) {
+	entries, err := ds.FetchAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	dayNotes, err := ds.FetchDayNotes()
+	if err != nil {
+		return nil, err
+	}
+	return searchEntries(entries, dayNotes, query, tag, from, to)
+}
+
+// RemoveEntries removes all entries for a given day from the database
+func (ds *SQLiteDataSource) RemoveEntries(entryDate string) error {
+	date, err := toSQLiteDate(entryDate)
+	if err != nil {
+		return fmt.Errorf("could not delete entries for %s", entryDate)
+	}
+	res, err := ds.DB.Exec("DELETE FROM entries WHERE profile_id = ? AND entry_date = ?", ds.profileID, date)
+	if err != nil {
+		return fmt.Errorf("could not delete entries for %s", entryDate)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("could not delete entries for %s", entryDate)
+	}
+	return nil
+}
+
+// RemoveEntry removes the entry with the given id for a given day from the database
+func (ds *SQLiteDataSource) RemoveEntry(entryDate string, id int) error {
+	date, err := toSQLiteDate(entryDate)
+	if err != nil {
+		return fmt.Errorf("could not delete entry with id %d on day %s", id, entryDate)
+	}
+	res, err := ds.DB.Exec("DELETE FROM entries WHERE profile_id = ? AND entry_date = ? AND id = ?", ds.profileID, date, id)
+	if err != nil {
+		return fmt.Errorf("could not delete entry with id %d on day %s", id, entryDate)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("could not delete entry with id %d on day %s", id, entryDate)
+	}
+	return nil
+}
+
+// SetDayNote sets the tags and the note of a day, replacing the previous ones
+// If there are neither tags nor a note, the note of the day is removed
+func (ds *SQLiteDataSource) SetDayNote(note *model.DayNote) error {
+	date, err := toSQLiteDate(note.Date)
+	if err != nil {
+		return fmt.Errorf("could not set note for %s, %v", note.Date, err)
+	}
+	if note.IsEmpty() {
+		_, err = ds.DB.Exec("DELETE FROM day_notes WHERE profile_id = ? AND date = ?", ds.profileID, date)
+		if err != nil {
+			return fmt.Errorf("could not remove note for %s, %v", note.Date, err)
+		}
+		return nil
+	}
+	_, err = ds.DB.Exec("INSERT INTO day_notes (profile_id, date, tags, note) VALUES (?, ?, ?, ?) ON CONFLICT (profile_id, date) DO UPDATE SET tags = excluded.tags, note = excluded.note",
+		ds.profileID, date, strings.Join(note.Tags, ","), note.Note)
+	if err != nil {
+		return fmt.Errorf("could not set note for %s, %v", note.Date, err)
+	}
+	return nil
+}
+
+// FetchDayNote fetches the note of the given day, returning an empty note,
+// if there is none
+func (ds *SQLiteDataSource) FetchDayNote(entryDate string) (*model.DayNote, error) {
+	date, err := toSQLiteDate(entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch note for %s, %v", entryDate, err)
+	}
+	note, err := scanDayNote(ds.DB.QueryRow("SELECT id, profile_id, date, tags, note FROM day_notes WHERE profile_id = ? AND date = ?", ds.profileID, date))
+	if err == sql.ErrNoRows {
+		return &model.DayNote{Date: entryDate}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch note for %s, %v", entryDate, err)
+	}
+	return note, nil
+}
+
+// FetchDayNotes fetches the notes of all days
+func (ds *SQLiteDataSource) FetchDayNotes() ([]model.DayNote, error) {
+	rows, err := ds.DB.Query("SELECT id, profile_id, date, tags, note FROM day_notes WHERE profile_id = ? ORDER BY id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch notes, %v", err)
+	}
+	defer rows.Close()
+	var notes []model.DayNote
+	for rows.Next() {
+		note, scanErr := scanDayNote(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("could not fetch notes, %v", scanErr)
+		}
+		notes = append(notes, *note)
+	}
+	return notes, rows.Err()
+}
+
+// AddWater adds the given amount of water for the given date
+func (ds *SQLiteDataSource) AddWater(entryDate string, amount float64) error {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return err
+	}
+	if config.UnitSystem == util.Imperial {
+		amount = util.ToMl(amount)
+	}
+	err = insertWater(ds.DB, &model.Water{
+		ProfileID: ds.profileID,
+		Created:   util.Now(ds.Clock).UTC(),
+		EntryDate: entryDate,
+		Amount:    amount,
+	})
+	if err != nil {
+		return fmt.Errorf("could not add water: %v", err)
+	}
+	return nil
+}
+
+// FetchWater fetches the water drunk on the given date and the water target of the
+// config effective on this date
+func (ds *SQLiteDataSource) FetchWater(entryDate string) (*model.DayWater, error) {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse entry date %s, %v", entryDate, err)
+	}
+	config, err := ds.FetchConfigForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	water, err := ds.queryWater("SELECT id, profile_id, created, entry_date, amount FROM water WHERE profile_id = ? AND entry_date = ? ORDER BY id", ds.profileID, date.Format(sqliteDateFormat))
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch water for %s, %v", entryDate, err)
+	}
+	return dayWater(water, config), nil
+}
+
+// FetchAllWater fetches all water entries
+func (ds *SQLiteDataSource) FetchAllWater() ([]model.Water, error) {
+	water, err := ds.queryWater("SELECT id, profile_id, created, entry_date, amount FROM water WHERE profile_id = ? ORDER BY id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch water, %v", err)
+	}
+	return water, nil
+}
+
+// queryWater runs the given query and scans the resulting water entries
+func (ds *SQLiteDataSource) queryWater(query string, args ...interface{}) ([]model.Water, error) {
+	rows, err := ds.DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var water []model.Water
+	for rows.Next() {
+		var w model.Water
+		var created, date string
+		if err = rows.Scan(&w.ID, &w.ProfileID, &created, &date, &w.Amount); err != nil {
+			return nil, err
+		}
+		if w.Created, err = parseSQLiteTime(created); err != nil {
+			return nil, err
+		}
+		if w.EntryDate, err = fromSQLiteDate(date); err != nil {
+			return nil, err
+		}
+		water = append(water, w)
+	}
+	return water, rows.Err()
+}
+
+// AddMeasurement adds the given body measurements for todays date
+func (ds *SQLiteDataSource) AddMeasurement(measurement *model.Measurement) error {
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return err
+	}
+	m := metricMeasurement(measurement, config.UnitSystem)
+	m.ProfileID = ds.profileID
+	m.Created = util.Now(ds.Clock).UTC()
+	err = insertMeasurement(ds.DB, &m)
+	if err != nil {
+		return fmt.Errorf("could not add measurement: %v", err)
+	}
+	return nil
+}
+
+// FetchMeasurements fetches all measurements ordered by date
+func (ds *SQLiteDataSource) FetchMeasurements() ([]model.Measurement, error) {
+	rows, err := ds.DB.Query("SELECT "+measurementColumns+" FROM measurements WHERE profile_id = ? ORDER BY created, id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch measurements, %v", err)
+	}
+	defer rows.Close()
+	var measurements []model.Measurement
+	for rows.Next() {
+		var m model.Measurement
+		var created string
+		if err = rows.Scan(&m.ID, &m.ProfileID, &created, &m.Waist, &m.Hip, &m.Chest, &m.Neck, &m.Arm, &m.Thigh, &m.BodyFat); err != nil {
+			return nil, fmt.Errorf("could not fetch measurements, %v", err)
+		}
+		if m.Created, err = parseSQLiteTime(created); err != nil {
+			return nil, fmt.Errorf("could not fetch measurements, %v", err)
+		}
+		measurements = append(measurements, m)
+	}
+	return measurements, rows.Err()
+}
+
+// FetchBodyFatForDate fetches the body fat percentage on the given date, which is the last
+// body fat measured on or before that date. If there is no such measurement, 0 is returned
+func (ds *SQLiteDataSource) FetchBodyFatForDate(date time.Time) (float64, error) {
+	measurements, err := ds.FetchMeasurements()
+	if err != nil {
+		return 0, err
+	}
+	config, _ := ds.FetchConfigForDate(date)
+	return bodyFatForDate(measurements, date, config), nil
+}
+
+// StartFast starts a fast at the given time, if there is no running fast
+func (ds *SQLiteDataSource) StartFast(start time.Time) error {
+	var running string
+	err := ds.DB.QueryRow("SELECT start FROM fasts WHERE profile_id = ? AND end IS NULL LIMIT 1", ds.profileID).Scan(&running)
+	if err == nil {
+		runningStart, _ := parseSQLiteTime(running)
+		return fmt.Errorf("there is already a fast running since %s", runningStart.Format(util.DateFormat))
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("could not start fast: %v", err)
+	}
+	err = insertFast(ds.DB, &model.Fast{ProfileID: ds.profileID, Start: start})
+	if err != nil {
+		return fmt.Errorf("could not start fast: %v", err)
+	}
+	return nil
+}
+
+// StopFast stops the running fast at the given time and returns it
+func (ds *SQLiteDataSource) StopFast(end time.Time) (*model.Fast, error) {
+	fasts, err := ds.FetchFasts()
+	if err != nil {
+		return nil, err
+	}
+	for i := range fasts {
+		if !fasts[i].IsRunning() {
+			continue
+		}
+		fast := fasts[i]
+		fast.End = end.UTC()
+		_, err = ds.DB.Exec("UPDATE fasts SET end = ? WHERE id = ?", formatSQLiteTime(fast.End), fast.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not stop fast: %v", err)
+		}
+		return &fast, nil
+	}
+	return nil, fmt.Errorf("there is no running fast")
+}
+
+// FetchFasts fetches all fasts ordered by their start
+func (ds *SQLiteDataSource) FetchFasts() ([]model.Fast, error) {
+	rows, err := ds.DB.Query("SELECT id, profile_id, start, end FROM fasts WHERE profile_id = ? ORDER BY start, id", ds.profileID)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch fasts, %v", err)
+	}
+	defer rows.Close()
+	var fasts []model.Fast
+	for rows.Next() {
+		var fast model.Fast
+		var start string
+		var end sql.NullString
+		if err = rows.Scan(&fast.ID, &fast.ProfileID, &start, &end); err != nil {
+			return nil, fmt.Errorf("could not fetch fasts, %v", err)
+		}
+		if fast.Start, err = parseSQLiteTime(start); err != nil {
+			return nil, fmt.Errorf("could not fetch fasts, %v", err)
+		}
+		if end.Valid {
+			if fast.End, err = parseSQLiteTime(end.String); err != nil {
+				return nil, fmt.Errorf("could not fetch fasts, %v", err)
+			}
+		}
+		fasts = append(fasts, fast)
+	}
+	return fasts, rows.Err()
+}
+
+// Import imports the given data to the database in one transaction, overwriting the previous
+// data
+func (ds *SQLiteDataSource) Import(data *model.ImpEx) error {
+	tx, err := ds.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("could not start import, %v", err)
+	}
+	err = ds.importData(tx, data)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// importData replaces the data of the selected profile with the given data
+func (ds *SQLiteDataSource) importData(tx *sql.Tx, data *model.ImpEx) error {
+	configs := []model.Config{*data.Config}
+	if len(data.ConfigHistory) > 0 {
+		configs = data.ConfigHistory
+	}
+	if err := ds.removeConfigs(tx); err != nil {
+		return fmt.Errorf("could not replace config, %v", err)
+	}
+	for i := range configs {
+		if err := ds.addConfigFromImport(tx, &configs[i]); err != nil {
+			return fmt.Errorf("could not replace config history, %v", err)
+		}
+	}
+	for _, table := range []string{"weights", "entries", "day_notes", "water", "measurements", "fasts"} {
+		if _, err := tx.Exec("DELETE FROM "+table+" WHERE profile_id = ?", ds.profileID); err != nil {
+			return fmt.Errorf("could not remove %s, %v", table, err)
+		}
+	}
+	for _, weight := range data.Weights {
+		weight.ProfileID = ds.profileID
+		if err := insertWeight(tx, &weight); err != nil {
+			return fmt.Errorf("could not insert/update weight with id %d", weight.ID)
+		}
+	}
+	for _, entry := range data.Entries {
+		entry.ProfileID = ds.profileID
+		if err := insertEntry(tx, &entry); err != nil {
+			return fmt.Errorf("could not insert/update entry with id %d", entry.ID)
+		}
+	}
+	for _, note := range data.DayNotes {
+		date, err := toSQLiteDate(note.Date)
+		if err == nil {
+			_, err = tx.Exec("INSERT INTO day_notes (profile_id, date, tags, note) VALUES (?, ?, ?, ?)", ds.profileID, date, strings.Join(note.Tags, ","), note.Note)
+		}
+		if err != nil {
+			return fmt.Errorf("could not insert/update note for %s", note.Date)
+		}
+	}
+	for _, water := range data.Water {
+		water.ProfileID = ds.profileID
+		if err := insertWater(tx, &water); err != nil {
+			return fmt.Errorf("could not insert/update water for %s", water.EntryDate)
+		}
+	}
+	for _, measurement := range data.Measurements {
+		measurement.ProfileID = ds.profileID
+		if err := insertMeasurement(tx, &measurement); err != nil {
+			return fmt.Errorf("could not insert/update measurement with id %d", measurement.ID)
+		}
+	}
+	for _, fast := range data.Fasts {
+		fast.ProfileID = ds.profileID
+		if err := insertFast(tx, &fast); err != nil {
+			return fmt.Errorf("could not insert/update fast with id %d", fast.ID)
+		}
+	}
+	return nil
+}
+
+// Export creates a JSON representation of the database
+func (ds *SQLiteDataSource) Export() (*model.ImpEx, error) {
+	entries, err := ds.FetchAllEntries()
+	if err != nil {
+		return nil, err
+	}
+	weights, err := ds.FetchWeights()
+	if err != nil {
+		return nil, err
+	}
+	config, err := ds.FetchConfig()
+	if err != nil {
+		return nil, err
+	}
+	configs, err := ds.FetchConfigHistory()
+	if err != nil {
+		return nil, err
+	}
+	dayNotes, err := ds.FetchDayNotes()
+	if err != nil {
+		return nil, err
+	}
+	water, err := ds.FetchAllWater()
+	if err != nil {
+		return nil, err
+	}
+	measurements, err := ds.FetchMeasurements()
+	if err != nil {
+		return nil, err
+	}
+	fasts, err := ds.FetchFasts()
+	if err != nil {
+		return nil, err
+	}
+	impex := &model.ImpEx{
+		Config:        config,
+		ConfigHistory: configs,
+		Entries:       entries,
+		Weights:       weights,
+		DayNotes:      dayNotes,
+		Water:         water,
+		Measurements:  measurements,
+		Fasts:         fasts,
+	}
+	return impex, nil
+}
+
+// Backup writes a consistent copy of the database to the given path
+func (ds *SQLiteDataSource) Backup(path string) error {
+	_, err := ds.DB.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// insertConfig inserts the given config version
+func insertConfig(e execer, c *model.Config) error {
+	_, err := e.Exec("INSERT INTO configs (profile_id, effective, height, activity, birthday, gender, unit_system, date_format, timezone, day_rollover, budget, water_target, formula, eating_window) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		c.ProfileID, c.Effective.Format(sqliteDateFormat), c.Height, c.Activity, c.Birthday.Format(sqliteDateFormat), c.Gender, c.UnitSystem, c.DateFormat, c.Timezone, c.DayRollover, c.Budget, c.WaterTarget, c.Formula, c.EatingWindow)
+	return err
+}
+
+// insertWeight inserts the given weight with a new id
+func insertWeight(e execer, w *model.Weight) error {
+	_, err := e.Exec("INSERT INTO weights (profile_id, created, weight) VALUES (?, ?, ?)", w.ProfileID, formatSQLiteTime(w.Created), w.Weight)
+	return err
+}
+
+// insertEntry inserts the given entry with a new id
+func insertEntry(e execer, entry *model.Entry) error {
+	date, err := toSQLiteDate(entry.EntryDate)
+	if err != nil {
+		return err
+	}
+	_, err = e.Exec("INSERT INTO entries (profile_id, created, entry_date, calories, food, bmr, amr, tags, note) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.ProfileID, formatSQLiteTime(entry.Created), date, entry.Calories, entry.Food, entry.BMR, entry.AMR, strings.Join(entry.Tags, ","), entry.Note)
+	return err
+}
+
+// insertWater inserts the given water with a new id
+func insertWater(e execer, w *model.Water) error {
+	date, err := toSQLiteDate(w.EntryDate)
+	if err != nil {
+		return err
+	}
+	_, err = e.Exec("INSERT INTO water (profile_id, created, entry_date, amount) VALUES (?, ?, ?, ?)", w.ProfileID, formatSQLiteTime(w.Created), date, w.Amount)
+	return err
+}
+
+// insertMeasurement inserts the given measurement with a new id
+func insertMeasurement(e execer, m *model.Measurement) error {
+	_, err := e.Exec("INSERT INTO measurements (profile_id, created, waist, hip, chest, neck, arm, thigh, body_fat) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		m.ProfileID, formatSQLiteTime(m.Created), m.Waist, m.Hip, m.Chest, m.Neck, m.Arm, m.Thigh, m.BodyFat)
+	return err
+}
+
+// insertFast inserts the given fast with a new id, a running fast has no end
+func insertFast(e execer, f *model.Fast) error {
+	var end interface{}
+	if !f.IsRunning() {
+		end = formatSQLiteTime(f.End)
+	}
+	_, err := e.Exec("INSERT INTO fasts (profile_id, start, end) VALUES (?, ?, ?)", f.ProfileID, formatSQLiteTime(f.Start), end)
+	return err
+}
+
+// scanConfig scans a config row selected with configColumns
+func scanConfig(s scanner) (*model.Config, error) {
+	var c model.Config
+	var effective, birthday string
+	err := s.Scan(&c.ID, &c.ProfileID, &effective, &c.Height, &c.Activity, &birthday, &c.Gender, &c.UnitSystem, &c.DateFormat, &c.Timezone, &c.DayRollover, &c.Budget, &c.WaterTarget, &c.Formula, &c.EatingWindow)
+	if err != nil {
+		return nil, err
+	}
+	if c.Effective, err = time.Parse(sqliteDateFormat, effective); err != nil {
+		return nil, err
+	}
+	if c.Birthday, err = time.Parse(sqliteDateFormat, birthday); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// scanWeight scans a weight row
+func scanWeight(s scanner) (*model.Weight, error) {
+	var w model.Weight
+	var created string
+	err := s.Scan(&w.ID, &w.ProfileID, &created, &w.Weight)
+	if err != nil {
+		return nil, err
+	}
+	if w.Created, err = parseSQLiteTime(created); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// scanEntry scans an entry row selected with entryColumns
+func scanEntry(s scanner) (*model.Entry, error) {
+	var entry model.Entry
+	var created, date, tags string
+	err := s.Scan(&entry.ID, &entry.ProfileID, &created, &date, &entry.Calories, &entry.Food, &entry.BMR, &entry.AMR, &tags, &entry.Note)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Created, err = parseSQLiteTime(created); err != nil {
+		return nil, err
+	}
+	if entry.EntryDate, err = fromSQLiteDate(date); err != nil {
+		return nil, err
+	}
+	entry.Tags = util.ParseTags(tags)
+	return &entry, nil
+}
+
+// scanDayNote scans a day note row
+func scanDayNote(s scanner) (*model.DayNote, error) {
+	var note model.DayNote
+	var date, tags string
+	err := s.Scan(&note.ID, &note.ProfileID, &date, &tags, &note.Note)
+	if err != nil {
+		return nil, err
+	}
+	if note.Date, err = fromSQLiteDate(date); err != nil {
+		return nil, err
+	}
+	note.Tags = util.ParseTags(tags)
+	return &note, nil
+}
+
+// openSQLite opens the SQLite database at the given path, waiting for locks held by other processes
+func openSQLite(path string) (*sql.DB, error) {
+	return sql.Open("sqlite", fmt.Sprintf("file:%s?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path))
+}
+
+// toSQLiteDate converts an entry date to the date format stored in SQLite
+func toSQLiteDate(entryDate string) (string, error) {
+	date, err := time.Parse(util.DateFormat, entryDate)
+	if err != nil {
+		return "", fmt.Errorf("could not parse date %s, %v", entryDate, err)
+	}
+	return date.Format(sqliteDateFormat), nil
+}
+
+// fromSQLiteDate converts a date stored in SQLite to an entry date
+func fromSQLiteDate(date string) (string, error) {
+	parsed, err := time.Parse(sqliteDateFormat, date)
+	if err != nil {
+		return "", fmt.Errorf("could not parse date %s, %v", date, err)
+	}
+	return parsed.Format(util.DateFormat), nil
+}
+
+// formatSQLiteTime formats the given time as a timestamp in UTC
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(sqliteTimeFormat)
+}
+
+// parseSQLiteTime parses a timestamp stored in SQLite
+func parseSQLiteTime(t string) (time.Time, error) {
+	return time.ParseInLocation(sqliteTimeFormat, t, time.UTC)
+}
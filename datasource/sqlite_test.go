@@ -0,0 +1,175 @@
+package datasource
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func setupSQLite(t *testing.T) (*SQLiteDataSource, func()) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Fatalf("Error, actual: %v expected: %v", err, nil)
+	}
+	ds := &SQLiteDataSource{Clock: util.FixedClock{Time: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)}}
+	close, err := ds.Setup(filepath.Join(dir, "calories.sqlite"))
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Error, actual: %v expected: %v", err, nil)
+	}
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	err = ds.SetConfig(&model.Config{Height: 180, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		err = ds.AddWeight(80)
+	}
+	if err != nil {
+		t.Fatalf("Error, actual: %v expected: %v", err, nil)
+	}
+	return ds, func() {
+		close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestSQLiteConfig(t *testing.T) {
+	ds, cleanup := setupSQLite(t)
+	defer cleanup()
+	config, err := ds.FetchConfig()
+	if err != nil || config.Height != 180 || config.Birthday.Format(util.DateFormat) != "01.01.1990" {
+		t.Errorf("Error, actual: %v, %v expected: %v", config, err, "the saved config")
+		return
+	}
+	effective := time.Date(2017, 2, 1, 0, 0, 0, 0, time.UTC)
+	err = ds.SetConfig(&model.Config{Effective: effective, Height: 181, Activity: 1.2, Birthday: config.Birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		err = ds.SetConfig(&model.Config{Height: 182, Activity: 1.2, Birthday: config.Birthday, Gender: "male", UnitSystem: util.Metric})
+	}
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	history, _ := ds.FetchConfigHistory()
+	if len(history) != 2 || history[0].Height != 182 || history[1].Height != 181 {
+		t.Errorf("Error, actual: %v expected: %v", history, "the replaced config and the future config")
+		return
+	}
+	config, _ = ds.FetchConfigForDate(effective.AddDate(0, 0, 1))
+	if config.Height != 181 {
+		t.Errorf("Error, actual: %v expected: %v", config.Height, 181)
+		return
+	}
+}
+
+func TestSQLiteSetConfigKeepsConfigOnError(t *testing.T) {
+	ds, cleanup := setupSQLite(t)
+	defer cleanup()
+	_, err := ds.DB.Exec("CREATE TRIGGER fail_configs BEFORE INSERT ON configs BEGIN SELECT RAISE(ABORT, 'failed'); END")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	birthday, _ := time.Parse(util.DateFormat, "01.01.1990")
+	err = ds.SetConfig(&model.Config{Height: 190, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	err = ds.SetConfigFromImport(&model.Config{Height: 190, Activity: 1.2, Birthday: birthday, Gender: "male", UnitSystem: util.Metric})
+	if err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "an error")
+		return
+	}
+	config, err := ds.FetchConfig()
+	if err != nil || config.Height != 180 {
+		t.Errorf("Error, actual: %v, %v expected: %v", config, err, "the old config")
+		return
+	}
+}
+
+func TestSQLiteWeights(t *testing.T) {
+	ds, cleanup := setupSQLite(t)
+	defer cleanup()
+	ds.Clock = util.FixedClock{Time: time.Date(2017, 1, 7, 12, 0, 0, 0, time.UTC)}
+	if err := ds.AddWeight(79.5); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	weights, err := ds.FetchWeights()
+	if err != nil || len(weights) != 2 || weights[0].Weight != 80 || weights[1].Weight != 79.5 {
+		t.Errorf("Error, actual: %v, %v expected: %v", weights, err, "80 and 79.5")
+		return
+	}
+	weight, _ := ds.FetchWeightForDate(time.Date(2017, 1, 6, 0, 0, 0, 0, time.UTC))
+	if weight.Weight != 80 {
+		t.Errorf("Error, actual: %v expected: %v", weight.Weight, 80)
+		return
+	}
+	weight, _ = ds.CurrentWeight()
+	if weight.Weight != 79.5 {
+		t.Errorf("Error, actual: %v expected: %v", weight.Weight, 79.5)
+		return
+	}
+}
+
+func TestSQLiteEntries(t *testing.T) {
+	ds, cleanup := setupSQLite(t)
+	defer cleanup()
+	ds.AddEntry("05.01.2017", 100, "Apple", []string{"fruit", "snack"})
+	ds.AddEntry("05.01.2017", 200, "Pizza", nil)
+	ds.AddEntry("06.01.2017", 300, "Pasta", nil)
+	entries, err := ds.FetchEntries("05.01.2017")
+	if err != nil || len(entries) != 2 || entries[0].Food != "Apple" || len(entries[0].Tags) != 2 || entries[0].EntryDate != "05.01.2017" || entries[0].AMR == 0 {
+		t.Errorf("Error, actual: %v, %v expected: %v", entries, err, "Apple and Pizza")
+		return
+	}
+	if err = ds.RemoveEntry("05.01.2017", entries[0].ID); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	if err = ds.RemoveEntries("06.01.2017"); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, _ = ds.FetchAllEntries()
+	if len(entries) != 1 || entries[0].Food != "Pizza" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "Pizza")
+		return
+	}
+}
+
+func TestSQLiteImportExport(t *testing.T) {
+	ds, cleanup := setupSQLite(t)
+	defer cleanup()
+	ds.AddEntry("05.01.2017", 100, "Apple", []string{"fruit"})
+	ds.SetDayNote(&model.DayNote{Date: "05.01.2017", Note: "party"})
+	ds.AddWater("05.01.2017", 500)
+	data, err := ds.Export()
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	other, cleanupOther := setupSQLite(t)
+	defer cleanupOther()
+	other.AddEntry("06.01.2017", 300, "Pasta", nil)
+	if err = other.Import(data); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	entries, _ := other.FetchAllEntries()
+	if len(entries) != 1 || entries[0].Food != "Apple" || entries[0].Tags[0] != "fruit" {
+		t.Errorf("Error, actual: %v expected: %v", entries, "Apple")
+		return
+	}
+	note, _ := other.FetchDayNote("05.01.2017")
+	water, _ := other.FetchWater("05.01.2017")
+	weights, _ := other.FetchWeights()
+	if note.Note != "party" || water.Amount != 500 || len(weights) != 1 {
+		t.Errorf("Error, actual: %v, %v, %v expected: %v", note, water, weights, "the exported note, water and weight")
+		return
+	}
+}
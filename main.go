@@ -8,6 +8,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/kardianos/osext"
@@ -15,16 +16,16 @@ import (
 	"github.com/zupzup/calories/command"
 	"github.com/zupzup/calories/datasource"
 	"github.com/zupzup/calories/renderer"
+	"github.com/zupzup/calories/util"
 )
 
 // VERSION indicates the version of the binary
 const VERSION = "1.0.0"
 
-const configFile string = ".caloriesconf"
 const defaultDBFile string = "calories.db"
 
 // A flagset for all subcommands "e.g.: calories config
-var commandFlag = flag.NewFlagSet("", flag.ExitOnError)
+var commandFlag = flag.NewFlagSet("", flag.ContinueOnError)
 
 var (
 	weightFlag        float64
@@ -33,21 +34,51 @@ var (
 	birthDayFlag      string
 	genderFlag        string
 	unitFlag          string
+	dateFormatFlag    string
+	timezoneFlag      string
+	rolloverFlag      int
+	budgetFlag        string
+	waterTargetFlag   float64
+	configHistoryFlag bool
 	dateFlag          string
 	yesFlag           bool
 	commandOutputFlag string
 	positionFlag      int
 	fileFlag          string
+	fromFlag          string
+	toFlag            string
+	dryRunFlag        bool
+	tagsFlag          string
+	clearNoteFlag     bool
+	formulaFlag       string
+	eatingWindowFlag  string
+	waistFlag         float64
+	hipFlag           float64
+	chestFlag         float64
+	neckFlag          float64
+	armFlag           float64
+	thighFlag         float64
+	bodyFatFlag       float64
 
 	defaultDateFlag string
+	defaultTagFlag  string
 	weekFlag        bool
 	monthFlag       bool
 	histFlag        int
 	commandsFlag    bool
 	outputFlag      string
 	versionFlag     bool
+	nowFlag         string
+	profileFlag     string
+	dbFlag          string
 )
 
+// configFlagNames are the names of the command flags, which set the config
+var configFlagNames = []string{
+	"weight", "w", "height", "h", "activity", "a", "birthday", "b", "gender", "g", "unit", "u",
+	"dateformat", "df", "timezone", "tz", "rollover", "r", "budget", "water", "formula", "window", "date", "d",
+}
+
 func init() {
 	commandFlag.Float64Var(&weightFlag, "weight", -1, "your weight")
 	commandFlag.Float64Var(&weightFlag, "w", -1, "your weight (shorthand)")
@@ -61,19 +92,43 @@ func init() {
 	commandFlag.StringVar(&genderFlag, "g", "male", "your gender (shorthand)")
 	commandFlag.StringVar(&unitFlag, "unit", "metric", "your preferred unit system (metric | imperial)")
 	commandFlag.StringVar(&unitFlag, "u", "metric", "your preferred unit system (metric | imperial) (shorthand)")
-	commandFlag.StringVar(&dateFlag, "date", "", "date to add an entry on")
-	commandFlag.StringVar(&dateFlag, "d", "", "date to add an entry on (shorthand)")
+	commandFlag.StringVar(&dateFormatFlag, "dateformat", "", "your preferred date format (dd.mm.yyyy | mm/dd/yyyy | yyyy-mm-dd)")
+	commandFlag.StringVar(&dateFormatFlag, "df", "", "your preferred date format (dd.mm.yyyy | mm/dd/yyyy | yyyy-mm-dd) (shorthand)")
+	commandFlag.StringVar(&timezoneFlag, "timezone", "", "your timezone, e.g. Europe/Vienna or Local (default: the current one, or the local timezone)")
+	commandFlag.StringVar(&timezoneFlag, "tz", "", "your timezone, e.g. Europe/Vienna or Local (default: the current one, or the local timezone) (shorthand)")
+	commandFlag.IntVar(&rolloverFlag, "rollover", -1, "the hour at which a new day starts (0-23, -1 keeps the current one, or uses 0)")
+	commandFlag.IntVar(&rolloverFlag, "r", -1, "the hour at which a new day starts (0-23, -1 keeps the current one, or uses 0) (shorthand)")
+	commandFlag.StringVar(&budgetFlag, "budget", "", "your budget mode (daily | weekly) (default: the current one, or daily)")
+	commandFlag.Float64Var(&waterTargetFlag, "water", -1, "your daily water target in ml or fl oz (-1 keeps the current one, 0 uses 2000 ml)")
+	commandFlag.BoolVar(&configHistoryFlag, "history", false, "show all versions of the config")
+	commandFlag.StringVar(&dateFlag, "date", "", "date to add an entry on / date a config is effective from")
+	commandFlag.StringVar(&dateFlag, "d", "", "date to add an entry on / date a config is effective from (shorthand)")
 	commandFlag.BoolVar(&yesFlag, "yes", false, "skip confirmations")
 	commandFlag.BoolVar(&yesFlag, "y", false, "skip confirmations (shorthand)")
-	commandFlag.StringVar(&commandOutputFlag, "output", "terminal", "output format (terminal | json)")
-	commandFlag.StringVar(&commandOutputFlag, "o", "terminal", "output format (terminal | json) (shorthand)")
+	commandFlag.StringVar(&commandOutputFlag, "output", "terminal", "output format (terminal | json | csv | markdown | html)")
+	commandFlag.StringVar(&commandOutputFlag, "o", "terminal", "output format (terminal | json | csv | markdown | html) (shorthand)")
 	commandFlag.IntVar(&positionFlag, "position", -1, "position of the entry to clear (1-n)")
 	commandFlag.IntVar(&positionFlag, "p", -1, "position of the entry to clear (1-n) (shorthand)")
 	commandFlag.StringVar(&fileFlag, "file", "", "file to export to / import from")
 	commandFlag.StringVar(&fileFlag, "f", "", "file to export to / import from (shorthand)")
+	commandFlag.StringVar(&fromFlag, "from", "", "first date to recalculate / of the statistics / to search")
+	commandFlag.StringVar(&toFlag, "to", "", "last date to recalculate / of the statistics / to search")
+	commandFlag.BoolVar(&dryRunFlag, "dry-run", false, "only show the changes, without saving them")
+	commandFlag.StringVar(&tagsFlag, "tag", "", "tags of an entry / a day, e.g. cheatday,restaurant or the tag to search for")
+	commandFlag.BoolVar(&clearNoteFlag, "clear", false, "remove the tags and the note")
+	commandFlag.StringVar(&formulaFlag, "formula", "", "the formula for your BMR (harris-benedict | katch-mcardle) (default: the current one, or harris-benedict)")
+	commandFlag.StringVar(&eatingWindowFlag, "window", "", "your eating window as START-END hours, e.g. 12-20, or none to remove it (default: the current one)")
+	commandFlag.Float64Var(&waistFlag, "waist", 0, "your waist in cm or inches")
+	commandFlag.Float64Var(&hipFlag, "hip", 0, "your hip in cm or inches")
+	commandFlag.Float64Var(&chestFlag, "chest", 0, "your chest in cm or inches")
+	commandFlag.Float64Var(&neckFlag, "neck", 0, "your neck in cm or inches")
+	commandFlag.Float64Var(&armFlag, "arm", 0, "your arm in cm or inches")
+	commandFlag.Float64Var(&thighFlag, "thigh", 0, "your thigh in cm or inches")
+	commandFlag.Float64Var(&bodyFatFlag, "fat", 0, "your body fat in percent")
 
 	flag.StringVar(&defaultDateFlag, "date", "", "date to show")
 	flag.StringVar(&defaultDateFlag, "d", "", "date to show (shorthand)")
+	flag.StringVar(&defaultTagFlag, "tag", "", "only show days and entries with this tag")
 	flag.BoolVar(&weekFlag, "week", false, "show current week")
 	flag.BoolVar(&weekFlag, "w", false, "show current week (shorthand)")
 	flag.BoolVar(&monthFlag, "month", false, "show current month")
@@ -84,37 +139,67 @@ func init() {
 	flag.BoolVar(&commandsFlag, "c", false, "show list of commands (shorthand)")
 	flag.BoolVar(&versionFlag, "version", false, "show version")
 	flag.BoolVar(&versionFlag, "v", false, "show version (shorthand)")
-	flag.StringVar(&outputFlag, "output", "terminal", "output format (terminal | json)")
-	flag.StringVar(&outputFlag, "o", "terminal", "output format (terminal | json) (shorthand)")
+	flag.StringVar(&outputFlag, "output", "terminal", "output format (terminal | json | csv | markdown | html)")
+	flag.StringVar(&outputFlag, "o", "terminal", "output format (terminal | json | csv | markdown | html) (shorthand)")
+	flag.StringVar(&nowFlag, "now", "", "date to use as today, e.g. for reports as of a past date")
+	commandFlag.StringVar(&nowFlag, "now", "", "date to use as today, e.g. for reports as of a past date")
+	flag.StringVar(&profileFlag, "profile", "", "profile to use instead of the active profile")
+	commandFlag.StringVar(&profileFlag, "profile", "", "profile to use instead of the active profile")
+	flag.StringVar(&dbFlag, "db", "", "database to use without reading the config file (:memory: for an in-memory database)")
+	commandFlag.StringVar(&dbFlag, "db", "", "database to use without reading the config file (:memory: for an in-memory database)")
 }
 
 func main() {
 	flag.Parse()
 	var r renderer.Renderer
 	r = &renderer.TerminalRenderer{}
-	ds := &datasource.BoltDataSource{}
-	folder, err := osext.ExecutableFolder()
-	if err != nil {
-		fatalError(r, fmt.Errorf("error reading folder containing the calories binary, %v", err))
+	if len(flag.Args()) > 0 {
+		if err := parseCommandFlags(flag.Args()[1:]); err != nil {
+			fatalError(r, err)
+		}
+	}
+	if flag.Arg(0) == "init" {
+		res, err := handleInitCommand(commandOutputFlag)
+		if err != nil {
+			fatalError(r, err)
+		}
+		fmt.Fprintln(color.Output, res)
+		return
 	}
-	var configFile = filepath.Join(folder, configFile)
-	config, err := ioutil.ReadFile(configFile)
+	fileConfig := &util.ConfigFile{}
+	var err error
+	if dbFlag == "" {
+		fileConfig, err = readConfigFile()
+		if err != nil {
+			fatalError(r, err)
+		}
+	}
+	config, err := fileConfig.WithEnv()
 	if err != nil {
-		asciilogo()
-		if os.IsNotExist(err) {
-			configToSet, configErr := createConfig(config, folder, defaultDBFile)
-			if configErr != nil {
-				fatalError(r, configErr)
-			}
-			config = []byte(configToSet)
-		} else {
-			fatalError(r, fmt.Errorf("error reading config file at %s, %v", configFile, err))
+		fatalError(r, err)
+	}
+	applyConfigDefaults(config)
+	dbString := dbFlag
+	if dbString == "" {
+		dbString = config.DB
+	}
+	if dbString == "" {
+		dbString, err = createConfig(fileConfig)
+		if err != nil {
+			fatalError(r, err)
+		}
+	}
+	if flag.Arg(0) == "db" {
+		res, err := handleDBCommand(dbString, fileConfig, config, commandOutputFlag, commandFlag.Args())
+		if err != nil {
+			fatalError(r, err)
 		}
+		fmt.Fprintln(color.Output, res)
+		return
 	}
-	dbString := string(config)
-	close, err := ds.Setup(dbString)
+	ds, close, err := openDataSource(dbString)
 	if err != nil {
-		fatalError(r, fmt.Errorf("could not connect to database at %s, if you want to set a new database file, please use the 'calories db' command, %v", dbString, err))
+		fatalError(r, err)
 	}
 	defer func() {
 		closeErr := close()
@@ -122,15 +207,27 @@ func main() {
 			fatalError(r, closeErr)
 		}
 	}()
+	if profileFlag != "" {
+		err = ds.SelectProfile(profileFlag)
+		if err != nil {
+			fatalError(r, err)
+		}
+	}
+	s, err := fetchSettings(ds, nowFlag, dateFormatFlag, config.DateFormat)
+	if err != nil {
+		fatalError(r, err)
+	}
+	setClock(ds, s.clock)
+	s.backup = automaticBackup(ds, dbString, backupRotations(config))
 
 	if len(flag.Args()) > 0 {
-		res, err := handleSubCommand(commandFlag, commandOutputFlag, ds, r, os.Args)
+		res, err := handleSubCommand(flag.Arg(0), commandOutputFlag, ds, s, commandFlag.Args())
 		if err != nil {
 			fatalError(r, err)
 		}
 		fmt.Fprintln(color.Output, res)
 	} else {
-		res, err := handleNoSubCommand(commandsFlag, outputFlag, ds, r, os.Args)
+		res, err := handleNoSubCommand(commandsFlag, outputFlag, ds, s, os.Args)
 		if err != nil {
 			fatalError(r, err)
 		}
@@ -139,20 +236,152 @@ func main() {
 }
 
 // handleSubCommand handles calls to subcommands
-func handleSubCommand(commandFlag *flag.FlagSet, commandOutputFlag string, ds datasource.DataSource, r renderer.Renderer, args []string) (string, error) {
-	err := commandFlag.Parse(args[2:])
+func handleSubCommand(command, commandOutputFlag string, ds datasource.DataSource, s *settings, args []string) (string, error) {
+	return executeCommand(ds, newRenderer(commandOutputFlag, s), s, command, args)
+}
+
+// handleDBCommand handles the db command, which works on the database file
+// without opening the database
+func handleDBCommand(dbString string, fileConfig, config *util.ConfigFile, commandOutputFlag string, args []string) (string, error) {
+	var action, target string
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if len(args) > 1 {
+		target = args[1]
+	}
+	dbCmd := command.DBCommand{
+		Renderer:        newRenderer(commandOutputFlag, &settings{dateFormat: resolveDateFormat(dateFormatFlag, "", config.DateFormat)}),
+		Connection:      strings.TrimSpace(dbString),
+		Config:          fileConfig,
+		FromFlag:        dbFlag != "" || os.Getenv(util.DBEnv) != "",
+		Action:          action,
+		Target:          target,
+		BackupRotations: backupRotations(config),
+		YesMode:         yesFlag,
+		Passphrase:      util.ReadPassphrase,
+		Open: func() (datasource.DataSource, func() error, error) {
+			return openDataSource(dbString)
+		},
+		Clock: util.SystemClock{},
+	}
+	return dbCmd.Execute()
+}
+
+// openDataSource opens the database of the given connection, asking for the
+// passphrase, if it is encrypted
+func openDataSource(dbString string) (datasource.DataSource, func() error, error) {
+	ds, connection := datasource.New(dbString)
+	if boltDS, ok := ds.(*datasource.BoltDataSource); ok {
+		boltDS.Passphrase = func() (string, error) {
+			return util.ReadPassphrase(false)
+		}
+	}
+	close, err := ds.Setup(connection)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not connect to database at %s, if you want to set a new database file, please use the 'calories db set' command, %v", dbString, err)
+	}
+	return ds, close, nil
+}
+
+// automaticBackup returns the function to back up the database before destructive commands,
+// which is nil for in-memory databases or if automatic backups are disabled
+func automaticBackup(ds datasource.DataSource, dbString string, rotations int) func() error {
+	if rotations == 0 {
+		return nil
+	}
+	backups, err := datasource.NewBackups(dbString, rotations, util.SystemClock{})
+	if err != nil {
+		return nil
+	}
+	return func() error {
+		_, backupErr := backups.Create(ds)
+		return backupErr
+	}
+}
+
+// backupRotations returns the number of backups to keep from the config, or the default
+func backupRotations(config *util.ConfigFile) int {
+	if config.Backups == nil {
+		return datasource.DefaultBackupRotations
+	}
+	return *config.Backups
+}
+
+// applyConfigDefaults uses the output format and the profile of the config for the flags, which are not set
+func applyConfigDefaults(config *util.ConfigFile) {
+	if config.Output != "" {
+		if !isFlagSet(flag.CommandLine, "output", "o") {
+			outputFlag = config.Output
+		}
+		if !isFlagSet(commandFlag, "output", "o") {
+			commandOutputFlag = config.Output
+		}
+	}
+	if profileFlag == "" {
+		profileFlag = config.Profile
+	}
+}
+
+// isFlagSet checks, whether one of the flags with the given names was set on the command line
+func isFlagSet(flags *flag.FlagSet, names ...string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		for _, name := range names {
+			if f.Name == name {
+				set = true
+			}
+		}
+	})
+	return set
+}
+
+// configFlagCount returns the number of flags setting the config, which were set on the command line
+// Global flags like --db, --profile and --output are not counted
+func configFlagCount(flags *flag.FlagSet) int {
+	count := 0
+	for _, name := range configFlagNames {
+		if isFlagSet(flags, name) {
+			count++
+		}
+	}
+	return count
+}
+
+// handleInitCommand handles the init command, which sets up calories without any prompts
+func handleInitCommand(commandOutputFlag string) (string, error) {
+	fileConfig, err := readConfigFile()
+	if err != nil {
+		return "", err
+	}
+	config, err := fileConfig.WithEnv()
 	if err != nil {
 		return "", err
 	}
-	command := args[1]
-	if commandOutputFlag == "json" {
-		r = &renderer.JSONRenderer{}
+	s := &settings{
+		dateFormat: resolveDateFormat(dateFormatFlag, "", config.DateFormat),
+		location:   time.Local,
+		clock:      util.SystemClock{},
+	}
+	r := newRenderer(commandOutputFlag, s)
+	var configure *command.ConfigCommand
+	if configFlags := configFlagCount(commandFlag); configFlags > 0 {
+		configure = newConfigCommand(nil, r, s)
+		configure.Mode = configFlags
 	}
-	return executeCommand(ds, r, command, commandFlag.Args())
+	initCmd := command.InitCommand{
+		Renderer:  r,
+		Config:    fileConfig,
+		DB:        dbFlag,
+		DefaultDB: defaultDB(fileConfig),
+		Configure: configure,
+		Open:      openDataSource,
+	}
+	return initCmd.Execute()
 }
 
 // handleNoSubCommand handles calls without a subcommand
-func handleNoSubCommand(commandsFlag bool, outputFlag string, ds datasource.DataSource, r renderer.Renderer, args []string) (string, error) {
+func handleNoSubCommand(commandsFlag bool, outputFlag string, ds datasource.DataSource, s *settings, args []string) (string, error) {
 	if commandsFlag {
 		printCommands()
 		return "", nil
@@ -160,23 +389,106 @@ func handleNoSubCommand(commandsFlag bool, outputFlag string, ds datasource.Data
 	if versionFlag {
 		return fmt.Sprintf("Current Version: %s", VERSION), nil
 	}
-	if outputFlag == "json" {
-		r = &renderer.JSONRenderer{}
+	return executeCommand(ds, newRenderer(outputFlag, s), s, "", args)
+}
+
+// settings are the date and time settings of the current config, which are
+// used by the commands and renderers, backup backs up the database before destructive commands
+type settings struct {
+	dateFormat   string
+	location     *time.Location
+	dayRollover  int
+	budget       string
+	eatingWindow string
+	clock        util.Clock
+	backup       func() error
+}
+
+// newRenderer creates the renderer for the given output format, printing dates
+// in the configured display date format
+func newRenderer(output string, s *settings) renderer.Renderer {
+	if output == "json" {
+		return &renderer.JSONRenderer{DateFormat: s.dateFormat}
+	}
+	if output == "csv" {
+		return &renderer.CSVRenderer{DateFormat: s.dateFormat}
 	}
-	return executeCommand(ds, r, "", args)
+	if output == "markdown" {
+		return &renderer.MarkdownRenderer{TerminalRenderer: renderer.TerminalRenderer{DateFormat: s.dateFormat}}
+	}
+	if output == "html" {
+		return &renderer.HTMLRenderer{TerminalRenderer: renderer.TerminalRenderer{DateFormat: s.dateFormat}}
+	}
+	return &renderer.TerminalRenderer{DateFormat: s.dateFormat}
 }
 
-// createConfig asks the user which database file to use and writes the answer into
-// the .caloriesconf configuration file
-func createConfig(config []byte, folder, defaultDBFile string) (string, error) {
-	defaultConfig := filepath.Join(folder, defaultDBFile)
-	homedir, err := homedir.Dir()
+// fetchSettings returns the settings of the current config, or the
+// default settings, if no config has been set yet
+// If a date is given as now, the clock is fixed to noon of that date
+// The date format is the one given as a flag, the one of the config, the one of
+// the config file or environment, or the default, in this order
+func fetchSettings(ds datasource.DataSource, now, flagDateFormat, fileDateFormat string) (*settings, error) {
+	if flagDateFormat != "" && !util.IsValidDateFormat(flagDateFormat) {
+		return nil, fmt.Errorf("wrong date format: %s, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd", flagDateFormat)
+	}
+	s := &settings{
+		dateFormat: resolveDateFormat(flagDateFormat, "", fileDateFormat),
+		location:   time.Local,
+		clock:      util.SystemClock{},
+	}
+	config, err := ds.FetchConfig()
+	if err == nil {
+		location, locErr := util.LoadLocation(config.Timezone)
+		if locErr != nil {
+			return nil, fmt.Errorf("could not load timezone %s, %v", config.Timezone, locErr)
+		}
+		s.dateFormat = resolveDateFormat(flagDateFormat, config.DateFormat, fileDateFormat)
+		s.location = location
+		s.dayRollover = config.DayRollover
+		s.budget = config.Budget
+		s.eatingWindow = config.EatingWindow
+	}
+	if now != "" {
+		parsedNow, parseErr := util.ParseDate(now, s.dateFormat)
+		if parseErr != nil {
+			return nil, fmt.Errorf("wrong format for now: %v, please use %s", parseErr, s.dateFormat)
+		}
+		s.clock = util.FixedClock{Time: time.Date(parsedNow.Year(), parsedNow.Month(), parsedNow.Day(), 12, 0, 0, 0, s.location)}
+	}
+	return s, nil
+}
+
+// resolveDateFormat returns the first valid date format of the date format given as a flag,
+// the one of the config in the database and the one of the config file or environment,
+// or the default date format, if none of them is set
+func resolveDateFormat(flagDateFormat, configDateFormat, fileDateFormat string) string {
+	for _, format := range []string{flagDateFormat, configDateFormat, fileDateFormat} {
+		if util.IsValidDateFormat(format) {
+			return format
+		}
+	}
+	return util.DefaultDisplayDateFormat
+}
+
+// readConfigFile reads the config file from the first location, where it exists
+// If there is none, the config file is created at the first location
+func readConfigFile() (*util.ConfigFile, error) {
+	folder, err := osext.ExecutableFolder()
 	if err != nil {
-		fmt.Println("could not find home directory, using binary directory instead")
+		return nil, fmt.Errorf("error reading folder containing the calories binary, %v", err)
 	}
-	if homedir != "" {
-		defaultConfig = filepath.Join(homedir, defaultDBFile)
+	config, _, err := util.ReadConfigFile(util.ConfigFilePaths(folder))
+	return config, err
+}
+
+// createConfig asks the user which database file to use and writes the answer into
+// the config file. If stdin is not a terminal, it fails instead of waiting for an answer
+func createConfig(config *util.ConfigFile) (string, error) {
+	if !util.IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("no database is set in %s and stdin is not a terminal, please run 'calories init --db=[string PATH]' first, or use --db or %s", config.Path, util.DBEnv)
 	}
+	asciilogo()
+	defaultConfig := defaultDB(config)
 	configToSet := defaultConfig
 	prompt := bufio.NewReader(os.Stdin)
 	for {
@@ -191,15 +503,73 @@ func createConfig(config []byte, folder, defaultDBFile string) (string, error) {
 		}
 		break
 	}
-	configFilePath := filepath.Join(folder, configFile)
-	err = ioutil.WriteFile(configFilePath, []byte(configToSet), os.ModePerm)
+	config.DB = configToSet
+	err := config.Write()
 	if err != nil {
-		return "", fmt.Errorf("error writing config file at %s, %v", configFile, err)
+		return "", err
 	}
-	fmt.Printf("config file written successfully, using database: %s\n", configToSet)
+	fmt.Printf("config file written successfully to %s, using database: %s\n", config.Path, configToSet)
 	return configToSet, nil
 }
 
+// defaultDB returns the default database file in the home directory, or next to the config file
+func defaultDB(config *util.ConfigFile) string {
+	home, err := homedir.Dir()
+	if err != nil || home == "" {
+		return filepath.Join(filepath.Dir(config.Path), defaultDBFile)
+	}
+	return filepath.Join(home, defaultDBFile)
+}
+
+// setClock sets the clock used by the datasource for timestamping new data
+func setClock(ds datasource.DataSource, clock util.Clock) {
+	switch d := ds.(type) {
+	case *datasource.BoltDataSource:
+		d.Clock = clock
+	case *datasource.SQLiteDataSource:
+		d.Clock = clock
+	case *datasource.MemoryDataSource:
+		d.Clock = clock
+	}
+}
+
+// parseCommandFlags parses the flags of the subcommand and shows its usage and exits, if --help is given
+func parseCommandFlags(args []string) error {
+	commandFlag.SetOutput(ioutil.Discard)
+	err := commandFlag.Parse(args)
+	if err == flag.ErrHelp {
+		commandFlag.SetOutput(os.Stderr)
+		commandFlag.Usage()
+		os.Exit(0)
+	}
+	if err != nil {
+		return fmt.Errorf("%v, please see 'calories %s --help'", err, flag.Arg(0))
+	}
+	return nil
+}
+
+// parseInterspersedFlags parses the command flags between and after the given positional arguments,
+// e.g. of search QUERY --from=DATE, and returns the positional arguments
+// Arguments after -- are never parsed as flags
+func parseInterspersedFlags(args []string) ([]string, error) {
+	positional := []string{}
+	for len(args) > 0 {
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		if len(args[0]) > 1 && strings.HasPrefix(args[0], "-") {
+			if err := parseCommandFlags(args); err != nil {
+				return nil, err
+			}
+			args = commandFlag.Args()
+			continue
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+	return positional, nil
+}
+
 // fatalError prints the given error using the provided renderer and exits the program
 func fatalError(r renderer.Renderer, fatalError error) {
 	res, err := r.Error(fatalError)
@@ -211,10 +581,38 @@ func fatalError(r renderer.Renderer, fatalError error) {
 	os.Exit(1)
 }
 
+// newConfigCommand creates the config command from the command flags
+func newConfigCommand(ds datasource.DataSource, r renderer.Renderer, s *settings) *command.ConfigCommand {
+	return &command.ConfigCommand{
+		DataSource:        ds,
+		Renderer:          r,
+		Weight:            weightFlag,
+		Height:            heightFlag,
+		Activity:          activityFlag,
+		Birthday:          birthDayFlag,
+		Gender:            genderFlag,
+		UnitSystem:        unitFlag,
+		DateFormat:        dateFormatFlag,
+		CurrentDateFormat: s.dateFormat,
+		Timezone:          timezoneFlag,
+		DayRollover:       rolloverFlag,
+		Budget:            budgetFlag,
+		WaterTarget:       waterTargetFlag,
+		Formula:           formulaFlag,
+		EatingWindow:      eatingWindowFlag,
+		Date:              dateFlag,
+		History:           configHistoryFlag,
+		YesMode:           yesFlag,
+		Mode:              configFlagCount(commandFlag),
+		Clock:             s.clock,
+		Backup:            s.backup,
+	}
+}
+
 // executeCommand parses the subcommands and executes the associated command
 // If there is no subcommand, it executes the default command
 // which shows the current day/week/month
-func executeCommand(ds datasource.DataSource, r renderer.Renderer, cmd string, args []string) (string, error) {
+func executeCommand(ds datasource.DataSource, r renderer.Renderer, s *settings, cmd string, args []string) (string, error) {
 	switch cmd {
 	case "weight":
 		var weight string
@@ -226,21 +624,35 @@ func executeCommand(ds datasource.DataSource, r renderer.Renderer, cmd string, a
 			Renderer:   r,
 			Weight:     weight,
 			Mode:       len(args),
+			Location:   s.location,
 		})
-	case "config":
-		configCmd := command.ConfigCommand{
+	case "fast":
+		var action string
+		if len(args) > 0 {
+			action = args[0]
+		}
+		return checkConfig(ds, &command.FastCommand{
 			DataSource: ds,
 			Renderer:   r,
-			Weight:     weightFlag,
-			Height:     heightFlag,
-			Activity:   activityFlag,
-			Birthday:   birthDayFlag,
-			Gender:     genderFlag,
-			UnitSystem: unitFlag,
-			YesMode:    yesFlag,
-			Mode:       commandFlag.NFlag(),
-		}
-		return configCmd.Execute()
+			Action:     action,
+			Location:   s.location,
+			Clock:      s.clock,
+		})
+	case "measure":
+		return checkConfig(ds, &command.MeasureCommand{
+			DataSource: ds,
+			Renderer:   r,
+			Waist:      waistFlag,
+			Hip:        hipFlag,
+			Chest:      chestFlag,
+			Neck:       neckFlag,
+			Arm:        armFlag,
+			Thigh:      thighFlag,
+			BodyFat:    bodyFatFlag,
+			Location:   s.location,
+		})
+	case "config":
+		return newConfigCommand(ds, r, s).Execute()
 	case "add":
 		var food string
 		var calories string
@@ -250,21 +662,124 @@ func executeCommand(ds datasource.DataSource, r renderer.Renderer, cmd string, a
 		}
 
 		return checkConfig(ds, &command.AddEntryCommand{
-			DataSource: ds,
-			Renderer:   r,
-			Date:       dateFlag,
-			Food:       food,
-			Calories:   calories,
-			Mode:       len(args),
+			DataSource:   ds,
+			Renderer:     r,
+			Date:         dateFlag,
+			Food:         food,
+			Calories:     calories,
+			Tags:         tagsFlag,
+			EatingWindow: s.eatingWindow,
+			Mode:         len(args),
+			DateFormat:   s.dateFormat,
+			Location:     s.location,
+			DayRollover:  s.dayRollover,
+			Clock:        s.clock,
 		})
 	case "clear":
 		return checkConfig(ds, &command.ClearEntriesCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			Date:        dateFlag,
+			Position:    positionFlag,
+			YesMode:     yesFlag,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
+			Backup:      s.backup,
+		})
+	case "recalc":
+		return checkConfig(ds, &command.RecalcCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			From:        fromFlag,
+			To:          toFlag,
+			DryRun:      dryRunFlag,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
+		})
+	case "water":
+		var amount string
+		if len(args) > 0 {
+			amount = args[0]
+		}
+		return checkConfig(ds, &command.WaterCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			Date:        dateFlag,
+			Amount:      amount,
+			Mode:        len(args),
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
+		})
+	case "note":
+		return checkConfig(ds, &command.NoteCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			Date:        dateFlag,
+			Position:    positionFlag,
+			Note:        strings.Join(args, " "),
+			Tags:        tagsFlag,
+			Clear:       clearNoteFlag,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
+		})
+	case "calendar":
+		return checkConfig(ds, &command.CalendarCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			Date:        dateFlag,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
+		})
+	case "search":
+		query, err := parseInterspersedFlags(args)
+		if err != nil {
+			return "", err
+		}
+		return checkConfig(ds, &command.SearchCommand{
 			DataSource: ds,
 			Renderer:   r,
-			Date:       dateFlag,
-			Position:   positionFlag,
-			YesMode:    yesFlag,
+			Query:      strings.Join(query, " "),
+			Tag:        tagsFlag,
+			From:       fromFlag,
+			To:         toFlag,
+			DateFormat: s.dateFormat,
+		})
+	case "stats":
+		return checkConfig(ds, &command.StatsCommand{
+			DataSource:  ds,
+			Renderer:    r,
+			From:        fromFlag,
+			To:          toFlag,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
 		})
+	case "profile":
+		var action, name string
+		if len(args) > 0 {
+			action = args[0]
+		}
+		if len(args) > 1 {
+			name = args[1]
+		}
+		profileCmd := command.ProfileCommand{
+			DataSource: ds,
+			Renderer:   r,
+			Action:     action,
+			Name:       name,
+		}
+		return profileCmd.Execute()
 	case "export":
 		return checkConfig(ds, &command.ExportCommand{
 			DataSource: ds,
@@ -274,6 +789,7 @@ func executeCommand(ds datasource.DataSource, r renderer.Renderer, cmd string, a
 			DataSource: ds,
 			Renderer:   r,
 			File:       fileFlag,
+			Backup:     s.backup,
 		})
 	default:
 		return checkConfig(ds, &command.DayCommand{
@@ -283,6 +799,12 @@ func executeCommand(ds datasource.DataSource, r renderer.Renderer, cmd string, a
 			Month:       monthFlag,
 			History:     histFlag,
 			DefaultDate: defaultDateFlag,
+			Tag:         defaultTagFlag,
+			Budget:      s.budget,
+			DateFormat:  s.dateFormat,
+			Location:    s.location,
+			DayRollover: s.dayRollover,
+			Clock:       s.clock,
 		})
 	}
 }
@@ -305,13 +827,33 @@ func printCommands() {
 	fmt.Println("You can switch the output format of each command by using")
 	fmt.Println("\tCOMMAND --o=[string[terminal|json] OUTPUTFORMAT]")
 	fmt.Println("")
+	fmt.Println("You can run each command as of a given date by using")
+	fmt.Println("\tCOMMAND --now=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("")
+	fmt.Println("You can run each command for a profile other than the active one by using")
+	fmt.Println("\tCOMMAND --profile=[string NAME]")
+	fmt.Println("")
 	fmt.Println("List of Commands:")
 	fmt.Println("")
+	fmt.Println("- init --db=[string PATH] (--w=[float WEIGHT] --h=[float HEIGHT] --a=[float ACTIVITY] --b=[date[dd.mm.yyyy] BIRTHDAY] ...)")
+	fmt.Println("\tSets up the database and optionally the configuration without any prompts, e.g. for scripts")
+	fmt.Println("")
 	fmt.Println("- config")
 	fmt.Println("\tDisplays your current configuration")
 	fmt.Println("")
-	fmt.Println("- config --w=[float WEIGHT] --h=[float HEIGHT] --a=[float ACTIVITY] --b=[date[dd.mm.yyyy] BIRTHDAY], --g=[string[male|female] GENDER] --u=[string[metric|imperial] UNITSYSTEM")
+	fmt.Println("- config --w=[float WEIGHT] --h=[float HEIGHT] --a=[float ACTIVITY] --b=[date[dd.mm.yyyy] BIRTHDAY], --g=[string[male|female] GENDER] --u=[string[metric|imperial] UNITSYSTEM --df=[string[dd.mm.yyyy|mm/dd/yyyy|yyyy-mm-dd] DATEFORMAT] --tz=[string TIMEZONE] --r=[int ROLLOVERHOUR] --budget=[string[daily|weekly] BUDGETMODE] --water=[float WATERTARGET] --formula=[string[harris-benedict|katch-mcardle] FORMULA] --window=[string[START-END] EATINGWINDOW]")
 	fmt.Println("\tOverrides the configuration with the given values, asks for confirmation")
+	fmt.Println("\tThe date format is used for printing dates and for parsing the --date and --birthday flags")
+	fmt.Println("\tThe timezone and the rollover hour (e.g. 4 for 4am) determine on which day new entries land")
+	fmt.Println("\tIn the weekly budget mode, the remaining budget of the week and the allowance per day are shown")
+	fmt.Println("\tWith an eating window (e.g. 12-20), you get a warning if you add an entry outside of it")
+	fmt.Println("\tThe katch-mcardle formula calculates your BMR based on your lean body mass, if your body fat is known")
+	fmt.Println("")
+	fmt.Println("- config --d=[date[dd.mm.yyyy] DATE] --w=[float WEIGHT] ...")
+	fmt.Println("\tAdds a new version of the configuration, which is effective from the given date on")
+	fmt.Println("")
+	fmt.Println("- config --history")
+	fmt.Println("\tDisplays all versions of your configuration")
 	fmt.Println("")
 	fmt.Println("- weight")
 	fmt.Println("\tDisplays your weight timeline")
@@ -319,12 +861,42 @@ func printCommands() {
 	fmt.Println("- weight [float WEIGHT]")
 	fmt.Println("\tAdds the given weight to your weight timeline with date = today")
 	fmt.Println("")
+	fmt.Println("- fast")
+	fmt.Println("\tDisplays your fasting history with durations and streaks")
+	fmt.Println("")
+	fmt.Println("- fast [start|stop]")
+	fmt.Println("\tStarts a fast now / stops the running fast")
+	fmt.Println("")
+	fmt.Println("- measure")
+	fmt.Println("\tDisplays your body measurement timeline")
+	fmt.Println("")
+	fmt.Println("- measure --waist=[float WAIST] --hip=[float HIP] --chest=[float CHEST] --neck=[float NECK] --arm=[float ARM] --thigh=[float THIGH] --fat=[float BODYFAT]")
+	fmt.Println("\tAdds the given measurements (cm or inches) and body fat percentage with date = today")
+	fmt.Println("")
 	fmt.Println("- add [int CALORIES] [string FOOD]")
 	fmt.Println("\tAdds an entry with the given calories and food for today")
 	fmt.Println("")
 	fmt.Println("- add --date=[date[dd.mm.yyyy] DATE] [int CALORIES] [string FOOD]")
 	fmt.Println("\tAdds an entry with the given calories and food for the given date")
 	fmt.Println("")
+	fmt.Println("- water")
+	fmt.Println("\tDisplays the water you drank today compared to your daily target")
+	fmt.Println("")
+	fmt.Println("- water --date=[date[dd.mm.yyyy] DATE] [float AMOUNT]")
+	fmt.Println("\tAdds the given amount of water (ml or fl oz) for today or the given date")
+	fmt.Println("")
+	fmt.Println("- add --tag=[string TAGS] [int CALORIES] [string FOOD]")
+	fmt.Println("\tAdds an entry with the given tags (e.g. cheatday,restaurant)")
+	fmt.Println("")
+	fmt.Println("- note [string NOTE]")
+	fmt.Println("\tSets the note of the current day")
+	fmt.Println("")
+	fmt.Println("- note --date=[date[dd.mm.yyyy] DATE] --position=[int POSITION] --tag=[string TAGS] [string NOTE]")
+	fmt.Println("\tSets the tags and the note of the given day, or of the entry at the given position (1-n)")
+	fmt.Println("")
+	fmt.Println("- note --clear --date=[date[dd.mm.yyyy] DATE] --position=[int POSITION]")
+	fmt.Println("\tRemoves the tags and the note of the given day, or of the entry at the given position (1-n)")
+	fmt.Println("")
 	fmt.Println("- clear")
 	fmt.Println("\tClears the entries for the current day, asks for confirmation")
 	fmt.Println("")
@@ -334,11 +906,65 @@ func printCommands() {
 	fmt.Println("- clear --position=[int POSITION]")
 	fmt.Println("\tClears the entry at the given position (1-n) for the given day, asks for confirmation")
 	fmt.Println("")
+	fmt.Println("- search [string QUERY]")
+	fmt.Println("\tSearches the food of all entries, ignoring case and small typos")
+	fmt.Println("")
+	fmt.Println("- search [string QUERY] --from=[date[dd.mm.yyyy] DATE] --to=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("\tSearches the food of the entries in the given time span, the flags can also be given before the query")
+	fmt.Println("")
+	fmt.Println("- search --tag=[string TAG] [string QUERY]")
+	fmt.Println("\tSearches the entries tagged with the given tag, directly or by their day, the query is optional")
+	fmt.Println("")
+	fmt.Println("- calendar")
+	fmt.Println("\tDisplays the current month as a calendar, colored by deficit (green) and surplus (red)")
+	fmt.Println("")
+	fmt.Println("- calendar --date=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("\tDisplays the month of the given date as a calendar")
+	fmt.Println("")
+	fmt.Println("- stats")
+	fmt.Println("\tDisplays statistics for the last 30 days")
+	fmt.Println("")
+	fmt.Println("- stats --from=[date[dd.mm.yyyy] DATE] --to=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("\tDisplays averages, budget streaks, the most frequent foods and weekday patterns for the given time span")
+	fmt.Println("")
+	fmt.Println("- recalc --from=[date[dd.mm.yyyy] DATE] --to=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("\tRecalculates BMR and AMR of the entries in the given time span using the config and weight of each day")
+	fmt.Println("")
+	fmt.Println("- recalc --dry-run --from=[date[dd.mm.yyyy] DATE] --to=[date[dd.mm.yyyy] DATE]")
+	fmt.Println("\tShows the changes a recalculation would make, without saving them")
+	fmt.Println("")
+	fmt.Println("- profile")
+	fmt.Println("\tDisplays all profiles")
+	fmt.Println("")
+	fmt.Println("- profile add [string NAME]")
+	fmt.Println("\tAdds a new profile with the given name")
+	fmt.Println("")
+	fmt.Println("- profile use [string NAME]")
+	fmt.Println("\tMakes the given profile the active profile")
+	fmt.Println("")
 	fmt.Println("- export > backup.json")
 	fmt.Println("\tExports the database to stdout")
 	fmt.Println("")
 	fmt.Println("- import --f=[string FILENAME]")
 	fmt.Println("\tImports the given file to the database, overwriting all data")
+	fmt.Println("")
+	fmt.Println("- db [encrypt | decrypt]")
+	fmt.Println("\tEncrypts or decrypts the database with a passphrase from CALORIES_PASSPHRASE, CALORIES_PASSPHRASE_FILE or a prompt")
+	fmt.Println("\tEncrypting also encrypts the existing backups of the database")
+	fmt.Println("")
+	fmt.Println("- db [path | set [string DATABASE] | move [string PATH] | compact | check | stats]")
+	fmt.Println("\tShows the database in use, sets another database in the config file, moves the database file,")
+	fmt.Println("\tcompacts it, checks its integrity and shows the number of records and sizes of its buckets or tables")
+	fmt.Println("")
+	fmt.Println("- db [backup | restore [string BACKUP] | list-backups] (--yes)")
+	fmt.Println("\tCreates, restores (default: the newest) and lists backups next to the database file")
+	fmt.Println("\tThe database is backed up before clear, config and import, keeping 5 backups (backups in the config file)")
+	fmt.Println("")
+	fmt.Println("The config file is searched in $XDG_CONFIG_HOME/calories/config.json, ~/.caloriesconf and next to the binary")
+	fmt.Println("It holds the db, the default output, dateFormat and profile and the number of backups, which can be")
+	fmt.Println("overridden with CALORIES_DB, CALORIES_OUTPUT, CALORIES_DATE_FORMAT, CALORIES_PROFILE and CALORIES_BACKUPS")
+	fmt.Println("Use sqlite://[string PATH] as db to store the data in a SQLite database")
+	fmt.Println("Use --db=[string PATH] to use another database for a single command, or --db=:memory: for an in-memory database")
 }
 
 // asciilogo prints the logo in ascii
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/zupzup/calories/datasource"
+	"github.com/zupzup/calories/model"
+)
+
+func TestConfigFlagCount(t *testing.T) {
+	testCases := []struct {
+		description string
+		args        []string
+		expected    int
+	}{
+		{description: "no flags", args: []string{}, expected: 0},
+		{description: "only global flags", args: []string{"--db=calories.db", "--o=json", "--profile=anna", "--yes"}, expected: 0},
+		{description: "config flags", args: []string{"--db=calories.db", "--w=85", "--h=185", "--a=1.3", "--b=08.08.1985", "--budget=weekly"}, expected: 5},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			flags := flag.NewFlagSet("config", flag.ContinueOnError)
+			flags.SetOutput(ioutil.Discard)
+			commandFlag.VisitAll(func(f *flag.Flag) {
+				flags.Var(f.Value, f.Name, f.Usage)
+			})
+			if err := flags.Parse(tc.args); err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			if res := configFlagCount(flags); res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestParseInterspersedFlags(t *testing.T) {
+	defer func() {
+		fromFlag = ""
+		toFlag = ""
+	}()
+	res, err := parseInterspersedFlags([]string{"pizza", "--from=01.01.2017", "margherita", "--to=31.01.2017"})
+	if err != nil || fmt.Sprint(res) != "[pizza margherita]" || fromFlag != "01.01.2017" || toFlag != "31.01.2017" {
+		t.Errorf("Error, actual: %v %v %s %s expected: %v", res, err, fromFlag, toFlag, "the query and both dates")
+		return
+	}
+	res, err = parseInterspersedFlags([]string{"pizza", "--", "--from=02.01.2017"})
+	if err != nil || fmt.Sprint(res) != "[pizza --from=02.01.2017]" || fromFlag != "01.01.2017" {
+		t.Errorf("Error, actual: %v %v expected: %v", res, err, "the arguments after -- as query")
+		return
+	}
+	expected := "flag provided but not defined: -unknown, please see 'calories  --help'"
+	if _, err = parseInterspersedFlags([]string{"pizza", "--unknown"}); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestFetchSettingsDateFormat(t *testing.T) {
+	ds := &datasource.MemoryDataSource{}
+	if _, err := ds.Setup(datasource.MemoryConnection); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	s, err := fetchSettings(ds, "", "", "mm/dd/yyyy")
+	if err != nil || s.dateFormat != "mm/dd/yyyy" {
+		t.Errorf("Error, actual: %v %v expected: %v", s, err, "the date format of the config file without a config")
+		return
+	}
+	if err = ds.SetConfig(&model.Config{Height: 185.0, Activity: 1.3, Gender: "male", UnitSystem: "metric", DateFormat: "yyyy-mm-dd"}); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	testCases := []struct {
+		description    string
+		flagDateFormat string
+		fileDateFormat string
+		expected       string
+	}{
+		{description: "config over config file", fileDateFormat: "mm/dd/yyyy", expected: "yyyy-mm-dd"},
+		{description: "flag over config", flagDateFormat: "dd.mm.yyyy", fileDateFormat: "mm/dd/yyyy", expected: "dd.mm.yyyy"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			s, err := fetchSettings(ds, "", tc.flagDateFormat, tc.fileDateFormat)
+			if err != nil || s.dateFormat != tc.expected {
+				t.Errorf("Error, actual: %v %v expected: %v", s, err, tc.expected)
+				return
+			}
+		})
+	}
+	expected := "wrong date format: yyyy/mm/dd, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd"
+	if _, err = fetchSettings(ds, "", "yyyy/mm/dd", ""); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestResolveDateFormat(t *testing.T) {
+	if res := resolveDateFormat("", "", ""); res != "dd.mm.yyyy" {
+		t.Errorf("Error, actual: %v expected: %v", res, "dd.mm.yyyy")
+		return
+	}
+	if res := resolveDateFormat("", "invalid", "mm/dd/yyyy"); res != "mm/dd/yyyy" {
+		t.Errorf("Error, actual: %v expected: %v", res, "mm/dd/yyyy")
+		return
+	}
+}
@@ -2,6 +2,7 @@ package mock
 
 import (
 	"github.com/zupzup/calories/model"
+	"time"
 )
 
 // DataSource is a mocked out datasource
@@ -15,18 +16,66 @@ func (d *DataSource) Setup(connection string) (func() error, error) {
 	return v.(func() error), err
 }
 
+// AddProfile Mock
+func (d *DataSource) AddProfile(name string) error {
+	_, err := d.Expectations.Return("AddProfile")
+	return err
+}
+
+// FetchProfiles Mock
+func (d *DataSource) FetchProfiles() ([]model.Profile, error) {
+	v, err := d.Expectations.Return("FetchProfiles")
+	return v.([]model.Profile), err
+}
+
+// CurrentProfile Mock
+func (d *DataSource) CurrentProfile() (*model.Profile, error) {
+	v, err := d.Expectations.Return("CurrentProfile")
+	return v.(*model.Profile), err
+}
+
+// SelectProfile Mock
+func (d *DataSource) SelectProfile(name string) error {
+	_, err := d.Expectations.Return("SelectProfile")
+	return err
+}
+
+// UseProfile Mock
+func (d *DataSource) UseProfile(name string) error {
+	_, err := d.Expectations.Return("UseProfile")
+	return err
+}
+
 // SetConfig Mock
 func (d *DataSource) SetConfig(*model.Config) error {
 	_, err := d.Expectations.Return("SetConfig")
 	return err
 }
 
+// SetConfigFromImport Mock
+func (d *DataSource) SetConfigFromImport(*model.Config) error {
+	_, err := d.Expectations.Return("SetConfigFromImport")
+	return err
+}
+
 // FetchConfig Mock
 func (d *DataSource) FetchConfig() (*model.Config, error) {
 	v, err := d.Expectations.Return("FetchConfig")
 	return v.(*model.Config), err
 }
 
+// FetchConfigForDate Mock
+func (d *DataSource) FetchConfigForDate(date time.Time) (*model.Config, error) {
+	v, err := d.Expectations.Return("FetchConfigForDate")
+	return v.(*model.Config), err
+}
+
+// FetchConfigHistory Mock
+func (d *DataSource) FetchConfigHistory() ([]model.Config, error) {
+	v, err := d.Expectations.Return("FetchConfigHistory")
+	return v.([]model.Config), err
+}
+
 // AddWeight Mock
 func (d *DataSource) AddWeight(weight float64) error {
 	_, err := d.Expectations.Return("AddWeight")
@@ -39,6 +88,12 @@ func (d *DataSource) CurrentWeight() (*model.Weight, error) {
 	return v.(*model.Weight), err
 }
 
+// FetchWeightForDate Mock
+func (d *DataSource) FetchWeightForDate(date time.Time) (*model.Weight, error) {
+	v, err := d.Expectations.Return("FetchWeightForDate")
+	return v.(*model.Weight), err
+}
+
 // FetchWeights Mock
 func (d *DataSource) FetchWeights() ([]model.Weight, error) {
 	v, err := d.Expectations.Return("FetchWeights")
@@ -46,17 +101,29 @@ func (d *DataSource) FetchWeights() ([]model.Weight, error) {
 }
 
 // AddEntry Mock
-func (d *DataSource) AddEntry(entryDate string, calories int, food string) error {
+func (d *DataSource) AddEntry(entryDate string, calories int, food string, tags []string) error {
 	_, err := d.Expectations.Return("AddEntry")
 	return err
 }
 
+// UpdateEntry Mock
+func (d *DataSource) UpdateEntry(entry *model.Entry) error {
+	_, err := d.Expectations.Return("UpdateEntry")
+	return err
+}
+
 // FetchEntries Mock
 func (d *DataSource) FetchEntries(entryDate string) (model.Entries, error) {
 	v, err := d.Expectations.Return("FetchEntries")
 	return v.(model.Entries), err
 }
 
+// SearchEntries Mock
+func (d *DataSource) SearchEntries(query, tag string, from, to time.Time) (model.Entries, error) {
+	v, err := d.Expectations.Return("SearchEntries")
+	return v.(model.Entries), err
+}
+
 // FetchAllEntries Mock
 func (d *DataSource) FetchAllEntries() (model.Entries, error) {
 	v, err := d.Expectations.Return("FetchAllEntries")
@@ -75,6 +142,78 @@ func (d *DataSource) RemoveEntry(entryDate string, id int) error {
 	return err
 }
 
+// SetDayNote Mock
+func (d *DataSource) SetDayNote(note *model.DayNote) error {
+	_, err := d.Expectations.Return("SetDayNote")
+	return err
+}
+
+// FetchDayNote Mock
+func (d *DataSource) FetchDayNote(entryDate string) (*model.DayNote, error) {
+	v, err := d.Expectations.Return("FetchDayNote")
+	return v.(*model.DayNote), err
+}
+
+// FetchDayNotes Mock
+func (d *DataSource) FetchDayNotes() ([]model.DayNote, error) {
+	v, err := d.Expectations.Return("FetchDayNotes")
+	return v.([]model.DayNote), err
+}
+
+// AddWater Mock
+func (d *DataSource) AddWater(entryDate string, amount float64) error {
+	_, err := d.Expectations.Return("AddWater")
+	return err
+}
+
+// FetchWater Mock
+func (d *DataSource) FetchWater(entryDate string) (*model.DayWater, error) {
+	v, err := d.Expectations.Return("FetchWater")
+	return v.(*model.DayWater), err
+}
+
+// FetchAllWater Mock
+func (d *DataSource) FetchAllWater() ([]model.Water, error) {
+	v, err := d.Expectations.Return("FetchAllWater")
+	return v.([]model.Water), err
+}
+
+// AddMeasurement Mock
+func (d *DataSource) AddMeasurement(measurement *model.Measurement) error {
+	_, err := d.Expectations.Return("AddMeasurement")
+	return err
+}
+
+// FetchMeasurements Mock
+func (d *DataSource) FetchMeasurements() ([]model.Measurement, error) {
+	v, err := d.Expectations.Return("FetchMeasurements")
+	return v.([]model.Measurement), err
+}
+
+// FetchBodyFatForDate Mock
+func (d *DataSource) FetchBodyFatForDate(date time.Time) (float64, error) {
+	v, err := d.Expectations.Return("FetchBodyFatForDate")
+	return v.(float64), err
+}
+
+// StartFast Mock
+func (d *DataSource) StartFast(start time.Time) error {
+	_, err := d.Expectations.Return("StartFast")
+	return err
+}
+
+// StopFast Mock
+func (d *DataSource) StopFast(end time.Time) (*model.Fast, error) {
+	v, err := d.Expectations.Return("StopFast")
+	return v.(*model.Fast), err
+}
+
+// FetchFasts Mock
+func (d *DataSource) FetchFasts() ([]model.Fast, error) {
+	v, err := d.Expectations.Return("FetchFasts")
+	return v.([]model.Fast), err
+}
+
 // Import Mock
 func (d *DataSource) Import(data *model.ImpEx) error {
 	_, err := d.Expectations.Return("Import")
@@ -86,3 +225,9 @@ func (d *DataSource) Export() (*model.ImpEx, error) {
 	v, err := d.Expectations.Return("Export")
 	return v.(*model.ImpEx), err
 }
+
+// Backup Mock
+func (d *DataSource) Backup(path string) error {
+	_, err := d.Expectations.Return("Backup")
+	return err
+}
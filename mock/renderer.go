@@ -27,17 +27,22 @@ func (r *Renderer) AddWeight(weight float64, config *model.Config) (string, erro
 }
 
 // Config Mock
-func (r *Renderer) Config(config *model.Config, weight *model.Weight, amr, bmr float64, age int) (string, error) {
+func (r *Renderer) Config(config *model.Config, weight *model.Weight, composition *model.BodyComposition, amr, bmr float64, age int) (string, error) {
+	return r.Expected, r.Err
+}
+
+// ConfigHistory Mock
+func (r *Renderer) ConfigHistory(configs []model.Config) (string, error) {
 	return r.Expected, r.Err
 }
 
 // Days Mock
-func (r *Renderer) Days(days model.Days, from, to time.Time) (string, error) {
+func (r *Renderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
 	return r.Expected, r.Err
 }
 
 // AddEntry Mock
-func (r *Renderer) AddEntry(date string, calories int, food string) (string, error) {
+func (r *Renderer) AddEntry(date string, calories int, food, warning string) (string, error) {
 	return r.Expected, r.Err
 }
 
@@ -55,3 +60,133 @@ func (r *Renderer) ClearEntry(date string, entry *model.Entry) (string, error) {
 func (r *Renderer) Import(fileName string, numEntries, numWeights int) (string, error) {
 	return r.Expected, r.Err
 }
+
+// Recalc Mock
+func (r *Renderer) Recalc(recalculations []model.Recalculation, dryRun bool) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Stats Mock
+func (r *Renderer) Stats(stats *model.Stats) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Calendar Mock
+func (r *Renderer) Calendar(days model.Days, month time.Time) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Search Mock
+func (r *Renderer) Search(query string, entries model.Entries) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Note Mock
+func (r *Renderer) Note(date string, tags []string, note string, entry *model.Entry) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Water Mock
+func (r *Renderer) Water(date string, water *model.DayWater) (string, error) {
+	return r.Expected, r.Err
+}
+
+// AddWater Mock
+func (r *Renderer) AddWater(date string, amount float64, water *model.DayWater) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Measurements Mock
+func (r *Renderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	return r.Expected, r.Err
+}
+
+// AddMeasurement Mock
+func (r *Renderer) AddMeasurement(measurement *model.Measurement, config *model.Config) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Fasting Mock
+func (r *Renderer) Fasting(history *model.FastingHistory) (string, error) {
+	return r.Expected, r.Err
+}
+
+// StartFast Mock
+func (r *Renderer) StartFast(fast *model.Fast) (string, error) {
+	return r.Expected, r.Err
+}
+
+// StopFast Mock
+func (r *Renderer) StopFast(fast *model.Fast, goal float64) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Profiles Mock
+func (r *Renderer) Profiles(profiles []model.Profile) (string, error) {
+	return r.Expected, r.Err
+}
+
+// AddProfile Mock
+func (r *Renderer) AddProfile(name string) (string, error) {
+	return r.Expected, r.Err
+}
+
+// UseProfile Mock
+func (r *Renderer) UseProfile(name string) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Encryption Mock
+func (r *Renderer) Encryption(path string, encrypted bool) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Backup Mock
+func (r *Renderer) Backup(backup *model.Backup) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Restore Mock
+func (r *Renderer) Restore(backup *model.Backup) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Backups Mock
+func (r *Renderer) Backups(backups []model.Backup) (string, error) {
+	return r.Expected, r.Err
+}
+
+// DBPath Mock
+func (r *Renderer) DBPath(connection, configFile string) (string, error) {
+	return r.Expected, r.Err
+}
+
+// SetDB Mock
+func (r *Renderer) SetDB(connection, configFile string) (string, error) {
+	return r.Expected, r.Err
+}
+
+// MoveDB Mock
+func (r *Renderer) MoveDB(from, to string) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Compaction Mock
+func (r *Renderer) Compaction(compaction *model.Compaction) (string, error) {
+	return r.Expected, r.Err
+}
+
+// IntegrityCheck Mock
+func (r *Renderer) IntegrityCheck(check *model.IntegrityCheck) (string, error) {
+	return r.Expected, r.Err
+}
+
+// DBStats Mock
+func (r *Renderer) DBStats(stats *model.DBStats) (string, error) {
+	return r.Expected, r.Err
+}
+
+// Init Mock
+func (r *Renderer) Init(connection, configFile string, configured bool) (string, error) {
+	return r.Expected, r.Err
+}
@@ -0,0 +1,12 @@
+package model
+
+import (
+	"time"
+)
+
+// Backup is a backup of the database file, which was created at the given time
+type Backup struct {
+	Path    string    `json:"path"`
+	Created time.Time `json:"created"`
+	Size    int64     `json:"size"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// WeeklyBudget is the calorie budget of a week, which is the sum of the AMR of each day of the week
+// The daily allowance is the budget, which was left at the beginning of the current day,
+// spread over the remaining days of the week including the current day
+type WeeklyBudget struct {
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	Target         float64   `json:"target"`
+	Total          float64   `json:"total"`
+	Used           int       `json:"used"`
+	UsedToday      int       `json:"usedToday"`
+	Remaining      float64   `json:"remaining"`
+	DaysLeft       int       `json:"daysLeft"`
+	DailyAllowance float64   `json:"dailyAllowance"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// BodyComposition holds the BMI and the healthy weight range for the configured height in kg
+// LeanMass (kg) and FFMI are only set, if the body fat percentage is known
+type BodyComposition struct {
+	BMI              float64 `json:"bmi"`
+	BMICategory      string  `json:"bmiCategory"`
+	HealthyWeightMin float64 `json:"healthyWeightMin"`
+	HealthyWeightMax float64 `json:"healthyWeightMax"`
+	BodyFat          float64 `json:"bodyFat,omitempty"`
+	LeanMass         float64 `json:"leanMass,omitempty"`
+	FFMI             float64 `json:"ffmi,omitempty"`
+}
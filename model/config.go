@@ -4,13 +4,23 @@ import (
 	"time"
 )
 
-// Config represents the configuration and is unique and holds data relevant for calculating the
-// metabolic rate of the user
+// Config represents the configuration of a profile and holds data relevant for calculating the
+// metabolic rate of the user. Every change creates a new version of the config, which is
+// effective from the given date on
 type Config struct {
-	ID         int       `storm:"id,increment" json:"id"`
-	Height     float64   `json:"height"`
-	Activity   float64   `json:"activity"`
-	Birthday   time.Time `json:"birthday"`
-	Gender     string    `json:"gender"`
-	UnitSystem string    `json:"unitSystem"`
+	ID           int       `storm:"id,increment" json:"id"`
+	ProfileID    int       `json:"profileId"`
+	Effective    time.Time `json:"effective"`
+	Height       float64   `json:"height"`
+	Activity     float64   `json:"activity"`
+	Birthday     time.Time `json:"birthday"`
+	Gender       string    `json:"gender"`
+	UnitSystem   string    `json:"unitSystem"`
+	DateFormat   string    `json:"dateFormat"`
+	Timezone     string    `json:"timezone"`
+	DayRollover  int       `json:"dayRollover"`
+	Budget       string    `json:"budget"`
+	WaterTarget  float64   `json:"waterTarget"`
+	Formula      string    `json:"formula"`
+	EatingWindow string    `json:"eatingWindow"`
 }
@@ -0,0 +1,29 @@
+package model
+
+// Compaction is the size of the database file before and after compacting it
+type Compaction struct {
+	Path   string `json:"path"`
+	Before int64  `json:"before"`
+	After  int64  `json:"after"`
+}
+
+// IntegrityCheck is the result of checking the database file, Problems is empty, if it is ok
+type IntegrityCheck struct {
+	Path     string   `json:"path"`
+	Problems []string `json:"problems"`
+}
+
+// DBStats is the size of the database file and the statistics of its buckets or tables
+type DBStats struct {
+	Path    string        `json:"path"`
+	Size    int64         `json:"size"`
+	Buckets []BucketStats `json:"buckets"`
+}
+
+// BucketStats is the number of records and the size in bytes of a bucket or table
+// The size is only known for Bolt databases
+type BucketStats struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	Size    int64  `json:"size"`
+}
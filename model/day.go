@@ -6,10 +6,13 @@ import (
 
 // Day is an actual day with all it's entries and the
 // calories which have been used for the day
+// The note with the tags of the day and the water are only set, if there are any
 type Day struct {
 	Entries Entries   `json:"entries"`
 	Used    int       `json:"used"`
 	Date    time.Time `json:"date"`
+	Note    *DayNote  `json:"note,omitempty"`
+	Water   *DayWater `json:"water,omitempty"`
 }
 
 // Days is Custom slice type for a list of days
@@ -18,3 +21,12 @@ type Days []*Day
 func (days Days) Len() int           { return len(days) }
 func (days Days) Less(i, j int) bool { return days[i].Date.Before(days[j].Date) }
 func (days Days) Swap(i, j int)      { days[i], days[j] = days[j], days[i] }
+
+// AMR returns the AMR of the day, which is the same for all entries of a day,
+// or 0, if there are no entries
+func (d *Day) AMR() float64 {
+	if len(d.Entries) > 0 {
+		return d.Entries[0].AMR
+	}
+	return 0
+}
@@ -0,0 +1,15 @@
+package model
+
+// DayNote holds the tags and the note of a whole day
+type DayNote struct {
+	ID        int      `storm:"id,increment" json:"id"`
+	ProfileID int      `json:"profileId"`
+	Date      string   `json:"date"`
+	Tags      []string `json:"tags,omitempty"`
+	Note      string   `json:"note,omitempty"`
+}
+
+// IsEmpty returns true, if there are neither tags nor a note
+func (n *DayNote) IsEmpty() bool {
+	return len(n.Tags) == 0 && n.Note == ""
+}
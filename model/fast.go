@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+// Fast is a fasting period, the end is zero while the fast is running
+type Fast struct {
+	ID        int       `storm:"id,increment" json:"id"`
+	ProfileID int       `json:"profileId"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// IsRunning returns true, if the fast has not been stopped yet
+func (f *Fast) IsRunning() bool {
+	return f.End.IsZero()
+}
+
+// Hours returns the duration of the fast in hours, using the given time
+// as the end for a running fast
+func (f *Fast) Hours(now time.Time) float64 {
+	end := f.End
+	if f.IsRunning() {
+		end = now
+	}
+	return end.Sub(f.Start).Hours()
+}
+
+// FastingHistory holds all completed fasts, the running fast and the streaks of days
+// on which a fast reaching the goal (in hours) has been completed
+type FastingHistory struct {
+	Fasts         []Fast  `json:"fasts"`
+	Running       *Fast   `json:"running,omitempty"`
+	RunningHours  float64 `json:"runningHours,omitempty"`
+	Goal          float64 `json:"goal"`
+	AverageHours  float64 `json:"averageHours"`
+	CurrentStreak int     `json:"currentStreak"`
+	LongestStreak int     `json:"longestStreak"`
+}
@@ -3,7 +3,12 @@ package model
 // ImpEx is the data structure for importing and exporting data to a and from
 // the application
 type ImpEx struct {
-	Config  *Config  `json:"config"`
-	Entries Entries  `json:"entries"`
-	Weights []Weight `json:"weights"`
+	Config        *Config       `json:"config"`
+	ConfigHistory []Config      `json:"configHistory,omitempty"`
+	Entries       Entries       `json:"entries"`
+	Weights       []Weight      `json:"weights"`
+	DayNotes      []DayNote     `json:"dayNotes,omitempty"`
+	Water         []Water       `json:"water,omitempty"`
+	Measurements  []Measurement `json:"measurements,omitempty"`
+	Fasts         []Fast        `json:"fasts,omitempty"`
 }
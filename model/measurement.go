@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// Measurement holds body measurements in cm and the body fat in percent
+// Values, which have not been measured are 0
+type Measurement struct {
+	ID        int       `storm:"id,increment" json:"id"`
+	ProfileID int       `json:"profileId"`
+	Created   time.Time `json:"created"`
+	Waist     float64   `json:"waist,omitempty"`
+	Hip       float64   `json:"hip,omitempty"`
+	Chest     float64   `json:"chest,omitempty"`
+	Neck      float64   `json:"neck,omitempty"`
+	Arm       float64   `json:"arm,omitempty"`
+	Thigh     float64   `json:"thigh,omitempty"`
+	BodyFat   float64   `json:"bodyFat,omitempty"`
+}
+
+// IsEmpty returns true, if nothing has been measured
+func (m *Measurement) IsEmpty() bool {
+	return m.Waist == 0 && m.Hip == 0 && m.Chest == 0 && m.Neck == 0 && m.Arm == 0 && m.Thigh == 0 && m.BodyFat == 0
+}
@@ -0,0 +1,9 @@
+package model
+
+// Profile represents a user of the application, who has their own config,
+// entries and weights. The active profile is used, if no profile is chosen explicitly
+type Profile struct {
+	ID     int    `storm:"id,increment" json:"id"`
+	Name   string `storm:"unique" json:"name"`
+	Active bool   `json:"active"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// Recalculation is the result of recalculating the metabolic rates of an entry
+// and holds the previous and the recalculated rates
+type Recalculation struct {
+	Entry  Entry   `json:"entry"`
+	OldBMR float64 `json:"oldBmr"`
+	OldAMR float64 `json:"oldAmr"`
+	NewBMR float64 `json:"newBmr"`
+	NewAMR float64 `json:"newAmr"`
+}
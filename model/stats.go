@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// Stats are the statistics for a range of days
+// Averages are calculated over the days with entries
+type Stats struct {
+	From                     time.Time      `json:"from"`
+	To                       time.Time      `json:"to"`
+	LoggedDays               int            `json:"loggedDays"`
+	AverageIntake            float64        `json:"averageIntake"`
+	AverageDeficit           float64        `json:"averageDeficit"`
+	DaysUnderBudget          int            `json:"daysUnderBudget"`
+	DaysOverBudget           int            `json:"daysOverBudget"`
+	LongestUnderBudgetStreak int            `json:"longestUnderBudgetStreak"`
+	CurrentLoggingStreak     int            `json:"currentLoggingStreak"`
+	TopFoods                 []FoodCount    `json:"topFoods"`
+	Weekdays                 []WeekdayStats `json:"weekdays"`
+}
+
+// FoodCount is the number of entries for a food and the calories of all these entries
+type FoodCount struct {
+	Food     string `json:"food"`
+	Count    int    `json:"count"`
+	Calories int    `json:"calories"`
+}
+
+// WeekdayStats are the averages for all days with entries on a weekday
+type WeekdayStats struct {
+	Weekday        string  `json:"weekday"`
+	Days           int     `json:"days"`
+	AverageIntake  float64 `json:"averageIntake"`
+	AverageDeficit float64 `json:"averageDeficit"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// Water is an amount of water in ml, which has been drunk on the given entry date
+type Water struct {
+	ID        int       `storm:"id,increment" json:"id"`
+	ProfileID int       `json:"profileId"`
+	Created   time.Time `json:"created"`
+	EntryDate string    `json:"entryDate"`
+	Amount    float64   `json:"amount"`
+}
+
+// DayWater is the water drunk on a day and the daily target in ml, the unit system
+// is the one used for displaying the amounts
+type DayWater struct {
+	Amount     float64 `json:"amount"`
+	Target     float64 `json:"target"`
+	UnitSystem string  `json:"unitSystem"`
+}
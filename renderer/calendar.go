@@ -0,0 +1,63 @@
+package renderer
+
+import (
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// status of a calendar day
+const (
+	statusDeficit = "deficit"
+	statusSurplus = "surplus"
+	statusNone    = "none"
+)
+
+// calendarDay is a day of a calendar month with its status, which is deficit,
+// if the used calories are not above the AMR, surplus if they are and none,
+// if there are no entries
+type calendarDay struct {
+	Date   time.Time `json:"date"`
+	Used   int       `json:"used"`
+	AMR    float64   `json:"amr"`
+	Status string    `json:"status"`
+}
+
+// calendarWeek is a week row of a calendar month, the deficit is the sum over
+// all days with entries in this row
+type calendarWeek struct {
+	Days    []calendarDay `json:"days"`
+	Deficit float64       `json:"deficit"`
+	Logged  int           `json:"logged"`
+}
+
+// calendarWeeks returns the weeks of the month of the given date, starting on monday
+func calendarWeeks(days model.Days, month time.Time) []calendarWeek {
+	byDate := map[string]*model.Day{}
+	for _, day := range days {
+		byDate[day.Date.Format(util.DateFormat)] = day
+	}
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	weeks := []calendarWeek{}
+	var week *calendarWeek
+	for date := first; date.Month() == first.Month(); date = date.AddDate(0, 0, 1) {
+		if week == nil || date.Weekday() == time.Monday {
+			weeks = append(weeks, calendarWeek{Days: []calendarDay{}})
+			week = &weeks[len(weeks)-1]
+		}
+		cd := calendarDay{Date: date, Status: statusNone}
+		if day, ok := byDate[date.Format(util.DateFormat)]; ok {
+			cd.Used = day.Used
+			cd.AMR = day.AMR()
+			cd.Status = statusDeficit
+			if float64(cd.Used) > cd.AMR {
+				cd.Status = statusSurplus
+			}
+			week.Deficit += cd.AMR - float64(cd.Used)
+			week.Logged++
+		}
+		week.Days = append(week.Days, cd)
+	}
+	return weeks
+}
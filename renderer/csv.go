@@ -0,0 +1,415 @@
+package renderer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// CSVRenderer is the renderer for CSV output, e.g. for spreadsheets
+// Every output is rendered as a CSV table with a header, success messages as a single message column
+// and errors as plain text. Values are written without their unit, which is written in a separate
+// unit column
+type CSVRenderer struct {
+	DateFormat string
+}
+
+// Error renders the given error as plain text
+func (r *CSVRenderer) Error(err error) (string, error) {
+	return err.Error(), nil
+}
+
+// WeightHistory renders all weights with their date, value and unit in the unit system of the config
+func (r *CSVRenderer) WeightHistory(weights []model.Weight, config *model.Config) (string, error) {
+	records := [][]string{}
+	for _, weight := range weights {
+		records = append(records, append([]string{util.FormatDate(weight.Created, r.DateFormat)}, csvWeight(config.UnitSystem, weight.Weight)...))
+	}
+	return writeCSV([]string{"date", "weight", "unit"}, records)
+}
+
+// Config renders the given configuration with weight, body composition, amr and bmr as keys, values and units
+func (r *CSVRenderer) Config(config *model.Config, weight *model.Weight, composition *model.BodyComposition, amr, bmr float64, age int) (string, error) {
+	records := [][]string{
+		append([]string{"weight"}, csvWeight(config.UnitSystem, weight.Weight)...),
+		append([]string{"height"}, csvLength(config.UnitSystem, config.Height)...),
+		{"bmi", fmt.Sprintf("%.1f", composition.BMI), ""},
+		{"bmiCategory", composition.BMICategory, ""},
+		append([]string{"healthyWeightMin"}, csvWeight(config.UnitSystem, composition.HealthyWeightMin)...),
+		append([]string{"healthyWeightMax"}, csvWeight(config.UnitSystem, composition.HealthyWeightMax)...),
+	}
+	if composition.BodyFat > 0 {
+		records = append(records,
+			[]string{"bodyFat", fmt.Sprintf("%.1f", composition.BodyFat), "%"},
+			append([]string{"leanMass"}, csvWeight(config.UnitSystem, composition.LeanMass)...),
+			[]string{"ffmi", fmt.Sprintf("%.1f", composition.FFMI), ""},
+		)
+	}
+	records = append(records,
+		[]string{"activity", fmt.Sprint(config.Activity), ""},
+		[]string{"birthday", util.FormatDate(config.Birthday, r.DateFormat), ""},
+		[]string{"age", fmt.Sprintf("%d", age), "years"},
+		[]string{"gender", config.Gender, ""},
+		[]string{"unitSystem", config.UnitSystem, ""},
+		[]string{"dateFormat", util.NormalizeDateFormat(config.DateFormat), ""},
+		[]string{"timezone", timezoneName(config.Timezone), ""},
+		[]string{"dayRollover", fmt.Sprintf("%d", config.DayRollover), "hour"},
+		[]string{"budget", budgetMode(config.Budget), ""},
+		append([]string{"waterTarget"}, csvWater(config.UnitSystem, waterTarget(config.WaterTarget))...),
+		[]string{"eatingWindow", util.FormatEatingWindow(config.EatingWindow), ""},
+		[]string{"formula", formula(config.Formula), ""},
+		[]string{"amr", fmt.Sprintf("%.0f", amr), "calories"},
+		[]string{"bmr", fmt.Sprintf("%.0f", bmr), "calories"},
+	)
+	return writeCSV([]string{"key", "value", "unit"}, records)
+}
+
+// Measurements renders one row for every measured value of the given measurements with its date,
+// name, value and unit in the unit system of the config
+func (r *CSVRenderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	records := [][]string{}
+	for _, m := range measurements {
+		date := util.FormatDate(m.Created, r.DateFormat)
+		lengths := []struct {
+			name  string
+			value float64
+		}{
+			{"waist", m.Waist},
+			{"hip", m.Hip},
+			{"chest", m.Chest},
+			{"neck", m.Neck},
+			{"arm", m.Arm},
+			{"thigh", m.Thigh},
+		}
+		for _, l := range lengths {
+			if l.value > 0 {
+				records = append(records, append([]string{date, l.name}, csvLength(config.UnitSystem, l.value)...))
+			}
+		}
+		if m.BodyFat > 0 {
+			records = append(records, []string{date, "bodyFat", fmt.Sprintf("%.1f", m.BodyFat), "%"})
+		}
+	}
+	return writeCSV([]string{"date", "measurement", "value", "unit"}, records)
+}
+
+// Search renders one row for every found entry with its date, food and calories
+func (r *CSVRenderer) Search(query string, entries model.Entries) (string, error) {
+	records := [][]string{}
+	for _, entry := range entries {
+		records = append(records, []string{util.DisplayDate(entry.EntryDate, r.DateFormat), entry.Food, fmt.Sprintf("%d", entry.Calories)})
+	}
+	return writeCSV([]string{"date", "food", "calories"}, records)
+}
+
+// Days renders one row for every entry of the given days with its date, food, calories and AMR
+func (r *CSVRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
+	records := [][]string{}
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			records = append(records, []string{util.FormatDate(day.Date, r.DateFormat), entry.Food, fmt.Sprintf("%d", entry.Calories), fmt.Sprintf("%.0f", entry.AMR)})
+		}
+	}
+	return writeCSV([]string{"date", "food", "calories", "amr"}, records)
+}
+
+// AddWeight renders a success message after setting the weight
+func (r *CSVRenderer) AddWeight(weight float64, config *model.Config) (string, error) {
+	return csvMessage(fmt.Sprintf("Set weight: %s", util.WeightUnit(config.UnitSystem, weight)))
+}
+
+// ConfigHistory renders one row for every config version with its effective date, height, activity,
+// gender and unit system, the initial config has an empty effective date
+func (r *CSVRenderer) ConfigHistory(configs []model.Config) (string, error) {
+	records := [][]string{}
+	for _, config := range configs {
+		effective := ""
+		if !config.Effective.IsZero() {
+			effective = util.FormatDate(config.Effective, r.DateFormat)
+		}
+		records = append(records, append(append([]string{effective}, csvLength(config.UnitSystem, config.Height)...), fmt.Sprint(config.Activity), config.Gender, config.UnitSystem))
+	}
+	return writeCSV([]string{"effective", "height", "unit", "activity", "gender", "unitSystem"}, records)
+}
+
+// AddEntry renders a success message and the warning, if there is one
+func (r *CSVRenderer) AddEntry(date string, calories int, food, warning string) (string, error) {
+	return writeCSV([]string{"message", "warning"}, [][]string{
+		{fmt.Sprintf("Added Entry for %s with %d calories (%s)", util.DisplayDate(date, r.DateFormat), calories, food), warning},
+	})
+}
+
+// ClearEntries renders a success message after clearing all entries of a date
+func (r *CSVRenderer) ClearEntries(date string) (string, error) {
+	return csvMessage(fmt.Sprintf("Cleared all entries for %s", util.DisplayDate(date, r.DateFormat)))
+}
+
+// ClearEntry renders a success message after clearing an entry
+func (r *CSVRenderer) ClearEntry(date string, entry *model.Entry) (string, error) {
+	return csvMessage(fmt.Sprintf("Cleared entry %d %s for %s", entry.Calories, entry.Food, util.DisplayDate(date, r.DateFormat)))
+}
+
+// Import renders a success message after importing data
+func (r *CSVRenderer) Import(fileName string, numEntries, numWeights int) (string, error) {
+	return csvMessage(fmt.Sprintf("Imported data from %s with %d entries and %d weights", fileName, numEntries, numWeights))
+}
+
+// Recalc renders one row for every recalculated entry with its old and new BMR and AMR and whether
+// it was only a dry run
+func (r *CSVRenderer) Recalc(recalculations []model.Recalculation, dryRun bool) (string, error) {
+	records := [][]string{}
+	for _, rec := range recalculations {
+		records = append(records, []string{
+			util.DisplayDate(rec.Entry.EntryDate, r.DateFormat),
+			rec.Entry.Food,
+			fmt.Sprintf("%d", rec.Entry.Calories),
+			fmt.Sprintf("%.0f", rec.OldBMR),
+			fmt.Sprintf("%.0f", rec.NewBMR),
+			fmt.Sprintf("%.0f", rec.OldAMR),
+			fmt.Sprintf("%.0f", rec.NewAMR),
+			fmt.Sprint(dryRun),
+		})
+	}
+	return writeCSV([]string{"date", "food", "calories", "oldBmr", "newBmr", "oldAmr", "newAmr", "dryRun"}, records)
+}
+
+// Stats renders the statistics in long format, one row per value with its section, the name of the
+// food or weekday it belongs to, its key and value
+func (r *CSVRenderer) Stats(stats *model.Stats) (string, error) {
+	records := [][]string{
+		{"summary", "", "from", util.FormatDate(stats.From, r.DateFormat)},
+		{"summary", "", "to", util.FormatDate(stats.To, r.DateFormat)},
+		{"summary", "", "loggedDays", fmt.Sprintf("%d", stats.LoggedDays)},
+	}
+	if stats.LoggedDays > 0 {
+		records = append(records,
+			[]string{"summary", "", "averageIntake", fmt.Sprintf("%.0f", stats.AverageIntake)},
+			[]string{"summary", "", "averageDeficit", fmt.Sprintf("%.0f", stats.AverageDeficit)},
+			[]string{"summary", "", "daysUnderBudget", fmt.Sprintf("%d", stats.DaysUnderBudget)},
+			[]string{"summary", "", "daysOverBudget", fmt.Sprintf("%d", stats.DaysOverBudget)},
+			[]string{"summary", "", "longestUnderBudgetStreak", fmt.Sprintf("%d", stats.LongestUnderBudgetStreak)},
+			[]string{"summary", "", "currentLoggingStreak", fmt.Sprintf("%d", stats.CurrentLoggingStreak)},
+		)
+	}
+	for _, food := range stats.TopFoods {
+		records = append(records,
+			[]string{"topFood", food.Food, "count", fmt.Sprintf("%d", food.Count)},
+			[]string{"topFood", food.Food, "calories", fmt.Sprintf("%d", food.Calories)},
+		)
+	}
+	for _, weekday := range stats.Weekdays {
+		records = append(records,
+			[]string{"weekday", weekday.Weekday, "days", fmt.Sprintf("%d", weekday.Days)},
+			[]string{"weekday", weekday.Weekday, "averageIntake", fmt.Sprintf("%.0f", weekday.AverageIntake)},
+			[]string{"weekday", weekday.Weekday, "averageDeficit", fmt.Sprintf("%.0f", weekday.AverageDeficit)},
+		)
+	}
+	return writeCSV([]string{"section", "name", "key", "value"}, records)
+}
+
+// Calendar renders one row for every day of the month with the used calories, the AMR and the status
+func (r *CSVRenderer) Calendar(days model.Days, month time.Time) (string, error) {
+	records := [][]string{}
+	for _, week := range calendarWeeks(days, month) {
+		for _, day := range week.Days {
+			records = append(records, []string{util.FormatDate(day.Date, r.DateFormat), fmt.Sprintf("%d", day.Used), fmt.Sprintf("%.0f", day.AMR), day.Status})
+		}
+	}
+	return writeCSV([]string{"date", "calories", "amr", "status"}, records)
+}
+
+// Note renders a success message after setting the tags and the note of a day or an entry
+func (r *CSVRenderer) Note(date string, tags []string, note string, entry *model.Entry) (string, error) {
+	return csvMessage(noteMessage(util.DisplayDate(date, r.DateFormat), tags, note, entry))
+}
+
+// Water renders the water drunk on the given date and the water target
+func (r *CSVRenderer) Water(date string, water *model.DayWater) (string, error) {
+	return writeCSV([]string{"date", "amount", "target", "unit"}, [][]string{r.waterRecord(date, water)})
+}
+
+// AddWater renders the water drunk on the given date and the water target after adding water
+func (r *CSVRenderer) AddWater(date string, amount float64, water *model.DayWater) (string, error) {
+	return r.Water(date, water)
+}
+
+// waterRecord returns the date, amount, target and unit of the water drunk on a date
+func (r *CSVRenderer) waterRecord(date string, water *model.DayWater) []string {
+	amount := csvWater(water.UnitSystem, water.Amount)
+	target := csvWater(water.UnitSystem, waterTarget(water.Target))
+	return []string{util.DisplayDate(date, r.DateFormat), amount[0], target[0], amount[1]}
+}
+
+// AddMeasurement renders a success message and the added measurements
+func (r *CSVRenderer) AddMeasurement(measurement *model.Measurement, config *model.Config) (string, error) {
+	return csvMessage(fmt.Sprintf("Added measurements: %s", measurementString(measurement, config.UnitSystem)))
+}
+
+// Fasting renders one row for every completed fast and the running fast, which has no end, with its
+// start, end, duration in hours and whether the goal has been reached
+func (r *CSVRenderer) Fasting(history *model.FastingHistory) (string, error) {
+	records := [][]string{}
+	for i := range history.Fasts {
+		fast := &history.Fasts[i]
+		hours := fast.Hours(fast.End)
+		records = append(records, []string{r.csvTime(fast.Start), r.csvTime(fast.End), fmt.Sprintf("%.1f", hours), fmt.Sprint(hours >= history.Goal)})
+	}
+	if history.Running != nil {
+		records = append(records, []string{r.csvTime(history.Running.Start), "", fmt.Sprintf("%.1f", history.RunningHours), fmt.Sprint(history.RunningHours >= history.Goal)})
+	}
+	return writeCSV([]string{"start", "end", "hours", "reached"}, records)
+}
+
+// StartFast renders a success message after starting a fast
+func (r *CSVRenderer) StartFast(fast *model.Fast) (string, error) {
+	return csvMessage(fmt.Sprintf("Started fast at %s", r.csvTime(fast.Start)))
+}
+
+// StopFast renders a success message and the duration after stopping a fast
+func (r *CSVRenderer) StopFast(fast *model.Fast, goal float64) (string, error) {
+	return csvMessage(fmt.Sprintf("Stopped fast at %s after %.1f hours", r.csvTime(fast.End), fast.Hours(fast.End)))
+}
+
+// csvTime formats a point in time with the display date format and the time of day
+func (r *CSVRenderer) csvTime(t time.Time) string {
+	return fmt.Sprintf("%s %s", util.FormatDate(t, r.DateFormat), t.Format("15:04"))
+}
+
+// Profiles renders one row for every profile with its name and whether it is active
+func (r *CSVRenderer) Profiles(profiles []model.Profile) (string, error) {
+	records := [][]string{}
+	for _, profile := range profiles {
+		records = append(records, []string{profile.Name, fmt.Sprint(profile.Active)})
+	}
+	return writeCSV([]string{"name", "active"}, records)
+}
+
+// AddProfile renders a success message after adding a profile
+func (r *CSVRenderer) AddProfile(name string) (string, error) {
+	return csvMessage(fmt.Sprintf("Added profile %s", name))
+}
+
+// UseProfile renders a success message after switching the active profile
+func (r *CSVRenderer) UseProfile(name string) (string, error) {
+	return csvMessage(fmt.Sprintf("Switched to profile %s", name))
+}
+
+// Encryption renders a success message after encrypting or decrypting the database
+func (r *CSVRenderer) Encryption(path string, encrypted bool) (string, error) {
+	if encrypted {
+		return csvMessage(fmt.Sprintf("Encrypted database at %s", path))
+	}
+	return csvMessage(fmt.Sprintf("Decrypted database at %s", path))
+}
+
+// Backup renders a success message after creating a backup
+func (r *CSVRenderer) Backup(backup *model.Backup) (string, error) {
+	return csvMessage(fmt.Sprintf("Created backup %s", backup.Path))
+}
+
+// Restore renders a success message after restoring a backup
+func (r *CSVRenderer) Restore(backup *model.Backup) (string, error) {
+	return csvMessage(fmt.Sprintf("Restored database from backup %s", backup.Path))
+}
+
+// Backups renders one row for every backup with its path, creation time and size in bytes
+func (r *CSVRenderer) Backups(backups []model.Backup) (string, error) {
+	records := [][]string{}
+	for _, backup := range backups {
+		records = append(records, []string{backup.Path, r.csvTime(backup.Created), fmt.Sprintf("%d", backup.Size)})
+	}
+	return writeCSV([]string{"path", "created", "bytes"}, records)
+}
+
+// DBPath renders the database in use and the config file it is set in, which is empty if it is set
+// with --db or CALORIES_DB
+func (r *CSVRenderer) DBPath(connection, configFile string) (string, error) {
+	return writeCSV([]string{"database", "configFile"}, [][]string{{connection, configFile}})
+}
+
+// SetDB renders a success message after setting the database in the config file
+func (r *CSVRenderer) SetDB(connection, configFile string) (string, error) {
+	return csvMessage(fmt.Sprintf("Set database to %s in %s", connection, configFile))
+}
+
+// MoveDB renders a success message after moving the database
+func (r *CSVRenderer) MoveDB(from, to string) (string, error) {
+	return csvMessage(fmt.Sprintf("Moved database from %s to %s", from, to))
+}
+
+// Compaction renders the size of the database in bytes before and after compacting it
+func (r *CSVRenderer) Compaction(compaction *model.Compaction) (string, error) {
+	return writeCSV([]string{"path", "before", "after"}, [][]string{
+		{compaction.Path, fmt.Sprintf("%d", compaction.Before), fmt.Sprintf("%d", compaction.After)},
+	})
+}
+
+// IntegrityCheck renders one row for every problem found while checking the database
+func (r *CSVRenderer) IntegrityCheck(check *model.IntegrityCheck) (string, error) {
+	records := [][]string{}
+	for _, problem := range check.Problems {
+		records = append(records, []string{check.Path, problem})
+	}
+	return writeCSV([]string{"path", "problem"}, records)
+}
+
+// DBStats renders one row for every bucket or table with its number of records and size in bytes,
+// the size of the whole database is in the row without a name
+func (r *CSVRenderer) DBStats(stats *model.DBStats) (string, error) {
+	records := [][]string{{"", "", fmt.Sprintf("%d", stats.Size)}}
+	for _, bucket := range stats.Buckets {
+		records = append(records, []string{bucket.Name, fmt.Sprintf("%d", bucket.Records), fmt.Sprintf("%d", bucket.Size)})
+	}
+	return writeCSV([]string{"name", "records", "bytes"}, records)
+}
+
+// Init renders a success message after setting up calories
+func (r *CSVRenderer) Init(connection, configFile string, configured bool) (string, error) {
+	return csvMessage(fmt.Sprintf("Initialized database %s in %s", connection, configFile))
+}
+
+// csvMessage writes the given success message as CSV with a single message column
+func csvMessage(message string) (string, error) {
+	return writeCSV([]string{"message"}, [][]string{{message}})
+}
+
+// writeCSV writes the given header and records as CSV, without a trailing newline
+func writeCSV(header []string, records [][]string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(header); err != nil {
+		return "", fmt.Errorf("could not write csv, %v", err)
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", fmt.Errorf("could not write csv, %v", err)
+	}
+	return strings.TrimSuffix(b.String(), "\n"), nil
+}
+
+// csvWeight returns the given weight in kg as value and unit in the given unit system
+func csvWeight(unitSystem string, value float64) []string {
+	if unitSystem == util.Imperial {
+		return []string{fmt.Sprintf("%.1f", util.ToPounds(value)), "pounds"}
+	}
+	return []string{fmt.Sprintf("%.1f", value), "kg"}
+}
+
+// csvLength returns the given length in cm as value and unit in the given unit system
+func csvLength(unitSystem string, value float64) []string {
+	if unitSystem == util.Imperial {
+		return []string{fmt.Sprintf("%.1f", util.ToInches(value)), "inches"}
+	}
+	return []string{fmt.Sprintf("%.1f", value), "cm"}
+}
+
+// csvWater returns the given amount of water in ml as value and unit in the given unit system
+func csvWater(unitSystem string, value float64) []string {
+	if unitSystem == util.Imperial {
+		return []string{fmt.Sprintf("%.1f", util.ToFluidOunces(value)), "fl oz"}
+	}
+	return []string{fmt.Sprintf("%.0f", value), "ml"}
+}
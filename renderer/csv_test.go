@@ -0,0 +1,283 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestCSVWeightHistory(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	weights := []model.Weight{
+		{Weight: 85.0, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{Weight: 84.5, Created: time.Date(2017, 1, 6, 12, 0, 0, 0, time.UTC)},
+	}
+	res, err := r.WeightHistory(weights, &model.Config{UnitSystem: util.Metric})
+	expected := "date,weight,unit\n2017-01-05,85.0,kg\n2017-01-06,84.5,kg"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.WeightHistory(weights[:1], &model.Config{UnitSystem: util.Imperial})
+	expected = "date,weight,unit\n2017-01-05,187.4,pounds"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVConfig(t *testing.T) {
+	r := CSVRenderer{DateFormat: "dd.mm.yyyy"}
+	res, err := r.Config(&model.Config{
+		Height:     185.0,
+		Activity:   1.375,
+		Birthday:   time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		Gender:     "male",
+		UnitSystem: util.Metric,
+		Timezone:   "Europe/Vienna",
+	}, &model.Weight{Weight: 85.0}, &model.BodyComposition{BMI: 24.8, BMICategory: "normal", HealthyWeightMin: 63.3, HealthyWeightMax: 85.2}, 2000.0, 1500.0, 27)
+	expected := "key,value,unit\nweight,85.0,kg\nheight,185.0,cm\nbmi,24.8,\nbmiCategory,normal,\nhealthyWeightMin,63.3,kg\nhealthyWeightMax,85.2,kg\nactivity,1.375,\nbirthday,01.01.1990,\nage,27,years\ngender,male,\nunitSystem,metric,\ndateFormat,dd.mm.yyyy,\ntimezone,Europe/Vienna,\ndayRollover,0,hour\nbudget,daily,\nwaterTarget,2000,ml\neatingWindow,none,\nformula,harris-benedict,\namr,2000,calories\nbmr,1500,calories"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVDays(t *testing.T) {
+	r := CSVRenderer{DateFormat: "dd.mm.yyyy"}
+	from := time.Date(2017, 1, 5, 0, 0, 0, 0, time.UTC)
+	days := model.Days{
+		&model.Day{Date: from, Used: 700, Entries: model.Entries{
+			{Food: "Apple", Calories: 100, AMR: 2500.4},
+			{Food: "Pizza, large", Calories: 600, AMR: 2500.4},
+		}},
+		&model.Day{Date: from.AddDate(0, 0, 1)},
+		&model.Day{Date: from.AddDate(0, 0, 2), Used: 300, Entries: model.Entries{
+			{Food: "Toast \"deluxe\"", Calories: 300, AMR: 2400},
+		}},
+	}
+	res, err := r.Days(days, from, from.AddDate(0, 0, 2), &model.WeeklyBudget{})
+	expected := "date,food,calories,amr\n05.01.2017,Apple,100,2500\n05.01.2017,\"Pizza, large\",600,2500\n07.01.2017,\"Toast \"\"deluxe\"\"\",300,2400"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Days(model.Days{}, from, from, nil)
+	expected = "date,food,calories,amr"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVError(t *testing.T) {
+	r := CSVRenderer{}
+	res, err := r.Error(errors.New("someError"))
+	if res != "someError" || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, "someError")
+		return
+	}
+}
+
+func TestCSVMeasurements(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	measurements := []model.Measurement{
+		{Waist: 90.0, BodyFat: 18.5, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{Hip: 100.0, Created: time.Date(2017, 1, 6, 12, 0, 0, 0, time.UTC)},
+	}
+	res, err := r.Measurements(measurements, &model.Config{UnitSystem: util.Metric})
+	expected := "date,measurement,value,unit\n2017-01-05,waist,90.0,cm\n2017-01-05,bodyFat,18.5,%\n2017-01-06,hip,100.0,cm"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Measurements(measurements[1:], &model.Config{UnitSystem: util.Imperial})
+	expected = "date,measurement,value,unit\n2017-01-06,hip,39.4,inches"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVSearch(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	entries := model.Entries{
+		{EntryDate: "05.01.2017", Food: "Pizza, large", Calories: 600},
+		{EntryDate: "07.01.2017", Food: "Pizza", Calories: 400},
+	}
+	res, err := r.Search("pizza", entries)
+	expected := "date,food,calories\n2017-01-05,\"Pizza, large\",600\n2017-01-07,Pizza,400"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Search("pizza", model.Entries{})
+	expected = "date,food,calories"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVStats(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	stats := &model.Stats{
+		From:                     time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:                       time.Date(2017, 1, 7, 0, 0, 0, 0, time.UTC),
+		LoggedDays:               2,
+		AverageIntake:            1800,
+		AverageDeficit:           200,
+		DaysUnderBudget:          2,
+		LongestUnderBudgetStreak: 2,
+		CurrentLoggingStreak:     1,
+		TopFoods:                 []model.FoodCount{{Food: "Pizza", Count: 2, Calories: 1200}},
+		Weekdays:                 []model.WeekdayStats{{Weekday: "Monday", Days: 1, AverageIntake: 1800, AverageDeficit: 200}},
+	}
+	res, err := r.Stats(stats)
+	expected := "section,name,key,value\nsummary,,from,2017-01-01\nsummary,,to,2017-01-07\nsummary,,loggedDays,2\nsummary,,averageIntake,1800\nsummary,,averageDeficit,200\nsummary,,daysUnderBudget,2\nsummary,,daysOverBudget,0\nsummary,,longestUnderBudgetStreak,2\nsummary,,currentLoggingStreak,1\ntopFood,Pizza,count,2\ntopFood,Pizza,calories,1200\nweekday,Monday,days,1\nweekday,Monday,averageIntake,1800\nweekday,Monday,averageDeficit,200"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Stats(&model.Stats{From: stats.From, To: stats.To})
+	expected = "section,name,key,value\nsummary,,from,2017-01-01\nsummary,,to,2017-01-07\nsummary,,loggedDays,0"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCSVCalendar(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	month := time.Date(2017, 2, 1, 0, 0, 0, 0, time.UTC)
+	days := model.Days{
+		&model.Day{Date: time.Date(2017, 2, 2, 0, 0, 0, 0, time.UTC), Used: 2500, Entries: model.Entries{{Calories: 2500, AMR: 2000}}},
+	}
+	res, err := r.Calendar(days, month)
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	lines := strings.Split(res, "\n")
+	if len(lines) != 29 {
+		t.Errorf("Error, actual: %v expected: %v", len(lines), 29)
+		return
+	}
+	expected := []string{"date,calories,amr,status", "2017-02-01,0,0,none", "2017-02-02,2500,2000,surplus"}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Error, actual: %v expected: %v", lines[i], expected[i])
+			return
+		}
+	}
+}
+
+func TestCSVTables(t *testing.T) {
+	r := CSVRenderer{DateFormat: "yyyy-mm-dd"}
+	start := time.Date(2017, 1, 5, 20, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		description string
+		render      func() (string, error)
+		expected    string
+	}{
+		{
+			description: "config history",
+			render: func() (string, error) {
+				return r.ConfigHistory([]model.Config{
+					{Height: 180, Activity: 1.5, Gender: "male", UnitSystem: util.Metric},
+					{Height: 180, Activity: 1.2, Gender: "male", UnitSystem: util.Metric, Effective: start},
+				})
+			},
+			expected: "effective,height,unit,activity,gender,unitSystem\n,180.0,cm,1.5,male,metric\n2017-01-05,180.0,cm,1.2,male,metric",
+		},
+		{
+			description: "recalc",
+			render: func() (string, error) {
+				return r.Recalc([]model.Recalculation{{Entry: model.Entry{EntryDate: "05.01.2017", Food: "Pizza", Calories: 600}, OldBMR: 1500, NewBMR: 1600, OldAMR: 2000, NewAMR: 2100}}, true)
+			},
+			expected: "date,food,calories,oldBmr,newBmr,oldAmr,newAmr,dryRun\n2017-01-05,Pizza,600,1500,1600,2000,2100,true",
+		},
+		{
+			description: "water",
+			render: func() (string, error) {
+				return r.Water("05.01.2017", &model.DayWater{Amount: 750, UnitSystem: util.Metric})
+			},
+			expected: "date,amount,target,unit\n2017-01-05,750,2000,ml",
+		},
+		{
+			description: "fasting",
+			render: func() (string, error) {
+				return r.Fasting(&model.FastingHistory{
+					Fasts:        []model.Fast{{Start: start, End: start.Add(17 * time.Hour)}},
+					Running:      &model.Fast{Start: start.AddDate(0, 0, 1)},
+					RunningHours: 2,
+					Goal:         16,
+				})
+			},
+			expected: "start,end,hours,reached\n2017-01-05 20:00,2017-01-06 13:00,17.0,true\n2017-01-06 20:00,,2.0,false",
+		},
+		{
+			description: "profiles",
+			render: func() (string, error) {
+				return r.Profiles([]model.Profile{{Name: "default"}, {Name: "anna", Active: true}})
+			},
+			expected: "name,active\ndefault,false\nanna,true",
+		},
+		{
+			description: "backups",
+			render: func() (string, error) {
+				return r.Backups([]model.Backup{{Path: "/tmp/calories.db.bak", Created: start, Size: 2048}})
+			},
+			expected: "path,created,bytes\n/tmp/calories.db.bak,2017-01-05 20:00,2048",
+		},
+		{
+			description: "db path",
+			render: func() (string, error) {
+				return r.DBPath("/tmp/calories.db", "")
+			},
+			expected: "database,configFile\n/tmp/calories.db,",
+		},
+		{
+			description: "integrity check",
+			render: func() (string, error) {
+				return r.IntegrityCheck(&model.IntegrityCheck{Path: "/tmp/calories.db", Problems: []string{"broken page"}})
+			},
+			expected: "path,problem\n/tmp/calories.db,broken page",
+		},
+		{
+			description: "db stats",
+			render: func() (string, error) {
+				return r.DBStats(&model.DBStats{Size: 4096, Buckets: []model.BucketStats{{Name: "Entry", Records: 3}}})
+			},
+			expected: "name,records,bytes\n,,4096\nEntry,3,0",
+		},
+		{
+			description: "success message",
+			render: func() (string, error) {
+				return r.ClearEntry("05.01.2017", &model.Entry{Food: "Pizza, large", Calories: 600})
+			},
+			expected: "message\n\"Cleared entry 600 Pizza, large for 2017-01-05\"",
+		},
+		{
+			description: "add entry with warning",
+			render: func() (string, error) {
+				return r.AddEntry("05.01.2017", 600, "Pizza", "over budget")
+			},
+			expected: "message,warning\nAdded Entry for 2017-01-05 with 600 calories (Pizza),over budget",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res, err := tc.render()
+			if res != tc.expected || err != nil {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
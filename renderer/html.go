@@ -0,0 +1,86 @@
+package renderer
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/zupzup/calories/model"
+)
+
+// htmlStyle is the inline stylesheet, so the reports don't depend on other files
+const htmlStyle = "body{font-family:sans-serif;margin:2em}table{border-collapse:collapse;margin-bottom:1em}th,td{border:1px solid #ccc;padding:4px 8px;text-align:left}th{background:#eee}"
+
+// HTMLRenderer is the renderer for self-contained HTML reports, e.g. for emails
+// Days, statistics, search results, weights, measurements and errors are rendered as HTML documents,
+// all other output like in the terminal
+type HTMLRenderer struct {
+	TerminalRenderer
+}
+
+// Error renders an HTML document with the given error
+func (r *HTMLRenderer) Error(err error) (string, error) {
+	return htmlDocument(errorReport(err)), nil
+}
+
+// WeightHistory renders an HTML document with a table of all weights with their date and value
+func (r *HTMLRenderer) WeightHistory(weights []model.Weight, config *model.Config) (string, error) {
+	return htmlDocument(weightReport(weights, config, r.DateFormat)), nil
+}
+
+// Days renders an HTML document with a table of the given days with their total, a table of
+// their entries and the weekly budget
+func (r *HTMLRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
+	return htmlDocument(daysReport(days, from, to, budget, r.DateFormat)), nil
+}
+
+// Stats renders an HTML document with a table of the given statistics, a table of the most
+// frequent foods and a table of the weekdays
+func (r *HTMLRenderer) Stats(stats *model.Stats) (string, error) {
+	return htmlDocument(statsReport(stats, r.DateFormat)), nil
+}
+
+// Search renders an HTML document with a table of the found entries with their total
+func (r *HTMLRenderer) Search(query string, entries model.Entries) (string, error) {
+	return htmlDocument(searchReport(query, entries, r.DateFormat)), nil
+}
+
+// Measurements renders an HTML document with a table of all measurements with their date and values
+func (r *HTMLRenderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	return htmlDocument(measurementsReport(measurements, config, r.DateFormat)), nil
+}
+
+// htmlDocument renders the given sections as a complete HTML document, titled with the first section
+func htmlDocument(sections []section) string {
+	var b strings.Builder
+	title := ""
+	if len(sections) > 0 {
+		title = sections[0].title
+	}
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n", html.EscapeString(title), htmlStyle)
+	for _, s := range sections {
+		fmt.Fprintf(&b, "<h2>%s</h2>\n", html.EscapeString(s.title))
+		if s.header == nil {
+			fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(s.text))
+			continue
+		}
+		b.WriteString("<table>\n")
+		b.WriteString(htmlRow("th", s.header))
+		for _, row := range s.rows {
+			b.WriteString(htmlRow("td", row))
+		}
+		b.WriteString("</table>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// htmlRow renders a table row with the given cell tag, escaping the cells
+func htmlRow(tag string, cells []string) string {
+	res := "<tr>"
+	for _, cell := range cells {
+		res += fmt.Sprintf("<%s>%s</%s>", tag, html.EscapeString(cell), tag)
+	}
+	return res + "</tr>\n"
+}
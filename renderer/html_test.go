@@ -0,0 +1,178 @@
+package renderer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestHTMLWeightHistory(t *testing.T) {
+	r := HTMLRenderer{TerminalRenderer{DateFormat: "yyyy-mm-dd"}}
+	weights := []model.Weight{
+		{Weight: 85.0, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+	}
+	res, err := r.WeightHistory(weights, &model.Config{UnitSystem: util.Metric})
+	expected := "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Weight over time</title>\n<style>" + htmlStyle + "</style>\n</head>\n<body>\n" +
+		"<h2>Weight over time</h2>\n<table>\n<tr><th>Date</th><th>Weight</th></tr>\n<tr><td>2017-01-05</td><td>85.0 kg</td></tr>\n</table>\n" +
+		"</body>\n</html>\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestHTMLDays(t *testing.T) {
+	r := HTMLRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	from := time.Date(2017, 1, 5, 0, 0, 0, 0, time.UTC)
+	days := model.Days{
+		&model.Day{Date: from, Used: 700, Entries: model.Entries{
+			{Food: "Fish & Chips", Calories: 700, AMR: 2500},
+		}},
+	}
+	testCases := []struct {
+		description string
+		days        model.Days
+		to          time.Time
+		budget      *model.WeeklyBudget
+		contains    []string
+	}{
+		{
+			description: "days with entries",
+			days:        days,
+			contains: []string{
+				"<title>Data from 05.01.2017 to 05.01.2017</title>",
+				"<tr><td>05.01.2017</td><td>700</td><td>2500</td><td>1800 deficit</td></tr>",
+				"<tr><td>Total</td><td>700</td><td>2500</td><td>1800 deficit</td></tr>",
+				"<h2>Entries</h2>",
+				"<tr><td>05.01.2017</td><td>Fish &amp; Chips</td><td>700</td></tr>",
+			},
+		},
+		{
+			description: "days in two weeks",
+			days: model.Days{
+				days[0],
+				&model.Day{Date: from.AddDate(0, 0, 4), Used: 2600, Entries: model.Entries{{Food: "Cake", Calories: 2600, AMR: 2400}}},
+			},
+			to: from.AddDate(0, 0, 4),
+			contains: []string{
+				"<h2>Weeks</h2>",
+				"<tr><td>02.01.2017 - 08.01.2017</td><td>700</td><td>2500</td><td>1800 deficit</td></tr>",
+				"<tr><td>09.01.2017 - 15.01.2017</td><td>2600</td><td>2400</td><td>200 surplus</td></tr>",
+			},
+		},
+		{
+			description: "no entries with budget",
+			days:        model.Days{},
+			budget:      &model.WeeklyBudget{From: from, To: from.AddDate(0, 0, 6), Total: 17000, Used: 17500, Remaining: -500},
+			contains: []string{
+				"<p>No entries have been found.</p>",
+				"<h2>Weekly budget from 05.01.2017 to 11.01.2017</h2>",
+				"<tr><td>Remaining</td><td>500 over budget</td></tr>",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			to := tc.to
+			if to.IsZero() {
+				to = from
+			}
+			res, err := r.Days(tc.days, from, to, tc.budget)
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			if !strings.HasPrefix(res, "<!DOCTYPE html>") || !strings.HasSuffix(res, "</html>\n") {
+				t.Errorf("Error, actual: %v expected: %v", res, "a complete HTML document")
+				return
+			}
+			for _, expected := range tc.contains {
+				if !strings.Contains(res, expected) {
+					t.Errorf("Error, actual: %v expected: %v", res, expected)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestHTMLReports(t *testing.T) {
+	r := HTMLRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	from := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		description string
+		render      func() (string, error)
+		contains    []string
+	}{
+		{
+			description: "error",
+			render:      func() (string, error) { return r.Error(errors.New("<someError>")) },
+			contains:    []string{"<title>Error</title>", "<p>&lt;someError&gt;</p>"},
+		},
+		{
+			description: "stats",
+			render: func() (string, error) {
+				return r.Stats(&model.Stats{
+					From:           from,
+					To:             from,
+					LoggedDays:     1,
+					AverageIntake:  1800,
+					AverageDeficit: 200,
+					TopFoods:       []model.FoodCount{{Food: "Fish & Chips", Count: 1, Calories: 1800}},
+					Weekdays:       []model.WeekdayStats{{Weekday: "Monday", Days: 1, AverageIntake: 1800, AverageDeficit: 200}},
+				})
+			},
+			contains: []string{
+				"<title>Statistics from 02.01.2017 to 02.01.2017</title>",
+				"<tr><td>Average difference</td><td>200 deficit</td></tr>",
+				"<tr><td>Fish &amp; Chips</td><td>1</td><td>1800</td></tr>",
+				"<tr><td>Monday</td><td>1</td><td>1800</td><td>200 deficit</td></tr>",
+			},
+		},
+		{
+			description: "search",
+			render: func() (string, error) {
+				return r.Search("fish", model.Entries{{EntryDate: "02.01.2017", Food: "Fish & Chips", Calories: 700}})
+			},
+			contains: []string{
+				"<title>Entries for &#34;fish&#34;</title>",
+				"<tr><td>02.01.2017</td><td>Fish &amp; Chips</td><td>700</td></tr>",
+				"<tr><td>Total</td><td>1 entries</td><td>700</td></tr>",
+			},
+		},
+		{
+			description: "measurements",
+			render: func() (string, error) {
+				return r.Measurements([]model.Measurement{{Waist: 90.0, BodyFat: 18.5, Created: from}}, &model.Config{UnitSystem: util.Imperial})
+			},
+			contains: []string{
+				"<title>Measurements over time</title>",
+				"<tr><td>02.01.2017</td><td>35.4 inches</td><td></td><td></td><td></td><td></td><td></td><td>18.5%</td></tr>",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res, err := tc.render()
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			if !strings.HasPrefix(res, "<!DOCTYPE html>") || !strings.HasSuffix(res, "</html>\n") {
+				t.Errorf("Error, actual: %v expected: %v", res, "a complete HTML document")
+				return
+			}
+			for _, expected := range tc.contains {
+				if !strings.Contains(res, expected) {
+					t.Errorf("Error, actual: %v expected: %v", res, expected)
+					return
+				}
+			}
+		})
+	}
+}
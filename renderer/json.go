@@ -14,8 +14,17 @@ type success struct {
 	Message string `json:"message"`
 }
 
-// JSONRenderer is the JSON renderer
-type JSONRenderer struct{}
+// database is the database in use and the config file it is set in
+type database struct {
+	Connection string `json:"connection"`
+	ConfigFile string `json:"configFile"`
+}
+
+// JSONRenderer is the JSON renderer, DateFormat is the display date
+// format used for formatted dates
+type JSONRenderer struct {
+	DateFormat string
+}
 
 // Error renders an error
 func (r *JSONRenderer) Error(err error) (string, error) {
@@ -69,29 +78,87 @@ func (r *JSONRenderer) AddWeight(weight float64, config *model.Config) (string,
 	return string(b), nil
 }
 
-// Config prints the given configuration with weight, amr and bmr
-func (r *JSONRenderer) Config(config *model.Config, weight *model.Weight, amr, bmr float64, age int) (string, error) {
+// Config prints the given configuration with weight, body composition, amr and bmr
+func (r *JSONRenderer) Config(config *model.Config, weight *model.Weight, composition *model.BodyComposition, amr, bmr float64, age int) (string, error) {
 	type fullConfig struct {
-		Weight     string  `json:"weight"`
-		Height     string  `json:"height"`
-		Activity   float64 `json:"activity"`
-		Birthday   string  `json:"birthday"`
-		Age        int     `json:"age"`
-		Gender     string  `json:"gender"`
-		UnitSystem string  `json:"unitSystem"`
-		AMR        float64 `json:"amr"`
-		BMR        float64 `json:"bmr"`
+		Weight           string  `json:"weight"`
+		Height           string  `json:"height"`
+		BMI              float64 `json:"bmi"`
+		BMICategory      string  `json:"bmiCategory"`
+		HealthyWeightMin string  `json:"healthyWeightMin"`
+		HealthyWeightMax string  `json:"healthyWeightMax"`
+		BodyFat          float64 `json:"bodyFat,omitempty"`
+		LeanMass         string  `json:"leanMass,omitempty"`
+		FFMI             float64 `json:"ffmi,omitempty"`
+		Activity         float64 `json:"activity"`
+		Birthday         string  `json:"birthday"`
+		Age              int     `json:"age"`
+		Gender           string  `json:"gender"`
+		UnitSystem       string  `json:"unitSystem"`
+		DateFormat       string  `json:"dateFormat"`
+		Timezone         string  `json:"timezone"`
+		DayRollover      int     `json:"dayRollover"`
+		Budget           string  `json:"budget"`
+		WaterTarget      string  `json:"waterTarget"`
+		EatingWindow     string  `json:"eatingWindow"`
+		Formula          string  `json:"formula"`
+		AMR              float64 `json:"amr"`
+		BMR              float64 `json:"bmr"`
 	}
 	res := fullConfig{
-		Weight:     util.WeightUnit(config.UnitSystem, weight.Weight),
-		Height:     util.HeightUnit(config.UnitSystem, config.Height),
-		Activity:   config.Activity,
-		Birthday:   config.Birthday.Format(util.DateFormat),
-		Age:        age,
-		Gender:     config.Gender,
-		UnitSystem: config.UnitSystem,
-		AMR:        amr,
-		BMR:        bmr,
+		Weight:           util.WeightUnit(config.UnitSystem, weight.Weight),
+		Height:           util.HeightUnit(config.UnitSystem, config.Height),
+		BMI:              composition.BMI,
+		BMICategory:      composition.BMICategory,
+		HealthyWeightMin: util.WeightUnit(config.UnitSystem, composition.HealthyWeightMin),
+		HealthyWeightMax: util.WeightUnit(config.UnitSystem, composition.HealthyWeightMax),
+		Activity:         config.Activity,
+		Birthday:         util.FormatDate(config.Birthday, r.DateFormat),
+		Age:              age,
+		Gender:           config.Gender,
+		UnitSystem:       config.UnitSystem,
+		DateFormat:       util.NormalizeDateFormat(config.DateFormat),
+		Timezone:         timezoneName(config.Timezone),
+		DayRollover:      config.DayRollover,
+		Budget:           budgetMode(config.Budget),
+		WaterTarget:      util.WaterUnit(config.UnitSystem, waterTarget(config.WaterTarget)),
+		EatingWindow:     util.FormatEatingWindow(config.EatingWindow),
+		Formula:          formula(config.Formula),
+		AMR:              amr,
+		BMR:              bmr,
+	}
+	if composition.BodyFat > 0 {
+		res.BodyFat = composition.BodyFat
+		res.LeanMass = util.WeightUnit(config.UnitSystem, composition.LeanMass)
+		res.FFMI = composition.FFMI
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// ConfigHistory prints all config versions with the date they are effective from
+func (r *JSONRenderer) ConfigHistory(configs []model.Config) (string, error) {
+	type configVersion struct {
+		Effective  time.Time `json:"effective"`
+		Formatted  string    `json:"formatted"`
+		Height     string    `json:"height"`
+		Activity   float64   `json:"activity"`
+		Gender     string    `json:"gender"`
+		UnitSystem string    `json:"unitSystem"`
+	}
+	res := []*configVersion{}
+	for _, config := range configs {
+		res = append(res, &configVersion{
+			Effective:  config.Effective,
+			Formatted:  effectiveDate(config.Effective, r.DateFormat),
+			Height:     util.HeightUnit(config.UnitSystem, config.Height),
+			Activity:   config.Activity,
+			Gender:     config.Gender,
+			UnitSystem: config.UnitSystem,
+		})
 	}
 	b, err := json.Marshal(res)
 	if err != nil {
@@ -101,16 +168,18 @@ func (r *JSONRenderer) Config(config *model.Config, weight *model.Weight, amr, b
 }
 
 // Days renders the days in the given timespan
-func (r *JSONRenderer) Days(days model.Days, from, to time.Time) (string, error) {
+func (r *JSONRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
 	type daysData struct {
-		From time.Time
-		To   time.Time
-		Days model.Days
+		From         time.Time
+		To           time.Time
+		Days         model.Days
+		WeeklyBudget *model.WeeklyBudget `json:",omitempty"`
 	}
 	res := daysData{
-		From: from,
-		To:   to,
-		Days: days,
+		From:         from,
+		To:           to,
+		Days:         days,
+		WeeklyBudget: budget,
 	}
 	b, err := json.Marshal(res)
 	if err != nil {
@@ -119,11 +188,18 @@ func (r *JSONRenderer) Days(days model.Days, from, to time.Time) (string, error)
 	return string(b), nil
 }
 
-// AddEntry displays a success message after adding an entry
-func (r *JSONRenderer) AddEntry(date string, calories int, food string) (string, error) {
-	res := success{
-		Success: true,
-		Message: fmt.Sprintf("Added Entry for %s with %d calories (%s)", date, calories, food),
+// AddEntry displays a success message after adding an entry and the given warning, if there is one
+func (r *JSONRenderer) AddEntry(date string, calories int, food, warning string) (string, error) {
+	type successWithWarning struct {
+		success
+		Warning string `json:"warning,omitempty"`
+	}
+	res := successWithWarning{
+		success: success{
+			Success: true,
+			Message: fmt.Sprintf("Added Entry for %s with %d calories (%s)", util.DisplayDate(date, r.DateFormat), calories, food),
+		},
+		Warning: warning,
 	}
 	b, err := json.Marshal(res)
 	if err != nil {
@@ -136,7 +212,7 @@ func (r *JSONRenderer) AddEntry(date string, calories int, food string) (string,
 func (r *JSONRenderer) ClearEntries(date string) (string, error) {
 	res := success{
 		Success: true,
-		Message: fmt.Sprintf("Cleared all entries for %s", date),
+		Message: fmt.Sprintf("Cleared all entries for %s", util.DisplayDate(date, r.DateFormat)),
 	}
 	b, err := json.Marshal(res)
 	if err != nil {
@@ -149,7 +225,7 @@ func (r *JSONRenderer) ClearEntries(date string) (string, error) {
 func (r *JSONRenderer) ClearEntry(date string, entry *model.Entry) (string, error) {
 	res := success{
 		Success: true,
-		Message: fmt.Sprintf("Cleared entry %d %s for %s", entry.Calories, entry.Food, date),
+		Message: fmt.Sprintf("Cleared entry %d %s for %s", entry.Calories, entry.Food, util.DisplayDate(date, r.DateFormat)),
 	}
 	b, err := json.Marshal(res)
 	if err != nil {
@@ -179,3 +255,407 @@ func (r *JSONRenderer) Import(fileName string, numEntries, numWeights int) (stri
 	}
 	return string(b), nil
 }
+
+// Recalc renders the changed metabolic rates of all recalculated entries
+func (r *JSONRenderer) Recalc(recalculations []model.Recalculation, dryRun bool) (string, error) {
+	type recalcData struct {
+		DryRun         bool                  `json:"dryRun"`
+		Recalculations []model.Recalculation `json:"recalculations"`
+	}
+	if recalculations == nil {
+		recalculations = []model.Recalculation{}
+	}
+	res := recalcData{
+		DryRun:         dryRun,
+		Recalculations: recalculations,
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Stats renders the statistics for a range of days
+func (r *JSONRenderer) Stats(stats *model.Stats) (string, error) {
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Calendar renders the weeks of the given month with the status of each day
+func (r *JSONRenderer) Calendar(days model.Days, month time.Time) (string, error) {
+	type calendarData struct {
+		Month string         `json:"month"`
+		Weeks []calendarWeek `json:"weeks"`
+	}
+	res := calendarData{
+		Month: month.Format("2006-01"),
+		Weeks: calendarWeeks(days, month),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Search renders the entries found for the given query with their totals
+func (r *JSONRenderer) Search(query string, entries model.Entries) (string, error) {
+	type searchData struct {
+		Query    string        `json:"query"`
+		Entries  model.Entries `json:"entries"`
+		Count    int           `json:"count"`
+		Calories int           `json:"calories"`
+	}
+	if entries == nil {
+		entries = model.Entries{}
+	}
+	res := searchData{
+		Query:   query,
+		Entries: entries,
+		Count:   len(entries),
+	}
+	for _, entry := range entries {
+		res.Calories += entry.Calories
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Note renders a success message after setting the tags and the note of a day,
+// or of the given entry
+func (r *JSONRenderer) Note(date string, tags []string, note string, entry *model.Entry) (string, error) {
+	res := success{
+		Success: true,
+		Message: noteMessage(util.DisplayDate(date, r.DateFormat), tags, note, entry),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Water renders the water drunk on the given date and the daily target
+func (r *JSONRenderer) Water(date string, water *model.DayWater) (string, error) {
+	type waterData struct {
+		Date            string  `json:"date"`
+		Amount          float64 `json:"amount"`
+		Target          float64 `json:"target"`
+		UnitSystem      string  `json:"unitSystem"`
+		FormattedAmount string  `json:"formattedAmount"`
+		FormattedTarget string  `json:"formattedTarget"`
+	}
+	res := waterData{
+		Date:            util.DisplayDate(date, r.DateFormat),
+		Amount:          water.Amount,
+		Target:          water.Target,
+		UnitSystem:      water.UnitSystem,
+		FormattedAmount: util.WaterUnit(water.UnitSystem, water.Amount),
+		FormattedTarget: util.WaterUnit(water.UnitSystem, water.Target),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Measurements renders all body measurements in order and their dates
+func (r *JSONRenderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	type measurementUnit struct {
+		Created   time.Time `json:"created"`
+		Waist     float64   `json:"waist,omitempty"`
+		Hip       float64   `json:"hip,omitempty"`
+		Chest     float64   `json:"chest,omitempty"`
+		Neck      float64   `json:"neck,omitempty"`
+		Arm       float64   `json:"arm,omitempty"`
+		Thigh     float64   `json:"thigh,omitempty"`
+		BodyFat   float64   `json:"bodyFat,omitempty"`
+		Formatted string    `json:"formatted"`
+	}
+	length := func(value float64) float64 {
+		if config.UnitSystem == util.Imperial {
+			return util.ToInches(value)
+		}
+		return value
+	}
+	res := []*measurementUnit{}
+	for i := range measurements {
+		m := measurements[i]
+		res = append(res, &measurementUnit{
+			Created:   m.Created,
+			Waist:     length(m.Waist),
+			Hip:       length(m.Hip),
+			Chest:     length(m.Chest),
+			Neck:      length(m.Neck),
+			Arm:       length(m.Arm),
+			Thigh:     length(m.Thigh),
+			BodyFat:   m.BodyFat,
+			Formatted: measurementString(&m, config.UnitSystem),
+		})
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// AddMeasurement renders a success message and the added measurements
+func (r *JSONRenderer) AddMeasurement(measurement *model.Measurement, config *model.Config) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Added measurements: %s", measurementString(measurement, config.UnitSystem)),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Fasting renders all completed fasts with their durations, the running fast and the streaks
+func (r *JSONRenderer) Fasting(history *model.FastingHistory) (string, error) {
+	type fastData struct {
+		Start   time.Time `json:"start"`
+		End     time.Time `json:"end"`
+		Hours   float64   `json:"hours"`
+		Reached bool      `json:"reached"`
+	}
+	type historyData struct {
+		Fasts         []fastData `json:"fasts"`
+		Running       *fastData  `json:"running,omitempty"`
+		Goal          float64    `json:"goal"`
+		AverageHours  float64    `json:"averageHours"`
+		CurrentStreak int        `json:"currentStreak"`
+		LongestStreak int        `json:"longestStreak"`
+	}
+	res := historyData{
+		Fasts:         []fastData{},
+		Goal:          history.Goal,
+		AverageHours:  history.AverageHours,
+		CurrentStreak: history.CurrentStreak,
+		LongestStreak: history.LongestStreak,
+	}
+	for i := range history.Fasts {
+		fast := &history.Fasts[i]
+		hours := fast.Hours(fast.End)
+		res.Fasts = append(res.Fasts, fastData{Start: fast.Start, End: fast.End, Hours: hours, Reached: hours >= history.Goal})
+	}
+	if history.Running != nil {
+		res.Running = &fastData{Start: history.Running.Start, Hours: history.RunningHours, Reached: history.RunningHours >= history.Goal}
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// StartFast renders a success message after starting a fast
+func (r *JSONRenderer) StartFast(fast *model.Fast) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Started fast at %s %s", util.FormatDate(fast.Start, r.DateFormat), fast.Start.Format("15:04")),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// StopFast renders a success message and the duration after stopping a fast
+func (r *JSONRenderer) StopFast(fast *model.Fast, goal float64) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Stopped fast at %s %s after %.1f hours", util.FormatDate(fast.End, r.DateFormat), fast.End.Format("15:04"), fast.Hours(fast.End)),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// AddWater renders a success message after adding water
+func (r *JSONRenderer) AddWater(date string, amount float64, water *model.DayWater) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Added %s of water for %s", util.WaterUnit(water.UnitSystem, amount), util.DisplayDate(date, r.DateFormat)),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Profiles renders all profiles
+func (r *JSONRenderer) Profiles(profiles []model.Profile) (string, error) {
+	b, err := json.Marshal(profiles)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// AddProfile displays a success message after adding a profile
+func (r *JSONRenderer) AddProfile(name string) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Added profile %s", name),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// UseProfile displays a success message after switching the active profile
+func (r *JSONRenderer) UseProfile(name string) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Switched to profile %s", name),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Encryption displays a success message after encrypting or decrypting the database
+func (r *JSONRenderer) Encryption(path string, encrypted bool) (string, error) {
+	message := fmt.Sprintf("Decrypted database at %s", path)
+	if encrypted {
+		message = fmt.Sprintf("Encrypted database at %s", path)
+	}
+	res := success{
+		Success: true,
+		Message: message,
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Backup displays a success message after creating a backup
+func (r *JSONRenderer) Backup(backup *model.Backup) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Created backup %s", backup.Path),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Restore displays a success message after restoring a backup
+func (r *JSONRenderer) Restore(backup *model.Backup) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Restored database from backup %s", backup.Path),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Backups renders all backups as JSON
+func (r *JSONRenderer) Backups(backups []model.Backup) (string, error) {
+	b, err := json.Marshal(backups)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// DBPath renders the database in use and the config file it is set in
+func (r *JSONRenderer) DBPath(connection, configFile string) (string, error) {
+	b, err := json.Marshal(database{Connection: connection, ConfigFile: configFile})
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// SetDB displays a success message after setting the database in the config file
+func (r *JSONRenderer) SetDB(connection, configFile string) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Set database to %s in %s", connection, configFile),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// MoveDB displays a success message after moving the database
+func (r *JSONRenderer) MoveDB(from, to string) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Moved database from %s to %s", from, to),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Compaction renders the size of the database before and after compacting it as JSON
+func (r *JSONRenderer) Compaction(compaction *model.Compaction) (string, error) {
+	b, err := json.Marshal(compaction)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// IntegrityCheck renders the problems found while checking the database as JSON
+func (r *JSONRenderer) IntegrityCheck(check *model.IntegrityCheck) (string, error) {
+	b, err := json.Marshal(check)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// DBStats renders the statistics of the database as JSON
+func (r *JSONRenderer) DBStats(stats *model.DBStats) (string, error) {
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
+
+// Init displays a success message after setting up calories
+func (r *JSONRenderer) Init(connection, configFile string, configured bool) (string, error) {
+	res := success{
+		Success: true,
+		Message: fmt.Sprintf("Initialized database %s in %s", connection, configFile),
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal json, %v", err)
+	}
+	return string(b), nil
+}
@@ -3,6 +3,7 @@ package renderer
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,8 +47,8 @@ func TestJSONConfig(t *testing.T) {
 		Birthday:   now,
 		Gender:     "male",
 		UnitSystem: util.Metric,
-	}, &model.Weight{Weight: 85.0}, 2000.0, 1500.0, 18)
-	expected := fmt.Sprintf("{\"weight\":\"85.0 kg\",\"height\":\"185.0 cm\",\"activity\":1.5,\"birthday\":\"%s\",\"age\":18,\"gender\":\"male\",\"unitSystem\":\"metric\",\"amr\":2000,\"bmr\":1500}", now.Format(util.DateFormat))
+	}, &model.Weight{Weight: 85.0}, &model.BodyComposition{BMI: 24.8, BMICategory: "normal", HealthyWeightMin: 63.3, HealthyWeightMax: 85.2}, 2000.0, 1500.0, 18)
+	expected := fmt.Sprintf("{\"weight\":\"85.0 kg\",\"height\":\"185.0 cm\",\"bmi\":24.8,\"bmiCategory\":\"normal\",\"healthyWeightMin\":\"63.3 kg\",\"healthyWeightMax\":\"85.2 kg\",\"activity\":1.5,\"birthday\":\"%s\",\"age\":18,\"gender\":\"male\",\"unitSystem\":\"metric\",\"dateFormat\":\"dd.mm.yyyy\",\"timezone\":\"Local\",\"dayRollover\":0,\"budget\":\"daily\",\"waterTarget\":\"2000 ml\",\"eatingWindow\":\"none\",\"formula\":\"harris-benedict\",\"amr\":2000,\"bmr\":1500}", now.Format(util.DateFormat))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
 		return
@@ -69,7 +70,7 @@ func TestJSONDaysNoEntries(t *testing.T) {
 	r := JSONRenderer{}
 	days := model.Days{}
 	now := time.Now()
-	res, err := r.Days(days, now, now)
+	res, err := r.Days(days, now, now, nil)
 	expected := fmt.Sprintf("{\"From\":\"%s\",\"To\":\"%s\",\"Days\":[]}", now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
@@ -95,8 +96,8 @@ func TestJSONDaysEntries(t *testing.T) {
 		Date:    now,
 		Entries: entries,
 	})
-	res, err := r.Days(days, now, now)
-	expected := fmt.Sprintf("{\"From\":\"%s\",\"To\":\"%s\",\"Days\":[{\"entries\":[{\"id\":0,\"created\":\"%s\",\"entryDate\":\"%s\",\"calories\":1000,\"food\":\"Schnitzel\",\"bmr\":1500,\"amr\":2000}],\"used\":1000,\"date\":\"%s\"}]}", now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Format(util.DateFormat), now.Format(time.RFC3339Nano))
+	res, err := r.Days(days, now, now, nil)
+	expected := fmt.Sprintf("{\"From\":\"%s\",\"To\":\"%s\",\"Days\":[{\"entries\":[{\"id\":0,\"profileId\":0,\"created\":\"%s\",\"entryDate\":\"%s\",\"calories\":1000,\"food\":\"Schnitzel\",\"bmr\":1500,\"amr\":2000}],\"used\":1000,\"date\":\"%s\"}]}", now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Format(time.RFC3339Nano), now.Format(util.DateFormat), now.Format(time.RFC3339Nano))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
 		return
@@ -105,7 +106,7 @@ func TestJSONDaysEntries(t *testing.T) {
 
 func TestJSONAddEntry(t *testing.T) {
 	r := JSONRenderer{}
-	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel")
+	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel", "")
 	expectedString := "Added Entry for 01.01.2017 with 1000 calories (Schnitzel)"
 	expected := fmt.Sprintf("{\"success\":true,\"message\":\"%s\"}", expectedString)
 	if res != expected || err != nil {
@@ -146,3 +147,210 @@ func TestJSONImport(t *testing.T) {
 		return
 	}
 }
+
+func TestJSONProfiles(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Profiles([]model.Profile{{ID: 1, Name: "default", Active: true}})
+	expected := "[{\"id\":1,\"name\":\"default\",\"active\":true}]"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONUseProfile(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.UseProfile("anna")
+	expected := "{\"success\":true,\"message\":\"Switched to profile anna\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONConfigHistory(t *testing.T) {
+	r := JSONRenderer{}
+	effective, _ := time.Parse(util.DateFormat, "01.02.2017")
+	res, err := r.ConfigHistory([]model.Config{
+		{Effective: effective, Height: 185.0, Activity: 1.2, Gender: "male", UnitSystem: util.Metric},
+	})
+	expected := "[{\"effective\":\"2017-02-01T00:00:00Z\",\"formatted\":\"From 01.02.2017\",\"height\":\"185.0 cm\",\"activity\":1.2,\"gender\":\"male\",\"unitSystem\":\"metric\"}]"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONRecalcEmpty(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Recalc(nil, false)
+	expected := "{\"dryRun\":false,\"recalculations\":[]}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONStats(t *testing.T) {
+	r := JSONRenderer{}
+	from, _ := time.Parse(util.DateFormat, "01.01.2017")
+	res, err := r.Stats(&model.Stats{From: from, To: from, TopFoods: []model.FoodCount{}})
+	expected := "{\"from\":\"2017-01-01T00:00:00Z\",\"to\":\"2017-01-01T00:00:00Z\",\"loggedDays\":0,\"averageIntake\":0,\"averageDeficit\":0,\"daysUnderBudget\":0,\"daysOverBudget\":0,\"longestUnderBudgetStreak\":0,\"currentLoggingStreak\":0,\"topFoods\":[],\"weekdays\":null}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONCalendar(t *testing.T) {
+	r := JSONRenderer{}
+	month, _ := time.Parse(util.DateFormat, "01.02.2017")
+	days := model.Days{
+		&model.Day{Date: month.AddDate(0, 0, 1), Used: 2500, Entries: model.Entries{model.Entry{Calories: 2500, AMR: 2000}}},
+	}
+	res, err := r.Calendar(days, month)
+	expected := "{\"month\":\"2017-02\",\"weeks\":[{\"days\":[{\"date\":\"2017-02-01T00:00:00Z\",\"used\":0,\"amr\":0,\"status\":\"none\"},{\"date\":\"2017-02-02T00:00:00Z\",\"used\":2500,\"amr\":2000,\"status\":\"surplus\"},"
+	if err != nil || !strings.HasPrefix(res, expected) {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONSearchEmpty(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Search("apple", nil)
+	expected := "{\"query\":\"apple\",\"entries\":[],\"count\":0,\"calories\":0}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONNote(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Note("01.01.2017", []string{"cheatday"}, "", nil)
+	expected := "{\"success\":true,\"message\":\"Set note for 01.01.2017: #cheatday\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONWater(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Water("01.01.2017", &model.DayWater{Amount: 500, Target: 2000, UnitSystem: util.Metric})
+	expected := "{\"date\":\"01.01.2017\",\"amount\":500,\"target\":2000,\"unitSystem\":\"metric\",\"formattedAmount\":\"500 ml\",\"formattedTarget\":\"2000 ml\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONMeasurements(t *testing.T) {
+	r := JSONRenderer{}
+	created := time.Date(2017, 1, 1, 8, 0, 0, 0, time.UTC)
+	res, err := r.Measurements([]model.Measurement{{Created: created, Waist: 80, BodyFat: 18.5}}, &model.Config{UnitSystem: util.Metric})
+	expected := "[{\"created\":\"2017-01-01T08:00:00Z\",\"waist\":80,\"bodyFat\":18.5,\"formatted\":\"Waist: 80.0 cm, Body Fat: 18.5%\"}]"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONAddEntryWarning(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel", "outside")
+	expected := "{\"success\":true,\"message\":\"Added Entry for 01.01.2017 with 1000 calories (Schnitzel)\",\"warning\":\"outside\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONFasting(t *testing.T) {
+	r := JSONRenderer{}
+	start := time.Date(2017, 1, 1, 20, 0, 0, 0, time.UTC)
+	res, err := r.Fasting(&model.FastingHistory{
+		Fasts:         []model.Fast{{Start: start, End: start.Add(17 * time.Hour)}},
+		Goal:          16,
+		AverageHours:  17,
+		CurrentStreak: 1,
+		LongestStreak: 1,
+	})
+	expected := "{\"fasts\":[{\"start\":\"2017-01-01T20:00:00Z\",\"end\":\"2017-01-02T13:00:00Z\",\"hours\":17,\"reached\":true}],\"goal\":16,\"averageHours\":17,\"currentStreak\":1,\"longestStreak\":1}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONEncryption(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Encryption("calories.db", true)
+	expected := "{\"success\":true,\"message\":\"Encrypted database at calories.db\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONBackups(t *testing.T) {
+	r := JSONRenderer{}
+	backup := model.Backup{Path: "calories.db.20170105-120000.000.bak", Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC), Size: 2048}
+	res, err := r.Backup(&backup)
+	expected := "{\"success\":true,\"message\":\"Created backup calories.db.20170105-120000.000.bak\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Restore(&backup)
+	expected = "{\"success\":true,\"message\":\"Restored database from backup calories.db.20170105-120000.000.bak\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Backups([]model.Backup{backup})
+	expected = "[{\"path\":\"calories.db.20170105-120000.000.bak\",\"created\":\"2017-01-05T12:00:00Z\",\"size\":2048}]"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONDB(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.DBPath("calories.db", ".caloriesconf")
+	expected := "{\"connection\":\"calories.db\",\"configFile\":\".caloriesconf\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.MoveDB("calories.db", "moved.db")
+	expected = "{\"success\":true,\"message\":\"Moved database from calories.db to moved.db\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.IntegrityCheck(&model.IntegrityCheck{Path: "calories.db", Problems: []string{}})
+	expected = "{\"path\":\"calories.db\",\"problems\":[]}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.DBStats(&model.DBStats{Path: "calories.db", Size: 65536, Buckets: []model.BucketStats{{Name: "Entry", Records: 2, Size: 512}}})
+	expected = "{\"path\":\"calories.db\",\"size\":65536,\"buckets\":[{\"name\":\"Entry\",\"records\":2,\"size\":512}]}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestJSONInit(t *testing.T) {
+	r := JSONRenderer{}
+	res, err := r.Init("calories.db", "config.json", true)
+	expected := "{\"success\":true,\"message\":\"Initialized database calories.db in config.json\"}"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
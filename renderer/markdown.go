@@ -0,0 +1,80 @@
+package renderer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zupzup/calories/model"
+)
+
+// MarkdownRenderer is the renderer for Markdown reports, e.g. for wikis
+// Days, statistics, search results, weights, measurements and errors are rendered as Markdown,
+// all other output like in the terminal
+type MarkdownRenderer struct {
+	TerminalRenderer
+}
+
+// Error renders the given error with a heading
+func (r *MarkdownRenderer) Error(err error) (string, error) {
+	return markdown(errorReport(err)), nil
+}
+
+// WeightHistory renders a table of all weights with their date and value
+func (r *MarkdownRenderer) WeightHistory(weights []model.Weight, config *model.Config) (string, error) {
+	return markdown(weightReport(weights, config, r.DateFormat)), nil
+}
+
+// Days renders a table of the given days with their total, a table of their entries and the weekly budget
+func (r *MarkdownRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
+	return markdown(daysReport(days, from, to, budget, r.DateFormat)), nil
+}
+
+// Stats renders a table of the given statistics, a table of the most frequent foods and a table of the weekdays
+func (r *MarkdownRenderer) Stats(stats *model.Stats) (string, error) {
+	return markdown(statsReport(stats, r.DateFormat)), nil
+}
+
+// Search renders a table of the found entries with their total
+func (r *MarkdownRenderer) Search(query string, entries model.Entries) (string, error) {
+	return markdown(searchReport(query, entries, r.DateFormat)), nil
+}
+
+// Measurements renders a table of all measurements with their date and values
+func (r *MarkdownRenderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	return markdown(measurementsReport(measurements, config, r.DateFormat)), nil
+}
+
+// markdown renders the given sections with a heading and their text or table
+func markdown(sections []section) string {
+	var b strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", s.title)
+		if s.header == nil {
+			fmt.Fprintf(&b, "%s\n", s.text)
+			continue
+		}
+		b.WriteString(markdownRow(s.header))
+		separator := make([]string, len(s.header))
+		for j := range separator {
+			separator[j] = "---"
+		}
+		b.WriteString(markdownRow(separator))
+		for _, row := range s.rows {
+			b.WriteString(markdownRow(row))
+		}
+	}
+	return b.String()
+}
+
+// markdownRow renders a table row, escaping pipes in the cells
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.Replace(cell, "|", "\\|", -1)
+	}
+	return fmt.Sprintf("| %s |\n", strings.Join(escaped, " | "))
+}
@@ -0,0 +1,180 @@
+package renderer
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+func TestMarkdownWeightHistory(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	weights := []model.Weight{
+		{Weight: 85.0, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{Weight: 84.5, Created: time.Date(2017, 1, 6, 12, 0, 0, 0, time.UTC)},
+	}
+	res, err := r.WeightHistory(weights, &model.Config{UnitSystem: util.Metric})
+	expected := "## Weight over time\n\n| Date | Weight |\n| --- | --- |\n| 05.01.2017 | 85.0 kg |\n| 06.01.2017 | 84.5 kg |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.WeightHistory([]model.Weight{}, &model.Config{UnitSystem: util.Metric})
+	expected = "## Weight over time\n\nNo weights have been found.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownDays(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	from := time.Date(2017, 1, 5, 0, 0, 0, 0, time.UTC)
+	days := model.Days{
+		&model.Day{Date: from, Used: 700, Entries: model.Entries{
+			{Food: "Apple", Calories: 100, AMR: 2500},
+			{Food: "Pizza | large", Calories: 600, AMR: 2500},
+		}},
+		&model.Day{Date: from.AddDate(0, 0, 1)},
+		&model.Day{Date: from.AddDate(0, 0, 2), Used: 2600, Entries: model.Entries{
+			{Food: "Cake", Calories: 2600, AMR: 2400},
+		}},
+	}
+	budget := &model.WeeklyBudget{From: from, To: from.AddDate(0, 0, 6), Total: 17000, Used: 3300, UsedToday: 2600, Remaining: 13700, DaysLeft: 5, DailyAllowance: 2900}
+	res, err := r.Days(days, from, from.AddDate(0, 0, 2), budget)
+	expected := "## Data from 05.01.2017 to 07.01.2017\n\n" +
+		"| Date | Calories | AMR | Difference |\n| --- | --- | --- | --- |\n" +
+		"| 05.01.2017 | 700 | 2500 | 1800 deficit |\n| 07.01.2017 | 2600 | 2400 | 200 surplus |\n| Total | 3300 | 4900 | 1600 deficit |\n" +
+		"\n## Entries\n\n| Date | Food | Calories |\n| --- | --- | --- |\n" +
+		"| 05.01.2017 | Apple | 100 |\n| 05.01.2017 | Pizza \\| large | 600 |\n| 07.01.2017 | Cake | 2600 |\n" +
+		"\n## Weekly budget from 05.01.2017 to 11.01.2017\n\n| Budget | Value |\n| --- | --- |\n" +
+		"| Used | 3300 / 17000 calories |\n| Remaining | 13700 left |\n| Allowance | 2900 calories per day for the remaining 5 days |\n| Today | 300 left |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	budget = &model.WeeklyBudget{From: from, To: from.AddDate(0, 0, 6), Total: 17000, Used: 17500, Remaining: -500}
+	res, err = r.Days(model.Days{}, from, from, budget)
+	expected = "## Data from 05.01.2017 to 05.01.2017\n\nNo entries have been found.\n" +
+		"\n## Weekly budget from 05.01.2017 to 11.01.2017\n\n| Budget | Value |\n| --- | --- |\n" +
+		"| Used | 17500 / 17000 calories |\n| Remaining | 500 over budget |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownDaysWeeks(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	from := time.Date(2017, 1, 7, 0, 0, 0, 0, time.UTC)
+	days := model.Days{
+		&model.Day{Date: from, Used: 2000, Entries: model.Entries{{Food: "Pizza", Calories: 2000, AMR: 2500}}},
+		&model.Day{Date: from.AddDate(0, 0, 1), Used: 2600, Entries: model.Entries{{Food: "Cake", Calories: 2600, AMR: 2500}}},
+		&model.Day{Date: from.AddDate(0, 0, 2), Used: 1500, Entries: model.Entries{{Food: "Salad", Calories: 1500, AMR: 2400}}},
+	}
+	res, err := r.Days(days, from, from.AddDate(0, 0, 2), nil)
+	expected := "## Data from 07.01.2017 to 09.01.2017\n\n" +
+		"| Date | Calories | AMR | Difference |\n| --- | --- | --- | --- |\n" +
+		"| 07.01.2017 | 2000 | 2500 | 500 deficit |\n| 08.01.2017 | 2600 | 2500 | 100 surplus |\n| 09.01.2017 | 1500 | 2400 | 900 deficit |\n| Total | 6100 | 7400 | 1300 deficit |\n" +
+		"\n## Weeks\n\n| Week | Calories | AMR | Difference |\n| --- | --- | --- | --- |\n" +
+		"| 02.01.2017 - 08.01.2017 | 4600 | 5000 | 400 deficit |\n| 09.01.2017 - 15.01.2017 | 1500 | 2400 | 900 deficit |\n" +
+		"\n## Entries\n\n| Date | Food | Calories |\n| --- | --- | --- |\n" +
+		"| 07.01.2017 | Pizza | 2000 |\n| 08.01.2017 | Cake | 2600 |\n| 09.01.2017 | Salad | 1500 |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownError(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{}}
+	res, err := r.Error(errors.New("someError"))
+	expected := "## Error\n\nsomeError\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownStats(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	from := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC)
+	stats := &model.Stats{
+		From:                     from,
+		To:                       from.AddDate(0, 0, 1),
+		LoggedDays:               2,
+		AverageIntake:            2100,
+		AverageDeficit:           -100,
+		DaysUnderBudget:          1,
+		DaysOverBudget:           1,
+		LongestUnderBudgetStreak: 1,
+		CurrentLoggingStreak:     2,
+		TopFoods:                 []model.FoodCount{{Food: "Pizza", Count: 2, Calories: 1600}},
+		Weekdays: []model.WeekdayStats{
+			{Weekday: "Monday", Days: 1, AverageIntake: 1800, AverageDeficit: 200},
+			{Weekday: "Tuesday", Days: 1, AverageIntake: 2400, AverageDeficit: -400},
+			{Weekday: "Wednesday"},
+		},
+	}
+	res, err := r.Stats(stats)
+	expected := "## Statistics from 02.01.2017 to 03.01.2017\n\n| Statistic | Value |\n| --- | --- |\n" +
+		"| Days with entries | 2 |\n| Average intake | 2100 calories |\n| Average difference | 100 surplus |\n| Days under budget | 1 |\n| Days over budget | 1 |\n" +
+		"| Longest streak under budget | 1 days |\n| Current logging streak | 2 days |\n" +
+		"\n## Most frequent foods\n\n| Food | Count | Calories |\n| --- | --- | --- |\n| Pizza | 2 | 1600 |\n" +
+		"\n## Weekdays\n\n| Weekday | Days | Average intake | Average difference |\n| --- | --- | --- | --- |\n" +
+		"| Monday | 1 | 1800 | 200 deficit |\n| Tuesday | 1 | 2400 | 400 surplus |\n| Wednesday | 0 | no entries |  |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Stats(&model.Stats{From: from, To: from})
+	expected = "## Statistics from 02.01.2017 to 02.01.2017\n\nNo entries have been found.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownSearch(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "yyyy-mm-dd"}}
+	entries := model.Entries{
+		{EntryDate: "05.01.2017", Food: "Pizza", Calories: 600},
+		{EntryDate: "07.01.2017", Food: "Pizza | large", Calories: 900},
+	}
+	res, err := r.Search("pizza", entries)
+	expected := "## Entries for \"pizza\"\n\n| Date | Food | Calories |\n| --- | --- | --- |\n" +
+		"| 2017-01-05 | Pizza | 600 |\n| 2017-01-07 | Pizza \\| large | 900 |\n| Total | 2 entries | 1500 |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Search("pizza", model.Entries{})
+	expected = "## Entries for \"pizza\"\n\nNo entries have been found.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestMarkdownMeasurements(t *testing.T) {
+	r := MarkdownRenderer{TerminalRenderer{DateFormat: "dd.mm.yyyy"}}
+	measurements := []model.Measurement{
+		{Waist: 90.0, BodyFat: 18.5, Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{Hip: 100.0, Thigh: 60.0, Created: time.Date(2017, 1, 6, 12, 0, 0, 0, time.UTC)},
+	}
+	res, err := r.Measurements(measurements, &model.Config{UnitSystem: util.Metric})
+	expected := "## Measurements over time\n\n| Date | Waist | Hip | Chest | Neck | Arm | Thigh | Body Fat |\n| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+		"| 05.01.2017 | 90.0 cm |  |  |  |  |  | 18.5% |\n| 06.01.2017 |  | 100.0 cm |  |  |  | 60.0 cm |  |\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Measurements([]model.Measurement{}, &model.Config{UnitSystem: util.Metric})
+	expected = "## Measurements over time\n\nNo measurements have been found.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
@@ -10,10 +10,37 @@ type Renderer interface {
 	Error(err error) (string, error)
 	WeightHistory(weights []model.Weight, config *model.Config) (string, error)
 	AddWeight(weight float64, config *model.Config) (string, error)
-	Config(config *model.Config, weight *model.Weight, amr, bmr float64, age int) (string, error)
-	Days(days model.Days, from, to time.Time) (string, error)
-	AddEntry(date string, calories int, food string) (string, error)
+	Config(config *model.Config, weight *model.Weight, composition *model.BodyComposition, amr, bmr float64, age int) (string, error)
+	ConfigHistory(configs []model.Config) (string, error)
+	Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error)
+	AddEntry(date string, calories int, food, warning string) (string, error)
 	ClearEntries(date string) (string, error)
 	ClearEntry(date string, entry *model.Entry) (string, error)
 	Import(fileName string, numEntries, numWeights int) (string, error)
+	Recalc(recalculations []model.Recalculation, dryRun bool) (string, error)
+	Stats(stats *model.Stats) (string, error)
+	Calendar(days model.Days, month time.Time) (string, error)
+	Search(query string, entries model.Entries) (string, error)
+	Note(date string, tags []string, note string, entry *model.Entry) (string, error)
+	Water(date string, water *model.DayWater) (string, error)
+	AddWater(date string, amount float64, water *model.DayWater) (string, error)
+	Measurements(measurements []model.Measurement, config *model.Config) (string, error)
+	AddMeasurement(measurement *model.Measurement, config *model.Config) (string, error)
+	Fasting(history *model.FastingHistory) (string, error)
+	StartFast(fast *model.Fast) (string, error)
+	StopFast(fast *model.Fast, goal float64) (string, error)
+	Profiles(profiles []model.Profile) (string, error)
+	AddProfile(name string) (string, error)
+	UseProfile(name string) (string, error)
+	Encryption(path string, encrypted bool) (string, error)
+	Backup(backup *model.Backup) (string, error)
+	Restore(backup *model.Backup) (string, error)
+	Backups(backups []model.Backup) (string, error)
+	DBPath(connection, configFile string) (string, error)
+	SetDB(connection, configFile string) (string, error)
+	MoveDB(from, to string) (string, error)
+	Compaction(compaction *model.Compaction) (string, error)
+	IntegrityCheck(check *model.IntegrityCheck) (string, error)
+	DBStats(stats *model.DBStats) (string, error)
+	Init(connection, configFile string, configured bool) (string, error)
 }
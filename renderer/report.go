@@ -0,0 +1,219 @@
+package renderer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/zupzup/calories/model"
+	"github.com/zupzup/calories/util"
+)
+
+// section is a part of a report with a title and either a text or a table
+type section struct {
+	title  string
+	text   string
+	header []string
+	rows   [][]string
+}
+
+// daysReport creates the sections for the given days: a summary of every day with the total,
+// a summary of every week, if the days span more than one week, all entries and the weekly budget,
+// if there is one
+func daysReport(days model.Days, from, to time.Time, budget *model.WeeklyBudget, dateFormat string) []section {
+	title := fmt.Sprintf("Data from %s to %s", util.FormatDate(from, dateFormat), util.FormatDate(to, dateFormat))
+	sections := []section{}
+	summary := [][]string{}
+	entries := [][]string{}
+	sumAMR := 0.0
+	sumCalories := 0
+	for _, day := range days {
+		if len(day.Entries) == 0 {
+			continue
+		}
+		date := util.FormatDate(day.Date, dateFormat)
+		sumAMR += day.AMR()
+		sumCalories += day.Used
+		summary = append(summary, []string{date, fmt.Sprintf("%d", day.Used), fmt.Sprintf("%.0f", day.AMR()), difference(day.AMR(), day.Used)})
+		for _, entry := range day.Entries {
+			entries = append(entries, []string{date, entry.Food, fmt.Sprintf("%d", entry.Calories)})
+		}
+	}
+	if len(summary) == 0 {
+		sections = append(sections, section{title: title, text: "No entries have been found."})
+	} else {
+		summary = append(summary, []string{"Total", fmt.Sprintf("%d", sumCalories), fmt.Sprintf("%.0f", sumAMR), difference(sumAMR, sumCalories)})
+		sections = append(sections, section{title: title, header: []string{"Date", "Calories", "AMR", "Difference"}, rows: summary})
+		if !util.GetBeginningOfWeek(util.TruncateToDate(from)).Equal(util.GetBeginningOfWeek(util.TruncateToDate(to))) {
+			sections = append(sections, weeksSection(days, dateFormat))
+		}
+		sections = append(sections, section{title: "Entries", header: []string{"Date", "Food", "Calories"}, rows: entries})
+	}
+	if budget != nil {
+		sections = append(sections, weeklyBudgetSection(budget, dateFormat))
+	}
+	return sections
+}
+
+// weeksSection creates the section with the total calories, AMR and difference of every week
+// of the given days, which need to be sorted by date
+func weeksSection(days model.Days, dateFormat string) section {
+	rows := [][]string{}
+	var week time.Time
+	sumAMR := 0.0
+	sumCalories := 0
+	addWeek := func() {
+		if !week.IsZero() {
+			rows = append(rows, []string{fmt.Sprintf("%s - %s", util.FormatDate(week, dateFormat), util.FormatDate(week.AddDate(0, 0, 6), dateFormat)), fmt.Sprintf("%d", sumCalories), fmt.Sprintf("%.0f", sumAMR), difference(sumAMR, sumCalories)})
+		}
+	}
+	for _, day := range days {
+		if len(day.Entries) == 0 {
+			continue
+		}
+		beginning := util.GetBeginningOfWeek(util.TruncateToDate(day.Date))
+		if !beginning.Equal(week) {
+			addWeek()
+			week = beginning
+			sumAMR = 0
+			sumCalories = 0
+		}
+		sumAMR += day.AMR()
+		sumCalories += day.Used
+	}
+	addWeek()
+	return section{title: "Weeks", header: []string{"Week", "Calories", "AMR", "Difference"}, rows: rows}
+}
+
+// weeklyBudgetSection creates the section with the used and remaining budget of the week
+// and the allowance for the remaining days
+func weeklyBudgetSection(budget *model.WeeklyBudget, dateFormat string) section {
+	rows := [][]string{
+		{"Used", fmt.Sprintf("%d / %.0f calories", budget.Used, budget.Total)},
+	}
+	if budget.Remaining < 0 {
+		rows = append(rows, []string{"Remaining", fmt.Sprintf("%.0f over budget", budget.Remaining*-1)})
+	} else {
+		leftToday := budget.DailyAllowance - float64(budget.UsedToday)
+		today := fmt.Sprintf("%.0f left", leftToday)
+		if leftToday < 0 {
+			today = fmt.Sprintf("%.0f over", leftToday*-1)
+		}
+		rows = append(rows,
+			[]string{"Remaining", fmt.Sprintf("%.0f left", budget.Remaining)},
+			[]string{"Allowance", fmt.Sprintf("%.0f calories per day for the remaining %d days", budget.DailyAllowance, budget.DaysLeft)},
+			[]string{"Today", today},
+		)
+	}
+	return section{
+		title:  fmt.Sprintf("Weekly budget from %s to %s", util.FormatDate(budget.From, dateFormat), util.FormatDate(budget.To, dateFormat)),
+		header: []string{"Budget", "Value"},
+		rows:   rows,
+	}
+}
+
+// weightReport creates the section with all weights in the unit system of the config
+func weightReport(weights []model.Weight, config *model.Config, dateFormat string) []section {
+	if len(weights) == 0 {
+		return []section{{title: "Weight over time", text: "No weights have been found."}}
+	}
+	rows := [][]string{}
+	for _, weight := range weights {
+		rows = append(rows, []string{util.FormatDate(weight.Created, dateFormat), util.WeightUnit(config.UnitSystem, weight.Weight)})
+	}
+	return []section{{title: "Weight over time", header: []string{"Date", "Weight"}, rows: rows}}
+}
+
+// errorReport creates the section with the given error
+func errorReport(err error) []section {
+	return []section{{title: "Error", text: err.Error()}}
+}
+
+// statsReport creates the sections for the given statistics: the summary, the most frequent foods
+// and the averages of every weekday
+func statsReport(stats *model.Stats, dateFormat string) []section {
+	title := fmt.Sprintf("Statistics from %s to %s", util.FormatDate(stats.From, dateFormat), util.FormatDate(stats.To, dateFormat))
+	if stats.LoggedDays == 0 {
+		return []section{{title: title, text: "No entries have been found."}}
+	}
+	summary := [][]string{
+		{"Days with entries", fmt.Sprintf("%d", stats.LoggedDays)},
+		{"Average intake", fmt.Sprintf("%.0f calories", stats.AverageIntake)},
+		{"Average difference", deficitText(stats.AverageDeficit)},
+		{"Days under budget", fmt.Sprintf("%d", stats.DaysUnderBudget)},
+		{"Days over budget", fmt.Sprintf("%d", stats.DaysOverBudget)},
+		{"Longest streak under budget", fmt.Sprintf("%d days", stats.LongestUnderBudgetStreak)},
+		{"Current logging streak", fmt.Sprintf("%d days", stats.CurrentLoggingStreak)},
+	}
+	foods := [][]string{}
+	for _, food := range stats.TopFoods {
+		foods = append(foods, []string{food.Food, fmt.Sprintf("%d", food.Count), fmt.Sprintf("%d", food.Calories)})
+	}
+	weekdays := [][]string{}
+	for _, weekday := range stats.Weekdays {
+		if weekday.Days == 0 {
+			weekdays = append(weekdays, []string{weekday.Weekday, "0", "no entries", ""})
+			continue
+		}
+		weekdays = append(weekdays, []string{weekday.Weekday, fmt.Sprintf("%d", weekday.Days), fmt.Sprintf("%.0f", weekday.AverageIntake), deficitText(weekday.AverageDeficit)})
+	}
+	return []section{
+		{title: title, header: []string{"Statistic", "Value"}, rows: summary},
+		{title: "Most frequent foods", header: []string{"Food", "Count", "Calories"}, rows: foods},
+		{title: "Weekdays", header: []string{"Weekday", "Days", "Average intake", "Average difference"}, rows: weekdays},
+	}
+}
+
+// measurementsReport creates the section with all measurements in the unit system of the config
+func measurementsReport(measurements []model.Measurement, config *model.Config, dateFormat string) []section {
+	if len(measurements) == 0 {
+		return []section{{title: "Measurements over time", text: "No measurements have been found."}}
+	}
+	length := func(value float64) string {
+		if value <= 0 {
+			return ""
+		}
+		return util.LengthUnit(config.UnitSystem, value)
+	}
+	rows := [][]string{}
+	for _, m := range measurements {
+		bodyFat := ""
+		if m.BodyFat > 0 {
+			bodyFat = fmt.Sprintf("%.1f%%", m.BodyFat)
+		}
+		rows = append(rows, []string{util.FormatDate(m.Created, dateFormat), length(m.Waist), length(m.Hip), length(m.Chest), length(m.Neck), length(m.Arm), length(m.Thigh), bodyFat})
+	}
+	return []section{{
+		title:  "Measurements over time",
+		header: []string{"Date", "Waist", "Hip", "Chest", "Neck", "Arm", "Thigh", "Body Fat"},
+		rows:   rows,
+	}}
+}
+
+// searchReport creates the section with the entries found for the given query and their total
+func searchReport(query string, entries model.Entries, dateFormat string) []section {
+	title := fmt.Sprintf("Entries for \"%s\"", query)
+	if len(entries) == 0 {
+		return []section{{title: title, text: "No entries have been found."}}
+	}
+	rows := [][]string{}
+	sumCalories := 0
+	for _, entry := range entries {
+		sumCalories += entry.Calories
+		rows = append(rows, []string{util.DisplayDate(entry.EntryDate, dateFormat), entry.Food, fmt.Sprintf("%d", entry.Calories)})
+	}
+	rows = append(rows, []string{"Total", fmt.Sprintf("%d entries", len(entries)), fmt.Sprintf("%d", sumCalories)})
+	return []section{{title: title, header: []string{"Date", "Food", "Calories"}, rows: rows}}
+}
+
+// difference formats the difference between the given AMR and calories as deficit or surplus
+func difference(amr float64, calories int) string {
+	return deficitText(amr - float64(calories))
+}
+
+// deficitText formats the given deficit as deficit, or as surplus, if it is negative
+func deficitText(deficit float64) string {
+	if deficit < 0 {
+		return fmt.Sprintf("%.0f surplus", deficit*-1)
+	}
+	return fmt.Sprintf("%.0f deficit", deficit)
+}
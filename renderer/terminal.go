@@ -2,6 +2,7 @@ package renderer
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -9,8 +10,11 @@ import (
 	"github.com/zupzup/calories/util"
 )
 
-// TerminalRenderer is the renderer for the CLI
-type TerminalRenderer struct{}
+// TerminalRenderer is the renderer for the CLI, DateFormat is the display date
+// format used for printing dates
+type TerminalRenderer struct {
+	DateFormat string
+}
 
 // Error renders an error
 func (r *TerminalRenderer) Error(err error) (string, error) {
@@ -21,7 +25,7 @@ func (r *TerminalRenderer) Error(err error) (string, error) {
 func (r *TerminalRenderer) WeightHistory(weights []model.Weight, config *model.Config) (string, error) {
 	var res string
 	for _, weight := range weights {
-		res += fmt.Sprintf("\t%s: %s\n", weight.Created.Format(util.DateFormat), util.WeightUnit(config.UnitSystem, weight.Weight))
+		res += fmt.Sprintf("\t%s: %s\n", util.FormatDate(weight.Created, r.DateFormat), util.WeightUnit(config.UnitSystem, weight.Weight))
 	}
 	return fmt.Sprintf("Weight over time:\n%s\n", res), nil
 }
@@ -31,23 +35,81 @@ func (r *TerminalRenderer) AddWeight(weight float64, config *model.Config) (stri
 	return fmt.Sprintf("Set weight: %s \n", util.WeightUnit(config.UnitSystem, weight)), nil
 }
 
-// Config prints the given configuration with weight, amr and bmr
-func (r *TerminalRenderer) Config(config *model.Config, weight *model.Weight, amr, bmr float64, age int) (string, error) {
-	return fmt.Sprintf("Current Config:\n\tWeight: %s \n\tHeight: %s \n\tActivity: %.1f \n\tBirthday: %s (%d)\n\tGender: %s\n\tUnit System: %s\n\tAMR (BMR): %.0f (%.0f) calories per day\n",
-		util.WeightUnit(config.UnitSystem, weight.Weight), util.HeightUnit(config.UnitSystem, config.Height), config.Activity, config.Birthday.Format(util.DateFormat), age, config.Gender, config.UnitSystem, amr, bmr), nil
+// Config prints the given configuration with weight, body composition, amr and bmr
+func (r *TerminalRenderer) Config(config *model.Config, weight *model.Weight, composition *model.BodyComposition, amr, bmr float64, age int) (string, error) {
+	return fmt.Sprintf("Current Config:\n\tWeight: %s \n\tHeight: %s \n%s\tActivity: %.1f \n\tBirthday: %s (%d)\n\tGender: %s\n\tUnit System: %s\n\tDate Format: %s\n\tTimezone: %s (day starts at %d:00)\n\tBudget: %s\n\tWater Target: %s per day\n\tEating Window: %s\n\tFormula: %s\n\tAMR (BMR): %.0f (%.0f) calories per day\n",
+		util.WeightUnit(config.UnitSystem, weight.Weight), util.HeightUnit(config.UnitSystem, config.Height), bodyComposition(composition, config.UnitSystem), config.Activity, util.FormatDate(config.Birthday, r.DateFormat), age, config.Gender, config.UnitSystem, util.NormalizeDateFormat(config.DateFormat), timezoneName(config.Timezone), config.DayRollover, budgetMode(config.Budget), util.WaterUnit(config.UnitSystem, waterTarget(config.WaterTarget)), util.FormatEatingWindow(config.EatingWindow), formula(config.Formula), amr, bmr), nil
+}
+
+// bodyComposition formats the BMI, the healthy weight range and, if the body fat is known, the lean mass and FFMI
+func bodyComposition(composition *model.BodyComposition, unitSystem string) string {
+	res := fmt.Sprintf("\tBMI: %.1f (%s)\n\tHealthy Weight: %s - %s\n", composition.BMI, composition.BMICategory, util.WeightUnit(unitSystem, composition.HealthyWeightMin), util.WeightUnit(unitSystem, composition.HealthyWeightMax))
+	if composition.BodyFat > 0 {
+		res += fmt.Sprintf("\tBody Fat: %.1f%%\n\tLean Mass: %s (FFMI: %.1f)\n", composition.BodyFat, util.WeightUnit(unitSystem, composition.LeanMass), composition.FFMI)
+	}
+	return res
+}
+
+// ConfigHistory prints all config versions with the date they are effective from
+func (r *TerminalRenderer) ConfigHistory(configs []model.Config) (string, error) {
+	var res string
+	for _, config := range configs {
+		res += fmt.Sprintf("\t%s: Height: %s, Activity: %.1f, Gender: %s, Unit System: %s\n", effectiveDate(config.Effective, r.DateFormat), util.HeightUnit(config.UnitSystem, config.Height), config.Activity, config.Gender, config.UnitSystem)
+	}
+	return fmt.Sprintf("Config over time:\n%s\n", res), nil
+}
+
+// effectiveDate formats the date a config is effective from
+func effectiveDate(effective time.Time, dateFormat string) string {
+	if effective.IsZero() {
+		return "Initial"
+	}
+	return fmt.Sprintf("From %s", util.FormatDate(effective, dateFormat))
+}
+
+// waterTarget returns the given water target, or the default water target if none is set
+func waterTarget(target float64) float64 {
+	if target <= 0 {
+		return util.DefaultWaterTarget
+	}
+	return target
+}
+
+// formula returns the formula used for the metabolic rates, which is harris-benedict by default
+func formula(formula string) string {
+	if formula == "" {
+		return util.HarrisBenedict
+	}
+	return formula
 }
 
-// Days renders the days in the given timespan
-func (r *TerminalRenderer) Days(days model.Days, from, to time.Time) (string, error) {
-	res := fmt.Sprintf("Data from %s to %s:\n-----------------------------------\n", from.Format(util.DateFormat), to.Format(util.DateFormat))
+// budgetMode returns the budget mode of the config, which is daily by default
+func budgetMode(budget string) string {
+	if budget == "" {
+		return util.DailyBudget
+	}
+	return budget
+}
+
+// timezoneName returns the given timezone name, or "Local" if none is set
+func timezoneName(timezone string) string {
+	if timezone == "" {
+		return time.Local.String()
+	}
+	return timezone
+}
+
+// Days renders the days in the given timespan and the weekly budget, if it is given
+func (r *TerminalRenderer) Days(days model.Days, from, to time.Time, budget *model.WeeklyBudget) (string, error) {
+	res := fmt.Sprintf("Data from %s to %s:\n-----------------------------------\n", util.FormatDate(from, r.DateFormat), util.FormatDate(to, r.DateFormat))
 	if len(days) > 0 {
 		var formattedDays string
 		sumAMR := 0.0
 		sumCalories := 0
 		for _, day := range days {
-			sumAMR += getAMR(day)
+			sumAMR += day.AMR()
 			sumCalories += day.Used
-			formattedDays += stringifyDay(day)
+			formattedDays += stringifyDay(day, r.DateFormat)
 		}
 		defSur := color.GreenString("deficit")
 		result := sumAMR - float64(sumCalories)
@@ -61,54 +123,425 @@ func (r *TerminalRenderer) Days(days model.Days, from, to time.Time) (string, er
 		}
 
 		formattedDays += fmt.Sprintf("-----------------------------------\n%s / %.0f calories = %s %s\n", formattedCalories, sumAMR, formattedResult, defSur)
-		return fmt.Sprintf("%s%s", res, formattedDays), nil
+		return fmt.Sprintf("%s%s%s", res, formattedDays, r.weeklyBudget(budget)), nil
 	}
-	return fmt.Sprintf("%sNo entries have been found.\n", res), nil
+	return fmt.Sprintf("%sNo entries have been found.\n%s", res, r.weeklyBudget(budget)), nil
 }
 
-// AddEntry displays a success message after adding an entry
-func (r *TerminalRenderer) AddEntry(date string, calories int, food string) (string, error) {
-	return fmt.Sprintf("Added Entry for %s with %d calories (%s)\n", date, calories, food), nil
+// weeklyBudget renders the used and remaining budget of the week and the allowance for
+// the remaining days, if there is a weekly budget
+func (r *TerminalRenderer) weeklyBudget(budget *model.WeeklyBudget) string {
+	if budget == nil {
+		return ""
+	}
+	res := fmt.Sprintf("\nWeekly budget from %s to %s:\n", util.FormatDate(budget.From, r.DateFormat), util.FormatDate(budget.To, r.DateFormat))
+	if budget.Remaining < 0 {
+		res += fmt.Sprintf("\t%s / %.0f calories used, %s\n", color.RedString("%d", budget.Used), budget.Total, color.RedString("%.0f over budget", budget.Remaining*-1))
+		return res
+	}
+	res += fmt.Sprintf("\t%s / %.0f calories used, %s\n", color.GreenString("%d", budget.Used), budget.Total, color.GreenString("%.0f left", budget.Remaining))
+	leftToday := budget.DailyAllowance - float64(budget.UsedToday)
+	formattedToday := color.GreenString("%.0f left today", leftToday)
+	if leftToday < 0 {
+		formattedToday = color.RedString("%.0f over today", leftToday*-1)
+	}
+	res += fmt.Sprintf("\tAllowance: %.0f calories per day for the remaining %d days (%s)\n", budget.DailyAllowance, budget.DaysLeft, formattedToday)
+	return res
+}
+
+// AddEntry displays a success message after adding an entry and the given warning, if there is one
+func (r *TerminalRenderer) AddEntry(date string, calories int, food, warning string) (string, error) {
+	res := fmt.Sprintf("Added Entry for %s with %d calories (%s)\n", util.DisplayDate(date, r.DateFormat), calories, food)
+	if warning != "" {
+		res += color.YellowString("Warning: %s", warning) + "\n"
+	}
+	return res, nil
 }
 
 // ClearEntries displays a success message after clearing the entries for a day
 func (r *TerminalRenderer) ClearEntries(date string) (string, error) {
-	return fmt.Sprintf("Cleared all entries for %s\n", date), nil
+	return fmt.Sprintf("Cleared all entries for %s\n", util.DisplayDate(date, r.DateFormat)), nil
 }
 
 // ClearEntry displays a success message after clearing the entry at a given position for a day
 func (r *TerminalRenderer) ClearEntry(date string, entry *model.Entry) (string, error) {
-	return fmt.Sprintf("Cleared entry %d %s for %s\n", entry.Calories, entry.Food, date), nil
+	return fmt.Sprintf("Cleared entry %d %s for %s\n", entry.Calories, entry.Food, util.DisplayDate(date, r.DateFormat)), nil
 }
 
 // stringifyDay turns a model.Day into it's terminal string representation
-func stringifyDay(d *model.Day) string {
+// The note and the tags of the day are shown, even if there are no entries on that day
+func stringifyDay(d *model.Day, dateFormat string) string {
+	res := util.FormatDate(d.Date, dateFormat)
+	if d.Note != nil && len(d.Note.Tags) > 0 {
+		res += " " + util.FormatTags(d.Note.Tags)
+	}
+	res += "\n"
+	if d.Note != nil && d.Note.Note != "" {
+		res += fmt.Sprintf("\tNote: %s\n", d.Note.Note)
+	}
+	for _, entry := range d.Entries {
+		res += fmt.Sprintf("\t%d %s", entry.Calories, entry.Food)
+		if len(entry.Tags) > 0 {
+			res += " " + util.FormatTags(entry.Tags)
+		}
+		if entry.Note != "" {
+			res += fmt.Sprintf(" (%s)", entry.Note)
+		}
+		res += "\n"
+	}
+	if len(d.Entries) > 0 {
+		calorieString := color.GreenString("%d", d.Used)
+		if float64(d.Used) > d.AMR() {
+			calorieString = color.RedString("%d", d.Used)
+		}
+		res += fmt.Sprintf("\t---------------------\n\t%s / %.0f calories\n", calorieString, d.AMR())
+	}
+	if d.Water != nil {
+		res += fmt.Sprintf("\tWater: %s\n", waterStatus(d.Water))
+	}
+	return res
+}
+
+// Import displays a success message after importing from a file
+func (r *TerminalRenderer) Import(fileName string, numEntries, numWeights int) (string, error) {
+	return fmt.Sprintf("Imported data from %s with %d entries and %d weights\n", fileName, numEntries, numWeights), nil
+}
+
+// Recalc renders the changed metabolic rates of all recalculated entries
+func (r *TerminalRenderer) Recalc(recalculations []model.Recalculation, dryRun bool) (string, error) {
 	var res string
-	for i, entry := range d.Entries {
+	for _, rec := range recalculations {
+		res += fmt.Sprintf("\t%s %d %s: AMR %.0f -> %.0f, BMR %.0f -> %.0f\n", util.DisplayDate(rec.Entry.EntryDate, r.DateFormat), rec.Entry.Calories, rec.Entry.Food, rec.OldAMR, rec.NewAMR, rec.OldBMR, rec.NewBMR)
+	}
+	if dryRun {
+		return fmt.Sprintf("Dry run, %d entries would be recalculated:\n%s", len(recalculations), res), nil
+	}
+	return fmt.Sprintf("Recalculated %d entries:\n%s", len(recalculations), res), nil
+}
+
+// Stats renders the statistics for a range of days
+func (r *TerminalRenderer) Stats(stats *model.Stats) (string, error) {
+	res := fmt.Sprintf("Statistics from %s to %s:\n-----------------------------------\n", util.FormatDate(stats.From, r.DateFormat), util.FormatDate(stats.To, r.DateFormat))
+	if stats.LoggedDays == 0 {
+		return fmt.Sprintf("%sNo entries have been found.\n", res), nil
+	}
+	res += fmt.Sprintf("Days with entries: %d\n", stats.LoggedDays)
+	res += fmt.Sprintf("Average intake: %.0f calories\n", stats.AverageIntake)
+	res += fmt.Sprintf("Average %s\n", deficitString(stats.AverageDeficit))
+	res += fmt.Sprintf("Days under budget: %s\n", color.GreenString("%d", stats.DaysUnderBudget))
+	res += fmt.Sprintf("Days over budget: %s\n", color.RedString("%d", stats.DaysOverBudget))
+	res += fmt.Sprintf("Longest streak under budget: %d days\n", stats.LongestUnderBudgetStreak)
+	res += fmt.Sprintf("Current logging streak: %d days\n", stats.CurrentLoggingStreak)
+	res += "Most frequent foods:\n"
+	for _, food := range stats.TopFoods {
+		res += fmt.Sprintf("\t%dx %s (%d calories)\n", food.Count, food.Food, food.Calories)
+	}
+	res += "Weekdays:\n"
+	for _, weekday := range stats.Weekdays {
+		if weekday.Days == 0 {
+			res += fmt.Sprintf("\t%s: no entries\n", weekday.Weekday)
+			continue
+		}
+		res += fmt.Sprintf("\t%s: %.0f calories, %s (%d days)\n", weekday.Weekday, weekday.AverageIntake, deficitString(weekday.AverageDeficit), weekday.Days)
+	}
+	return res, nil
+}
+
+// deficitString formats the given deficit as a green deficit, or as a red surplus, if it is negative
+func deficitString(deficit float64) string {
+	if deficit < 0 {
+		return fmt.Sprintf("%s %s", color.RedString("%.0f", deficit*-1), color.RedString("surplus"))
+	}
+	return fmt.Sprintf("%s %s", color.GreenString("%.0f", deficit), color.GreenString("deficit"))
+}
+
+// Calendar renders the given month as a grid, in which days with a deficit are green
+// and days with a surplus are red, with the total deficit or surplus of each week
+func (r *TerminalRenderer) Calendar(days model.Days, month time.Time) (string, error) {
+	res := fmt.Sprintf("Calendar for %s:\n-----------------------------------\n  Mo  Tu  We  Th  Fr  Sa  Su\n", month.Format("January 2006"))
+	total := 0.0
+	for i, week := range calendarWeeks(days, month) {
 		if i == 0 {
-			res += fmt.Sprintf("%s\n", entry.EntryDate)
+			for j := 0; j < (int(week.Days[0].Date.Weekday())+6)%7; j++ {
+				res += "    "
+			}
 		}
-		res += fmt.Sprintf("\t%d %s\n", entry.Calories, entry.Food)
-		if i == len(d.Entries)-1 {
-			calorieString := color.GreenString("%d", d.Used)
-			if float64(d.Used) > getAMR(d) {
-				calorieString = color.RedString("%d", d.Used)
+		for _, day := range week.Days {
+			switch day.Status {
+			case statusDeficit:
+				res += color.GreenString("%4d", day.Date.Day())
+			case statusSurplus:
+				res += color.RedString("%4d", day.Date.Day())
+			default:
+				res += fmt.Sprintf("%4d", day.Date.Day())
 			}
-			res += fmt.Sprintf("\t---------------------\n\t%s / %.0f calories\n", calorieString, getAMR(d))
 		}
+		for j := len(week.Days); i > 0 && j < 7; j++ {
+			res += "    "
+		}
+		if week.Logged == 0 {
+			res += " | -\n"
+			continue
+		}
+		total += week.Deficit
+		res += fmt.Sprintf(" | %s\n", deficitString(week.Deficit))
+	}
+	return fmt.Sprintf("%s-----------------------------------\nMonth: %s\n", res, deficitString(total)), nil
+}
+
+// Search renders the entries found for the given query with their dates and totals
+func (r *TerminalRenderer) Search(query string, entries model.Entries) (string, error) {
+	if len(entries) == 0 {
+		return fmt.Sprintf("No entries have been found for \"%s\".\n", query), nil
+	}
+	res := fmt.Sprintf("Entries for \"%s\":\n-----------------------------------\n", query)
+	sumCalories := 0
+	for _, entry := range entries {
+		sumCalories += entry.Calories
+		res += fmt.Sprintf("%s\t%d %s\n", util.DisplayDate(entry.EntryDate, r.DateFormat), entry.Calories, entry.Food)
+	}
+	return fmt.Sprintf("%s-----------------------------------\n%d entries, %d calories\n", res, len(entries), sumCalories), nil
+}
+
+// Note renders a success message after setting the tags and the note of a day,
+// or of the given entry
+func (r *TerminalRenderer) Note(date string, tags []string, note string, entry *model.Entry) (string, error) {
+	return fmt.Sprintf("%s\n", noteMessage(util.DisplayDate(date, r.DateFormat), tags, note, entry)), nil
+}
+
+// noteMessage creates the message for setting the tags and the note of a day or an entry
+func noteMessage(date string, tags []string, note string, entry *model.Entry) string {
+	target := date
+	if entry != nil {
+		target = fmt.Sprintf("entry %d %s on %s", entry.Calories, entry.Food, date)
+	}
+	if len(tags) == 0 && note == "" {
+		return fmt.Sprintf("Cleared note for %s", target)
+	}
+	return fmt.Sprintf("Set note for %s:%s", target, tagsAndNote(tags, note))
+}
+
+// tagsAndNote formats the given tags and note with a leading space, if they are set
+func tagsAndNote(tags []string, note string) string {
+	var res string
+	if len(tags) > 0 {
+		res += " " + util.FormatTags(tags)
+	}
+	if note != "" {
+		res += " " + note
 	}
 	return res
 }
 
-// getAMR calculates the AMR for a whole model.Day
-func getAMR(d *model.Day) float64 {
-	if d.Entries != nil {
-		return d.Entries[0].AMR
+// Water renders the water drunk on the given date compared to the daily target
+func (r *TerminalRenderer) Water(date string, water *model.DayWater) (string, error) {
+	return fmt.Sprintf("Water on %s: %s\n", util.DisplayDate(date, r.DateFormat), waterStatus(water)), nil
+}
+
+// AddWater renders a success message after adding water and the water drunk on the given date
+func (r *TerminalRenderer) AddWater(date string, amount float64, water *model.DayWater) (string, error) {
+	return fmt.Sprintf("Added %s of water for %s (%s)\n", util.WaterUnit(water.UnitSystem, amount), util.DisplayDate(date, r.DateFormat), waterStatus(water)), nil
+}
+
+// Measurements renders all body measurements in order and their dates
+func (r *TerminalRenderer) Measurements(measurements []model.Measurement, config *model.Config) (string, error) {
+	var res string
+	for i := range measurements {
+		res += fmt.Sprintf("\t%s: %s\n", util.FormatDate(measurements[i].Created, r.DateFormat), measurementString(&measurements[i], config.UnitSystem))
 	}
-	return 0
+	return fmt.Sprintf("Measurements over time:\n%s\n", res), nil
 }
 
-// Import displays a success message after importing from a file
-func (r *TerminalRenderer) Import(fileName string, numEntries, numWeights int) (string, error) {
-	return fmt.Sprintf("Imported data from %s with %d entries and %d weights\n", fileName, numEntries, numWeights), nil
+// AddMeasurement renders a success message and the added measurements
+func (r *TerminalRenderer) AddMeasurement(measurement *model.Measurement, config *model.Config) (string, error) {
+	return fmt.Sprintf("Added measurements: %s\n", measurementString(measurement, config.UnitSystem)), nil
+}
+
+// measurementString formats all measured values of the given measurement
+func measurementString(m *model.Measurement, unitSystem string) string {
+	lengths := []struct {
+		name  string
+		value float64
+	}{
+		{"Waist", m.Waist},
+		{"Hip", m.Hip},
+		{"Chest", m.Chest},
+		{"Neck", m.Neck},
+		{"Arm", m.Arm},
+		{"Thigh", m.Thigh},
+	}
+	var values []string
+	for _, l := range lengths {
+		if l.value > 0 {
+			values = append(values, fmt.Sprintf("%s: %s", l.name, util.LengthUnit(unitSystem, l.value)))
+		}
+	}
+	if m.BodyFat > 0 {
+		values = append(values, fmt.Sprintf("Body Fat: %.1f%%", m.BodyFat))
+	}
+	return strings.Join(values, ", ")
+}
+
+// Fasting renders all completed fasts with their durations, the running fast and the streaks
+func (r *TerminalRenderer) Fasting(history *model.FastingHistory) (string, error) {
+	var res string
+	for i := range history.Fasts {
+		fast := &history.Fasts[i]
+		res += fmt.Sprintf("\t%s - %s: %s\n", r.fastTime(fast.Start), r.fastTime(fast.End), fastDuration(fast.Hours(fast.End), history.Goal))
+	}
+	if res == "" {
+		res = "\tNo fasts have been completed yet.\n"
+	}
+	if history.Running != nil {
+		res += fmt.Sprintf("\nFasting since %s: %s\n", r.fastTime(history.Running.Start), fastDuration(history.RunningHours, history.Goal))
+	}
+	return fmt.Sprintf("Fasting history (goal: %.0f hours):\n%s\nAverage: %.1f hours, current streak: %d days, longest streak: %d days\n", history.Goal, res, history.AverageHours, history.CurrentStreak, history.LongestStreak), nil
+}
+
+// StartFast renders a success message after starting a fast
+func (r *TerminalRenderer) StartFast(fast *model.Fast) (string, error) {
+	return fmt.Sprintf("Started fast at %s\n", r.fastTime(fast.Start)), nil
+}
+
+// StopFast renders a success message and the duration after stopping a fast
+func (r *TerminalRenderer) StopFast(fast *model.Fast, goal float64) (string, error) {
+	return fmt.Sprintf("Stopped fast at %s after %s\n", r.fastTime(fast.End), fastDuration(fast.Hours(fast.End), goal)), nil
+}
+
+// fastTime formats the start or end of a fast with the display date format and the time of day
+func (r *TerminalRenderer) fastTime(t time.Time) string {
+	return fmt.Sprintf("%s %s", util.FormatDate(t, r.DateFormat), t.Format("15:04"))
+}
+
+// fastDuration formats the duration of a fast in hours, green if the goal has been reached
+func fastDuration(hours, goal float64) string {
+	duration := fmt.Sprintf("%.1f hours", hours)
+	if hours >= goal {
+		return color.GreenString(duration)
+	}
+	return duration
+}
+
+// waterStatus formats the water drunk compared to the target, green if the target has been reached
+func waterStatus(water *model.DayWater) string {
+	amount := util.WaterUnit(water.UnitSystem, water.Amount)
+	if water.Amount >= water.Target {
+		amount = color.GreenString(amount)
+	}
+	return fmt.Sprintf("%s / %s", amount, util.WaterUnit(water.UnitSystem, water.Target))
+}
+
+// Profiles renders all profiles, marking the active profile
+func (r *TerminalRenderer) Profiles(profiles []model.Profile) (string, error) {
+	var res string
+	for _, profile := range profiles {
+		if profile.Active {
+			res += fmt.Sprintf("\t%s (active)\n", profile.Name)
+			continue
+		}
+		res += fmt.Sprintf("\t%s\n", profile.Name)
+	}
+	return fmt.Sprintf("Profiles:\n%s", res), nil
+}
+
+// AddProfile displays a success message after adding a profile
+func (r *TerminalRenderer) AddProfile(name string) (string, error) {
+	return fmt.Sprintf("Added profile %s\n", name), nil
+}
+
+// UseProfile displays a success message after switching the active profile
+func (r *TerminalRenderer) UseProfile(name string) (string, error) {
+	return fmt.Sprintf("Switched to profile %s\n", name), nil
+}
+
+// Encryption displays a success message after encrypting or decrypting the database
+func (r *TerminalRenderer) Encryption(path string, encrypted bool) (string, error) {
+	if encrypted {
+		return fmt.Sprintf("Encrypted database at %s\n", path), nil
+	}
+	return fmt.Sprintf("Decrypted database at %s\n", path), nil
+}
+
+// Backup displays a success message after creating a backup
+func (r *TerminalRenderer) Backup(backup *model.Backup) (string, error) {
+	return fmt.Sprintf("Created backup %s\n", backup.Path), nil
+}
+
+// Restore displays a success message after restoring a backup
+func (r *TerminalRenderer) Restore(backup *model.Backup) (string, error) {
+	return fmt.Sprintf("Restored database from backup %s\n", backup.Path), nil
+}
+
+// Backups renders all backups with their creation time and size
+func (r *TerminalRenderer) Backups(backups []model.Backup) (string, error) {
+	if len(backups) == 0 {
+		return "There are no backups yet.\n", nil
+	}
+	var res string
+	for _, backup := range backups {
+		res += fmt.Sprintf("\t%s %s: %s (%s)\n", util.FormatDate(backup.Created, r.DateFormat), backup.Created.Format("15:04:05"), backup.Path, kilobytes(backup.Size))
+	}
+	return fmt.Sprintf("Backups:\n%s", res), nil
+}
+
+// DBPath renders the database in use and the config file it is set in
+func (r *TerminalRenderer) DBPath(connection, configFile string) (string, error) {
+	if configFile == "" {
+		return fmt.Sprintf("Database: %s (set with --db or CALORIES_DB)\n", connection), nil
+	}
+	return fmt.Sprintf("Database: %s\nConfig file: %s\n", connection, configFile), nil
+}
+
+// SetDB displays a success message after setting the database in the config file
+func (r *TerminalRenderer) SetDB(connection, configFile string) (string, error) {
+	return fmt.Sprintf("Set database to %s in %s\n", connection, configFile), nil
+}
+
+// MoveDB displays a success message after moving the database
+func (r *TerminalRenderer) MoveDB(from, to string) (string, error) {
+	return fmt.Sprintf("Moved database from %s to %s\n", from, to), nil
+}
+
+// Compaction renders the size of the database before and after compacting it
+func (r *TerminalRenderer) Compaction(compaction *model.Compaction) (string, error) {
+	return fmt.Sprintf("Compacted database at %s from %s to %s\n", compaction.Path, kilobytes(compaction.Before), kilobytes(compaction.After)), nil
+}
+
+// IntegrityCheck renders the problems found while checking the database
+func (r *TerminalRenderer) IntegrityCheck(check *model.IntegrityCheck) (string, error) {
+	if len(check.Problems) == 0 {
+		return fmt.Sprintf("Database at %s is %s\n", check.Path, color.GreenString("ok")), nil
+	}
+	res := fmt.Sprintf("Database at %s has %s:\n", check.Path, color.RedString("%d problems", len(check.Problems)))
+	for _, problem := range check.Problems {
+		res += fmt.Sprintf("\t%s\n", problem)
+	}
+	return res, nil
+}
+
+// DBStats renders the size of the database and the number of records of its buckets or tables
+func (r *TerminalRenderer) DBStats(stats *model.DBStats) (string, error) {
+	res := fmt.Sprintf("Database at %s (%s):\n", stats.Path, kilobytes(stats.Size))
+	for _, bucket := range stats.Buckets {
+		if bucket.Size > 0 {
+			res += fmt.Sprintf("\t%s: %d records (%s)\n", bucket.Name, bucket.Records, kilobytes(bucket.Size))
+			continue
+		}
+		res += fmt.Sprintf("\t%s: %d records\n", bucket.Name, bucket.Records)
+	}
+	return res, nil
+}
+
+// kilobytes formats the given number of bytes as kilobytes
+func kilobytes(size int64) string {
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
+
+// Init displays a success message after setting up calories and the next steps
+func (r *TerminalRenderer) Init(connection, configFile string, configured bool) (string, error) {
+	res := fmt.Sprintf("Initialized database %s in %s\n", connection, configFile)
+	if configured {
+		return res + "Your configuration has been set, you can start adding entries with 'calories add'\n", nil
+	}
+	return res + "Please set your configuration with 'calories config'\n", nil
 }
@@ -3,6 +3,7 @@ package renderer
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,8 +58,8 @@ func TestTerminalConfig(t *testing.T) {
 		Birthday:   now,
 		Gender:     "male",
 		UnitSystem: util.Metric,
-	}, &model.Weight{Weight: 85.0}, 2000.0, 1500.0, 18)
-	expected := fmt.Sprintf("Current Config:\n\tWeight: 85.0 kg \n\tHeight: 185.0 cm \n\tActivity: 1.5 \n\tBirthday: %s (18)\n\tGender: male\n\tUnit System: metric\n\tAMR (BMR): 2000 (1500) calories per day\n", now.Format(util.DateFormat))
+	}, &model.Weight{Weight: 85.0}, &model.BodyComposition{BMI: 24.8, BMICategory: "normal", HealthyWeightMin: 63.3, HealthyWeightMax: 85.2}, 2000.0, 1500.0, 18)
+	expected := fmt.Sprintf("Current Config:\n\tWeight: 85.0 kg \n\tHeight: 185.0 cm \n\tBMI: 24.8 (normal)\n\tHealthy Weight: 63.3 kg - 85.2 kg\n\tActivity: 1.5 \n\tBirthday: %s (18)\n\tGender: male\n\tUnit System: metric\n\tDate Format: dd.mm.yyyy\n\tTimezone: Local (day starts at 0:00)\n\tBudget: daily\n\tWater Target: 2000 ml per day\n\tEating Window: none\n\tFormula: harris-benedict\n\tAMR (BMR): 2000 (1500) calories per day\n", now.Format(util.DateFormat))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
 		return
@@ -69,7 +70,7 @@ func TestTerminalDaysNoEntries(t *testing.T) {
 	r := TerminalRenderer{}
 	days := model.Days{}
 	now := time.Now()
-	res, err := r.Days(days, now, now)
+	res, err := r.Days(days, now, now, nil)
 	expected := fmt.Sprintf("Data from %s to %s:\n-----------------------------------\nNo entries have been found.\n", now.Format(util.DateFormat), now.Format(util.DateFormat))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
@@ -95,7 +96,7 @@ func TestTerminalDaysEntries(t *testing.T) {
 		Date:    now,
 		Entries: entries,
 	})
-	res, err := r.Days(days, now, now)
+	res, err := r.Days(days, now, now, nil)
 	expected := fmt.Sprintf("Data from %s to %s:\n-----------------------------------\n%s\n\t1000 Schnitzel\n\t---------------------\n\t%s / 2000 calories\n-----------------------------------\n%s / 2000 calories = %s %s\n", now.Format(util.DateFormat), now.Format(util.DateFormat), now.Format(util.DateFormat), color.GreenString("1000"), color.GreenString("1000"), color.GreenString("1000"), color.GreenString("deficit"))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
@@ -121,7 +122,7 @@ func TestTerminalDaysEntriesSurplus(t *testing.T) {
 		Date:    now,
 		Entries: entries,
 	})
-	res, err := r.Days(days, now, now)
+	res, err := r.Days(days, now, now, nil)
 	expected := fmt.Sprintf("Data from %s to %s:\n-----------------------------------\n%s\n\t3000 Schnitzel\n\t---------------------\n\t%s / 2000 calories\n-----------------------------------\n%s / 2000 calories = %s %s\n", now.Format(util.DateFormat), now.Format(util.DateFormat), now.Format(util.DateFormat), color.RedString("3000"), color.RedString("3000"), color.RedString("1000"), color.RedString("surplus"))
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
@@ -131,7 +132,7 @@ func TestTerminalDaysEntriesSurplus(t *testing.T) {
 
 func TestTerminalAddEntry(t *testing.T) {
 	r := TerminalRenderer{}
-	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel")
+	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel", "")
 	expected := "Added Entry for 01.01.2017 with 1000 calories (Schnitzel)\n"
 	if res != expected || err != nil {
 		t.Errorf("Error, actual: %v expected: %v", res, expected)
@@ -168,3 +169,408 @@ func TestTerminalImport(t *testing.T) {
 		return
 	}
 }
+
+func TestTerminalAddEntryDateFormat(t *testing.T) {
+	r := TerminalRenderer{DateFormat: "mm/dd/yyyy"}
+	res, err := r.AddEntry("13.01.2017", 1000, "Schnitzel", "")
+	expected := "Added Entry for 01/13/2017 with 1000 calories (Schnitzel)\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalProfiles(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Profiles([]model.Profile{{Name: "default", Active: true}, {Name: "anna"}})
+	expected := "Profiles:\n\tdefault (active)\n\tanna\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalAddProfile(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.AddProfile("anna")
+	expected := "Added profile anna\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalConfigHistory(t *testing.T) {
+	r := TerminalRenderer{}
+	effective, _ := time.Parse(util.DateFormat, "01.02.2017")
+	res, err := r.ConfigHistory([]model.Config{
+		{Height: 185.0, Activity: 1.5, Gender: "male", UnitSystem: util.Metric},
+		{Effective: effective, Height: 185.0, Activity: 1.2, Gender: "male", UnitSystem: util.Metric},
+	})
+	expected := "Config over time:\n\tInitial: Height: 185.0 cm, Activity: 1.5, Gender: male, Unit System: metric\n\tFrom 01.02.2017: Height: 185.0 cm, Activity: 1.2, Gender: male, Unit System: metric\n\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalRecalc(t *testing.T) {
+	r := TerminalRenderer{}
+	recalculations := []model.Recalculation{{
+		Entry:  model.Entry{EntryDate: "01.01.2017", Calories: 100, Food: "Apple"},
+		OldAMR: 2000.0,
+		OldBMR: 1500.0,
+		NewAMR: 2100.0,
+		NewBMR: 1600.0,
+	}}
+	res, err := r.Recalc(recalculations, true)
+	expected := "Dry run, 1 entries would be recalculated:\n\t01.01.2017 100 Apple: AMR 2000 -> 2100, BMR 1500 -> 1600\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalStatsNoEntries(t *testing.T) {
+	r := TerminalRenderer{}
+	from, _ := time.Parse(util.DateFormat, "01.01.2017")
+	res, err := r.Stats(&model.Stats{From: from, To: from})
+	expected := "Statistics from 01.01.2017 to 01.01.2017:\n-----------------------------------\nNo entries have been found.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalStats(t *testing.T) {
+	r := TerminalRenderer{}
+	from, _ := time.Parse(util.DateFormat, "01.01.2017")
+	stats := model.Stats{
+		From:                     from,
+		To:                       from,
+		LoggedDays:               1,
+		AverageIntake:            2500,
+		AverageDeficit:           -500,
+		DaysOverBudget:           1,
+		CurrentLoggingStreak:     1,
+		TopFoods:                 []model.FoodCount{{Food: "Pizza", Count: 1, Calories: 2500}},
+		Weekdays:                 []model.WeekdayStats{{Weekday: "Monday"}, {Weekday: "Sunday", Days: 1, AverageIntake: 2500, AverageDeficit: -500}},
+		LongestUnderBudgetStreak: 0,
+	}
+	res, err := r.Stats(&stats)
+	surplus := fmt.Sprintf("%s %s", color.RedString("500"), color.RedString("surplus"))
+	expected := fmt.Sprintf("Statistics from 01.01.2017 to 01.01.2017:\n-----------------------------------\nDays with entries: 1\nAverage intake: 2500 calories\nAverage %s\nDays under budget: %s\nDays over budget: %s\nLongest streak under budget: 0 days\nCurrent logging streak: 1 days\nMost frequent foods:\n\t1x Pizza (2500 calories)\nWeekdays:\n\tMonday: no entries\n\tSunday: 2500 calories, %s (1 days)\n", surplus, color.GreenString("0"), color.RedString("1"), surplus)
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalCalendar(t *testing.T) {
+	r := TerminalRenderer{}
+	month, _ := time.Parse(util.DateFormat, "01.02.2017")
+	days := model.Days{
+		&model.Day{Date: month.AddDate(0, 0, 1), Used: 1500, Entries: model.Entries{model.Entry{Calories: 1500, AMR: 2000}}},
+		&model.Day{Date: month.AddDate(0, 0, 6), Used: 2500, Entries: model.Entries{model.Entry{Calories: 2500, AMR: 2000}}},
+	}
+	res, err := r.Calendar(days, month)
+	expected := fmt.Sprintf("Calendar for February 2017:\n-----------------------------------\n  Mo  Tu  We  Th  Fr  Sa  Su\n           1%s   3   4   5 | %s\n   6%s   8   9  10  11  12 | %s\n  13  14  15  16  17  18  19 | -\n  20  21  22  23  24  25  26 | -\n  27  28                     | -\n-----------------------------------\nMonth: %s\n",
+		color.GreenString("%4d", 2), deficitString(500), color.RedString("%4d", 7), deficitString(-500), deficitString(0))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalSearch(t *testing.T) {
+	r := TerminalRenderer{DateFormat: "yyyy-mm-dd"}
+	entries := model.Entries{
+		model.Entry{EntryDate: "01.01.2017", Calories: 100, Food: "Apple"},
+		model.Entry{EntryDate: "03.01.2017", Calories: 400, Food: "Apple Pie"},
+	}
+	res, err := r.Search("apple", entries)
+	expected := "Entries for \"apple\":\n-----------------------------------\n2017-01-01\t100 Apple\n2017-01-03\t400 Apple Pie\n-----------------------------------\n2 entries, 500 calories\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalSearchNoEntries(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Search("apple", model.Entries{})
+	expected := "No entries have been found for \"apple\".\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalDaysTagsAndNotes(t *testing.T) {
+	r := TerminalRenderer{}
+	date, _ := time.Parse(util.DateFormat, "01.01.2017")
+	days := model.Days{&model.Day{
+		Used: 1000,
+		Date: date,
+		Note: &model.DayNote{Tags: []string{"cheatday"}, Note: "birthday party"},
+		Entries: model.Entries{model.Entry{
+			EntryDate: "01.01.2017",
+			Calories:  1000,
+			Food:      "Cake",
+			AMR:       2000.0,
+			Tags:      []string{"homemade", "sweets"},
+			Note:      "two pieces",
+		}},
+	}}
+	res, err := r.Days(days, date, date, nil)
+	expected := fmt.Sprintf("Data from 01.01.2017 to 01.01.2017:\n-----------------------------------\n01.01.2017 #cheatday\n\tNote: birthday party\n\t1000 Cake #homemade #sweets (two pieces)\n\t---------------------\n\t%s / 2000 calories\n-----------------------------------\n%s / 2000 calories = %s %s\n", color.GreenString("1000"), color.GreenString("1000"), color.GreenString("1000"), color.GreenString("deficit"))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalDaysNoteWithoutEntries(t *testing.T) {
+	r := TerminalRenderer{}
+	date, _ := time.Parse(util.DateFormat, "01.01.2017")
+	days := model.Days{&model.Day{
+		Date:    date,
+		Note:    &model.DayNote{Tags: []string{"sick"}, Note: "stayed in bed"},
+		Entries: model.Entries{},
+	}}
+	res, err := r.Days(days, date, date, nil)
+	expected := fmt.Sprintf("Data from 01.01.2017 to 01.01.2017:\n-----------------------------------\n01.01.2017 #sick\n\tNote: stayed in bed\n-----------------------------------\n%s / 0 calories = %s %s\n", color.GreenString("0"), color.GreenString("0"), color.GreenString("deficit"))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalNote(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Note("01.01.2017", []string{"restaurant"}, "too salty", &model.Entry{Calories: 500, Food: "Pizza"})
+	expected := "Set note for entry 500 Pizza on 01.01.2017: #restaurant too salty\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Note("01.01.2017", nil, "", nil)
+	expected = "Cleared note for 01.01.2017\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalDaysWeeklyBudget(t *testing.T) {
+	r := TerminalRenderer{}
+	from, _ := time.Parse(util.DateFormat, "02.01.2017")
+	budget := model.WeeklyBudget{
+		From:           from,
+		To:             from.AddDate(0, 0, 6),
+		Target:         2000,
+		Total:          14000,
+		Used:           3000,
+		UsedToday:      2500,
+		Remaining:      11000,
+		DaysLeft:       6,
+		DailyAllowance: 2250,
+	}
+	res, err := r.Days(model.Days{}, from, from, &budget)
+	expected := fmt.Sprintf("Data from 02.01.2017 to 02.01.2017:\n-----------------------------------\nNo entries have been found.\n\nWeekly budget from 02.01.2017 to 08.01.2017:\n\t%s / 14000 calories used, %s\n\tAllowance: 2250 calories per day for the remaining 6 days (%s)\n", color.GreenString("3000"), color.GreenString("11000 left"), color.RedString("250 over today"))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalWater(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Water("01.01.2017", &model.DayWater{Amount: 500, Target: 2000, UnitSystem: util.Metric})
+	expected := "Water on 01.01.2017: 500 ml / 2000 ml\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalAddWaterImperial(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.AddWater("01.01.2017", 500, &model.DayWater{Amount: 2500, Target: 2000, UnitSystem: util.Imperial})
+	expected := fmt.Sprintf("Added 16.9 fl oz of water for 01.01.2017 (%s / 67.6 fl oz)\n", color.GreenString("84.5 fl oz"))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalMeasurements(t *testing.T) {
+	r := TerminalRenderer{}
+	created := time.Date(2017, 1, 1, 8, 0, 0, 0, time.UTC)
+	measurements := []model.Measurement{
+		{Created: created, Waist: 80, Hip: 95, BodyFat: 18.5},
+		{Created: created.AddDate(0, 0, 7), Chest: 100},
+	}
+	res, err := r.Measurements(measurements, &model.Config{UnitSystem: util.Metric})
+	expected := "Measurements over time:\n\t01.01.2017: Waist: 80.0 cm, Hip: 95.0 cm, Body Fat: 18.5%\n\t08.01.2017: Chest: 100.0 cm\n\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalAddMeasurementImperial(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.AddMeasurement(&model.Measurement{Waist: 81.28}, &model.Config{UnitSystem: util.Imperial})
+	expected := "Added measurements: Waist: 32.0 inches\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalConfigLeanMassImperial(t *testing.T) {
+	r := TerminalRenderer{}
+	now := time.Now()
+	res, err := r.Config(&model.Config{
+		Height:     185.0,
+		Activity:   1.5,
+		Birthday:   now,
+		Gender:     "male",
+		UnitSystem: util.Imperial,
+	}, &model.Weight{Weight: 85.0}, &model.BodyComposition{BMI: 24.8, BMICategory: "normal", HealthyWeightMin: 63.3, HealthyWeightMax: 85.2, BodyFat: 20, LeanMass: 68, FFMI: 19.9}, 2000.0, 1500.0, 18)
+	expected := "\tBMI: 24.8 (normal)\n\tHealthy Weight: 139.6 pounds - 187.8 pounds\n\tBody Fat: 20.0%\n\tLean Mass: 149.9 pounds (FFMI: 19.9)\n"
+	if !strings.Contains(res, expected) || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalAddEntryWarning(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.AddEntry("01.01.2017", 1000, "Schnitzel", "logged outside of your eating window (12:00 - 20:00)")
+	expected := "Added Entry for 01.01.2017 with 1000 calories (Schnitzel)\n" + color.YellowString("Warning: logged outside of your eating window (12:00 - 20:00)") + "\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalFasting(t *testing.T) {
+	r := TerminalRenderer{}
+	start := time.Date(2017, 1, 1, 20, 0, 0, 0, time.UTC)
+	history := &model.FastingHistory{
+		Fasts: []model.Fast{
+			{Start: start, End: start.Add(17 * time.Hour)},
+			{Start: start.AddDate(0, 0, 1), End: start.AddDate(0, 0, 1).Add(12 * time.Hour)},
+		},
+		Running:       &model.Fast{Start: start.AddDate(0, 0, 2)},
+		RunningHours:  3,
+		Goal:          16,
+		AverageHours:  14.5,
+		CurrentStreak: 0,
+		LongestStreak: 1,
+	}
+	res, err := r.Fasting(history)
+	expected := fmt.Sprintf("Fasting history (goal: 16 hours):\n\t01.01.2017 20:00 - 02.01.2017 13:00: %s\n\t02.01.2017 20:00 - 03.01.2017 08:00: 12.0 hours\n\nFasting since 03.01.2017 20:00: 3.0 hours\n\nAverage: 14.5 hours, current streak: 0 days, longest streak: 1 days\n", color.GreenString("17.0 hours"))
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalEncryption(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Encryption("calories.db", true)
+	expected := "Encrypted database at calories.db\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Encryption("calories.db", false)
+	expected = "Decrypted database at calories.db\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalBackups(t *testing.T) {
+	r := TerminalRenderer{DateFormat: "dd.mm.yyyy"}
+	backup := model.Backup{Path: "calories.db.20170105-120000.000.bak", Created: time.Date(2017, 1, 5, 12, 0, 0, 0, time.Local), Size: 2048}
+	res, err := r.Backup(&backup)
+	expected := "Created backup calories.db.20170105-120000.000.bak\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Restore(&backup)
+	expected = "Restored database from backup calories.db.20170105-120000.000.bak\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Backups([]model.Backup{backup})
+	expected = "Backups:\n\t05.01.2017 12:00:00: calories.db.20170105-120000.000.bak (2.0 KB)\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Backups([]model.Backup{})
+	expected = "There are no backups yet.\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestTerminalDB(t *testing.T) {
+	r := TerminalRenderer{}
+	var tests = []struct {
+		description string
+		render      func() (string, error)
+		expected    string
+	}{
+		{description: "path", render: func() (string, error) { return r.DBPath("calories.db", ".caloriesconf") }, expected: "Database: calories.db\nConfig file: .caloriesconf\n"},
+		{description: "path from flag", render: func() (string, error) { return r.DBPath(":memory:", "") }, expected: "Database: :memory: (set with --db or CALORIES_DB)\n"},
+		{description: "set", render: func() (string, error) { return r.SetDB("calories.db", ".caloriesconf") }, expected: "Set database to calories.db in .caloriesconf\n"},
+		{description: "move", render: func() (string, error) { return r.MoveDB("calories.db", "moved.db") }, expected: "Moved database from calories.db to moved.db\n"},
+		{description: "compaction", render: func() (string, error) {
+			return r.Compaction(&model.Compaction{Path: "calories.db", Before: 131072, After: 65536})
+		}, expected: "Compacted database at calories.db from 128.0 KB to 64.0 KB\n"},
+		{description: "check ok", render: func() (string, error) {
+			return r.IntegrityCheck(&model.IntegrityCheck{Path: "calories.db"})
+		}, expected: fmt.Sprintf("Database at calories.db is %s\n", color.GreenString("ok"))},
+		{description: "check problems", render: func() (string, error) {
+			return r.IntegrityCheck(&model.IntegrityCheck{Path: "calories.db", Problems: []string{"page 3: unreachable unfreed"}})
+		}, expected: fmt.Sprintf("Database at calories.db has %s:\n\tpage 3: unreachable unfreed\n", color.RedString("1 problems"))},
+		{description: "stats", render: func() (string, error) {
+			return r.DBStats(&model.DBStats{Path: "calories.db", Size: 65536, Buckets: []model.BucketStats{{Name: "Entry", Records: 2, Size: 512}, {Name: "entries", Records: 3}}})
+		}, expected: "Database at calories.db (64.0 KB):\n\tEntry: 2 records (0.5 KB)\n\tentries: 3 records\n"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res, err := tc.render()
+			if res != tc.expected || err != nil {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestTerminalInit(t *testing.T) {
+	r := TerminalRenderer{}
+	res, err := r.Init("calories.db", "config.json", true)
+	expected := "Initialized database calories.db in config.json\nYour configuration has been set, you can start adding entries with 'calories add'\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res, err = r.Init("calories.db", "config.json", false)
+	expected = "Initialized database calories.db in config.json\nPlease set your configuration with 'calories config'\n"
+	if res != expected || err != nil {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
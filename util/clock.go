@@ -0,0 +1,38 @@
+package util
+
+import (
+	"time"
+)
+
+// Clock is the interface for getting the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the clock returning the current system time
+type SystemClock struct{}
+
+// Now returns the current system time
+func (c SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock is a clock, which always returns the same point in time,
+// e.g. for generating reports "as of" a past date
+type FixedClock struct {
+	Time time.Time
+}
+
+// Now returns the fixed point in time
+func (c FixedClock) Now() time.Time {
+	return c.Time
+}
+
+// Now returns the current time of the given clock, using the system clock
+// if no clock is given
+func Now(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
@@ -0,0 +1,24 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNowFixedClock(t *testing.T) {
+	expected := time.Date(2017, 1, 15, 12, 0, 0, 0, time.UTC)
+	res := Now(FixedClock{Time: expected})
+	if !res.Equal(expected) {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestNowNilClock(t *testing.T) {
+	before := time.Now()
+	res := Now(nil)
+	if res.Before(before) {
+		t.Errorf("Error, actual: %v expected: after %v", res, before)
+		return
+	}
+}
@@ -0,0 +1,151 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// ConfigFileName is the name of the config file in the home directory and next to the binary
+const ConfigFileName = ".caloriesconf"
+
+// XDGConfigFileName is the name of the config file in $XDG_CONFIG_HOME/calories
+const XDGConfigFileName = "config.json"
+
+// Environment variables, which override the config file
+const (
+	ConfigEnv     = "CALORIES_CONFIG"
+	DBEnv         = "CALORIES_DB"
+	OutputEnv     = "CALORIES_OUTPUT"
+	DateFormatEnv = "CALORIES_DATE_FORMAT"
+	ProfileEnv    = "CALORIES_PROFILE"
+	BackupsEnv    = "CALORIES_BACKUPS"
+)
+
+// ConfigFile is the configuration of calories, which holds the database to use and
+// the defaults for the output format, the display date format and the profile
+// Backups is the number of backups to keep, the default is used, if it is nil
+// Path is the file the config was read from, or should be written to
+type ConfigFile struct {
+	DB         string `json:"db"`
+	Output     string `json:"output,omitempty"`
+	DateFormat string `json:"dateFormat,omitempty"`
+	Profile    string `json:"profile,omitempty"`
+	Backups    *int   `json:"backups,omitempty"`
+	Path       string `json:"-"`
+}
+
+// ConfigFilePaths returns the paths, where the config file is searched, in order:
+// $XDG_CONFIG_HOME/calories/config.json, $HOME/.caloriesconf and .caloriesconf in the given
+// binary folder. If CALORIES_CONFIG is set, only this path is used
+func ConfigFilePaths(binaryFolder string) []string {
+	if path := os.Getenv(ConfigEnv); path != "" {
+		return []string{path}
+	}
+	paths := []string{}
+	home, _ := homedir.Dir()
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "calories", XDGConfigFileName))
+	}
+	if home != "" {
+		paths = append(paths, filepath.Join(home, ConfigFileName))
+	}
+	if binaryFolder != "" {
+		paths = append(paths, filepath.Join(binaryFolder, ConfigFileName))
+	}
+	return paths
+}
+
+// ReadConfigFile reads the first existing config file of the given paths
+// If none exists, false and an empty config with the first path is returned
+func ReadConfigFile(paths []string) (*ConfigFile, bool, error) {
+	if len(paths) == 0 {
+		return nil, false, fmt.Errorf("could not find a location for the config file")
+	}
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("error reading config file at %s, %v", path, err)
+		}
+		config, err := ParseConfigFile(content)
+		if err != nil {
+			return nil, false, fmt.Errorf("error parsing config file at %s, %v", path, err)
+		}
+		config.Path = path
+		return config, true, nil
+	}
+	return &ConfigFile{Path: paths[0]}, false, nil
+}
+
+// ParseConfigFile parses a JSON config file, or an old config file, which only holds the database
+func ParseConfigFile(content []byte) (*ConfigFile, error) {
+	content = bytes.TrimSpace(content)
+	config := &ConfigFile{}
+	if !bytes.HasPrefix(content, []byte("{")) {
+		config.DB = string(content)
+		return config, nil
+	}
+	if err := json.Unmarshal(content, config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Write writes the config as JSON to its path, creating the folder, if necessary
+func (c *ConfigFile) Write() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal config file, %v", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(c.Path), 0700); err != nil {
+		return fmt.Errorf("error creating folder for config file at %s, %v", c.Path, err)
+	}
+	if err = ioutil.WriteFile(c.Path, append(b, '\n'), 0600); err != nil {
+		return fmt.Errorf("error writing config file at %s, %v", c.Path, err)
+	}
+	return nil
+}
+
+// WithEnv returns a copy of the config, in which the values set in the environment are overridden
+func (c *ConfigFile) WithEnv() (*ConfigFile, error) {
+	config := *c
+	if db := os.Getenv(DBEnv); db != "" {
+		config.DB = db
+	}
+	if output := os.Getenv(OutputEnv); output != "" {
+		config.Output = output
+	}
+	if dateFormat := os.Getenv(DateFormatEnv); dateFormat != "" {
+		config.DateFormat = dateFormat
+	}
+	if profile := os.Getenv(ProfileEnv); profile != "" {
+		config.Profile = profile
+	}
+	if value := os.Getenv(BackupsEnv); value != "" {
+		backups, err := strconv.Atoi(value)
+		if err != nil || backups < 0 {
+			return nil, fmt.Errorf("invalid value %s for %s, please use a number of backups to keep", value, BackupsEnv)
+		}
+		config.Backups = &backups
+	}
+	if config.DateFormat != "" && !IsValidDateFormat(config.DateFormat) {
+		return nil, fmt.Errorf("wrong date format: %s, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd", config.DateFormat)
+	}
+	if config.Backups != nil && *config.Backups < 0 {
+		return nil, fmt.Errorf("invalid number of backups %d in config file at %s", *config.Backups, c.Path)
+	}
+	return &config, nil
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+func TestConfigFilePaths(t *testing.T) {
+	home, _ := homedir.Dir()
+	os.Setenv("XDG_CONFIG_HOME", "/xdg")
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	paths := ConfigFilePaths("/bin")
+	expected := []string{"/xdg/calories/config.json", filepath.Join(home, ".caloriesconf"), "/bin/.caloriesconf"}
+	if fmt.Sprint(paths) != fmt.Sprint(expected) {
+		t.Errorf("Error, actual: %v expected: %v", paths, expected)
+		return
+	}
+	os.Setenv(ConfigEnv, "/etc/calories.json")
+	defer os.Unsetenv(ConfigEnv)
+	paths = ConfigFilePaths("/bin")
+	if len(paths) != 1 || paths[0] != "/etc/calories.json" {
+		t.Errorf("Error, actual: %v expected: %v", paths, "/etc/calories.json")
+		return
+	}
+}
+
+func TestReadConfigFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer os.RemoveAll(dir)
+	xdg := filepath.Join(dir, "xdg", "calories", "config.json")
+	legacy := filepath.Join(dir, ".caloriesconf")
+	paths := []string{xdg, legacy}
+	config, found, err := ReadConfigFile(paths)
+	if err != nil || found || config.Path != xdg || config.DB != "" {
+		t.Errorf("Error, actual: %v %v expected: %v", config, found, xdg)
+		return
+	}
+	ioutil.WriteFile(legacy, []byte("/home/user/calories.db\n"), 0600)
+	config, found, err = ReadConfigFile(paths)
+	if err != nil || !found || config.Path != legacy || config.DB != "/home/user/calories.db" {
+		t.Errorf("Error, actual: %v %v expected: %v", config, found, legacy)
+		return
+	}
+	backups := 3
+	written := &ConfigFile{DB: "sqlite:///home/user/calories.sqlite", Output: "json", DateFormat: "yyyy-mm-dd", Profile: "anna", Backups: &backups, Path: xdg}
+	if err = written.Write(); err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	config, found, err = ReadConfigFile(paths)
+	if err != nil || !found || config.Path != xdg || config.DB != written.DB || config.Output != "json" || config.DateFormat != "yyyy-mm-dd" || config.Profile != "anna" || *config.Backups != 3 {
+		t.Errorf("Error, actual: %v %v expected: %v", config, found, written)
+		return
+	}
+	ioutil.WriteFile(xdg, []byte("{\"db\": 5}"), 0600)
+	expected := fmt.Sprintf("error parsing config file at %s, json: cannot unmarshal number into Go struct field ConfigFile.db of type string", xdg)
+	if _, _, err = ReadConfigFile(paths); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
+
+func TestConfigFileWithEnv(t *testing.T) {
+	var tests = []struct {
+		description string
+		env         map[string]string
+		expected    ConfigFile
+		err         string
+	}{
+		{description: "no overrides", env: map[string]string{}, expected: ConfigFile{DB: "calories.db", Output: "json"}},
+		{description: "overrides", env: map[string]string{DBEnv: ":memory:", OutputEnv: "terminal", DateFormatEnv: "mm/dd/yyyy", ProfileEnv: "anna"}, expected: ConfigFile{DB: ":memory:", Output: "terminal", DateFormat: "mm/dd/yyyy", Profile: "anna"}},
+		{description: "invalid date format", env: map[string]string{DateFormatEnv: "yyyy"}, err: "wrong date format: yyyy, please use dd.mm.yyyy, mm/dd/yyyy or yyyy-mm-dd"},
+		{description: "invalid backups", env: map[string]string{BackupsEnv: "many"}, err: "invalid value many for CALORIES_BACKUPS, please use a number of backups to keep"},
+	}
+	for _, tc := range tests {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			for key, value := range tc.env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+			config := ConfigFile{DB: "calories.db", Output: "json"}
+			res, err := config.WithEnv()
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Errorf("Error, actual: %v expected: %v", err, tc.err)
+				}
+				return
+			}
+			if err != nil || *res != tc.expected || config.DB != "calories.db" {
+				t.Errorf("Error, actual: %v %v expected: %v", res, err, tc.expected)
+				return
+			}
+		})
+	}
+	os.Setenv(BackupsEnv, "0")
+	defer os.Unsetenv(BackupsEnv)
+	config := ConfigFile{}
+	res, err := config.WithEnv()
+	if err != nil || res.Backups == nil || *res.Backups != 0 {
+		t.Errorf("Error, actual: %v %v expected: %v", res, err, 0)
+		return
+	}
+}
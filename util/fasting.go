@@ -0,0 +1,60 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultFastingGoal is the fasting goal in hours, which is used if no eating window is configured
+const DefaultFastingGoal = 16.0
+
+// ParseEatingWindow parses an eating window of the form START-END with the hours from 0 to 23
+// (e.g. 12-20). If the end is before the start, the window spans midnight
+func ParseEatingWindow(window string) (int, int, error) {
+	parts := strings.Split(window, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("eating window needs to be of the form START-END (e.g.: 12-20): %s", window)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || start < 0 || start > 23 {
+		return 0, 0, fmt.Errorf("start of the eating window needs to be an hour from 0 to 23: %s", parts[0])
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil || end < 0 || end > 23 {
+		return 0, 0, fmt.Errorf("end of the eating window needs to be an hour from 0 to 23: %s", parts[1])
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("eating window needs to be at least one hour long: %s", window)
+	}
+	return start, end, nil
+}
+
+// IsInEatingWindow checks if the given time is within the eating window from the start hour to the end hour
+func IsInEatingWindow(t time.Time, start, end int) bool {
+	hour := t.Hour()
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// FastingGoal returns the hours between the end and the start of the given eating window
+// or the default fasting goal, if the eating window is empty or invalid
+func FastingGoal(window string) float64 {
+	start, end, err := ParseEatingWindow(window)
+	if window == "" || err != nil {
+		return DefaultFastingGoal
+	}
+	return float64((start - end + 24) % 24)
+}
+
+// FormatEatingWindow formats the given eating window as START:00 - END:00, or none, if it is empty
+func FormatEatingWindow(window string) string {
+	start, end, err := ParseEatingWindow(window)
+	if window == "" || err != nil {
+		return "none"
+	}
+	return fmt.Sprintf("%d:00 - %d:00", start, end)
+}
@@ -0,0 +1,79 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseEatingWindow(t *testing.T) {
+	testCases := []struct {
+		description string
+		window      string
+		expected    string
+	}{
+		{"valid", "12-20", "12 20 <nil>"},
+		{"spans midnight", "20-4", "20 4 <nil>"},
+		{"no dash", "12", "0 0 eating window needs to be of the form START-END (e.g.: 12-20): 12"},
+		{"invalid start", "25-20", "0 0 start of the eating window needs to be an hour from 0 to 23: 25"},
+		{"invalid end", "12-x", "0 0 end of the eating window needs to be an hour from 0 to 23: x"},
+		{"empty window", "12-12", "0 0 eating window needs to be at least one hour long: 12-12"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			start, end, err := ParseEatingWindow(tc.window)
+			res := fmt.Sprintf("%d %d %v", start, end, err)
+			if res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestIsInEatingWindow(t *testing.T) {
+	testCases := []struct {
+		description string
+		hour        int
+		start       int
+		end         int
+		expected    bool
+	}{
+		{"inside", 13, 12, 20, true},
+		{"at the start", 12, 12, 20, true},
+		{"at the end", 20, 12, 20, false},
+		{"before", 8, 12, 20, false},
+		{"inside over midnight", 2, 20, 4, true},
+		{"outside over midnight", 12, 20, 4, false},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := IsInEatingWindow(time.Date(2017, 1, 1, tc.hour, 30, 0, 0, time.UTC), tc.start, tc.end)
+			if res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestFastingGoal(t *testing.T) {
+	testCases := []struct {
+		window   string
+		expected float64
+	}{
+		{"", DefaultFastingGoal},
+		{"12-20", 16},
+		{"20-4", 16},
+		{"10-12", 22},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.window), func(t *testing.T) {
+			res := FastingGoal(tc.window)
+			if res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PassphraseEnv is the environment variable, which holds the passphrase of an encrypted database
+const PassphraseEnv = "CALORIES_PASSPHRASE"
+
+// PassphraseFileEnv is the environment variable, which holds the path of a keyring file
+// containing the passphrase of an encrypted database
+const PassphraseFileEnv = "CALORIES_PASSPHRASE_FILE"
+
+// ReadPassphrase returns the passphrase from the environment or from the keyring file and
+// otherwise asks the user for it, if stdin is a terminal
+// If confirm is set, the user has to enter the passphrase twice
+func ReadPassphrase(confirm bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if file := os.Getenv(PassphraseFileEnv); file != "" {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("could not read keyring file %s, %v", file, err)
+		}
+		passphrase := strings.TrimSpace(string(content))
+		if passphrase == "" {
+			return "", fmt.Errorf("the keyring file %s is empty", file)
+		}
+		return passphrase, nil
+	}
+	fd := int(os.Stdin.Fd())
+	if !IsTerminal(os.Stdin) {
+		return "", fmt.Errorf("no passphrase given, please set %s or %s", PassphraseEnv, PassphraseFileEnv)
+	}
+	passphrase, err := promptPassphrase(fd, "Passphrase: ")
+	if err != nil || !confirm {
+		return passphrase, err
+	}
+	repeated, err := promptPassphrase(fd, "Repeat passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if repeated != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// IsTerminal checks, whether the given file is a terminal, so the user can be asked for input
+func IsTerminal(f *os.File) bool {
+	return terminal.IsTerminal(int(f.Fd()))
+}
+
+// promptPassphrase asks the user for a passphrase, without showing the input
+func promptPassphrase(fd int, prompt string) (string, error) {
+	fmt.Print(prompt)
+	passphrase, err := terminal.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("could not read passphrase, %v", err)
+	}
+	return string(passphrase), nil
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadPassphraseFromEnv(t *testing.T) {
+	os.Setenv(PassphraseEnv, "secret")
+	defer os.Unsetenv(PassphraseEnv)
+	passphrase, err := ReadPassphrase(true)
+	if err != nil || passphrase != "secret" {
+		t.Errorf("Error, actual: %v expected: %v", passphrase, "secret")
+		return
+	}
+}
+
+func TestReadPassphraseFromFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "calories")
+	if err != nil {
+		t.Errorf("Error, actual: %v expected: %v", err, nil)
+		return
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "keyring")
+	ioutil.WriteFile(file, []byte("from file\n"), 0600)
+	os.Setenv(PassphraseFileEnv, file)
+	defer os.Unsetenv(PassphraseFileEnv)
+	passphrase, err := ReadPassphrase(false)
+	if err != nil || passphrase != "from file" {
+		t.Errorf("Error, actual: %v expected: %v", passphrase, "from file")
+		return
+	}
+	ioutil.WriteFile(file, []byte(" \n"), 0600)
+	expected := "the keyring file " + file + " is empty"
+	if _, err = ReadPassphrase(false); err == nil || err.Error() != expected {
+		t.Errorf("Error, actual: %v expected: %v", err, expected)
+		return
+	}
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"strings"
+)
+
+// MatchesFood checks case-insensitively, if the given food contains the query, or if
+// the query is close to the food or to one of its words, allowing one typo
+// (a missing, additional, wrong or swapped letter) for every four letters of the query
+func MatchesFood(food, query string) bool {
+	food = strings.ToLower(strings.TrimSpace(food))
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return false
+	}
+	if strings.Contains(food, query) {
+		return true
+	}
+	maxDistance := len([]rune(query)) / 4
+	if maxDistance == 0 {
+		return false
+	}
+	if editDistance(food, query) <= maxDistance {
+		return true
+	}
+	for _, word := range strings.Fields(food) {
+		if editDistance(word, query) <= maxDistance {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance calculates the optimal string alignment distance between a and b,
+// which is the levenshtein distance with transpositions of adjacent letters
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, minInt(d[i][j-1]+1, d[i-1][j-1]+cost))
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// minInt returns the smaller of the given ints
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
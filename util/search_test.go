@@ -0,0 +1,68 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+)
+
+var testsMatchesFood = []struct {
+	description string
+	food        string
+	query       string
+	result      bool
+}{
+	{
+		"substring",
+		"Dates and Cashews",
+		"cashew",
+		true,
+	},
+	{
+		"case insensitive",
+		"apple",
+		"APPLE",
+		true,
+	},
+	{
+		"typo",
+		"Apple Pie",
+		"aple",
+		true,
+	},
+	{
+		"swapped letters",
+		"apple",
+		"appel",
+		true,
+	},
+	{
+		"short query no typos",
+		"tea",
+		"pea",
+		false,
+	},
+	{
+		"too many typos",
+		"schnitzel",
+		"snitsel",
+		false,
+	},
+	{
+		"empty query",
+		"apple",
+		"",
+		false,
+	},
+}
+
+func TestMatchesFood(t *testing.T) {
+	for _, tc := range testsMatchesFood {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := MatchesFood(tc.food, tc.query)
+			if res != tc.result {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
+				return
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"strings"
+)
+
+// ParseTags splits the given tags at commas and whitespace and returns them
+// in lowercase without a leading #, leaving out duplicates
+func ParseTags(tags string) []string {
+	var res []string
+	fields := strings.FieldsFunc(tags, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	for _, field := range fields {
+		tag := strings.ToLower(strings.TrimLeft(field, "#"))
+		if tag != "" && !HasTag(res, tag) {
+			res = append(res, tag)
+		}
+	}
+	return res
+}
+
+// HasTag checks if the given tags contain the given tag, ignoring case and a leading #
+func HasTag(tags []string, tag string) bool {
+	tag = strings.ToLower(strings.TrimLeft(tag, "#"))
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatTags formats the given tags with a leading #, separated by spaces
+func FormatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
@@ -0,0 +1,63 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var testsParseTags = []struct {
+	description string
+	in          string
+	result      []string
+}{
+	{
+		"empty",
+		"",
+		nil,
+	},
+	{
+		"comma separated",
+		"cheatday,restaurant",
+		[]string{"cheatday", "restaurant"},
+	},
+	{
+		"hashes and spaces",
+		"#CheatDay #restaurant",
+		[]string{"cheatday", "restaurant"},
+	},
+	{
+		"duplicates",
+		"#cheatday, cheatday,,#",
+		[]string{"cheatday"},
+	},
+}
+
+func TestParseTags(t *testing.T) {
+	for _, tc := range testsParseTags {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := ParseTags(tc.in)
+			if !reflect.DeepEqual(res, tc.result) {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
+				return
+			}
+		})
+	}
+}
+
+func TestHasTag(t *testing.T) {
+	tags := []string{"cheatday", "restaurant"}
+	if !HasTag(tags, "#Restaurant") || HasTag(tags, "party") {
+		t.Errorf("Error, actual: %v expected: %v", HasTag(tags, "#Restaurant"), true)
+		return
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	res := FormatTags([]string{"cheatday", "restaurant"})
+	expected := "#cheatday #restaurant"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
@@ -8,15 +8,40 @@ import (
 	"time"
 )
 
-// DateFormat is the date format we are using
+// DateFormat is the date format used for the entry dates stored in the database
 const DateFormat = "02.01.2006"
 
+// DefaultDisplayDateFormat is the display date format, which is used if none is configured
+const DefaultDisplayDateFormat = "dd.mm.yyyy"
+
+// displayDateLayouts maps the supported display date formats to their layouts
+var displayDateLayouts = map[string]string{
+	"dd.mm.yyyy": "02.01.2006",
+	"mm/dd/yyyy": "01/02/2006",
+	"yyyy-mm-dd": "2006-01-02",
+}
+
 // Imperial depicts the identifier for the imperial unit system
 const Imperial = "imperial"
 
 // Metric depicts the identifier for the metric unit system
 const Metric = "metric"
 
+// DailyBudget depicts the budget mode, in which every day is compared to its AMR
+const DailyBudget = "daily"
+
+// WeeklyBudget depicts the budget mode, in which the AMRs of the whole week are the budget
+const WeeklyBudget = "weekly"
+
+// HarrisBenedict depicts the formula, which calculates the BMR based on weight, height, age and gender
+const HarrisBenedict = "harris-benedict"
+
+// KatchMcArdle depicts the formula, which calculates the BMR based on the lean body mass
+const KatchMcArdle = "katch-mcardle"
+
+// DefaultWaterTarget is the daily water target in ml, if none is configured
+const DefaultWaterTarget = 2000.0
+
 // AskConfirmation asks the user for confirmation on a given question
 // and returns the user's answer
 func AskConfirmation(s string, r io.Reader) (bool, error) {
@@ -53,10 +78,83 @@ func CalculateHarrisBenedict(age, height, weight, activity float64, gender strin
 	return basicMetabolicRate, basicMetabolicRate * activity
 }
 
+// CalculateKatchMcArdle calculates Katch-McArdle (https://en.wikipedia.org/wiki/Basal_metabolic_rate)
+// for calculating the basic metabolic rate based on the lean body mass in kg
+func CalculateKatchMcArdle(leanMass, activity float64) (float64, float64) {
+	basicMetabolicRate := 370 + 21.6*leanMass
+	return basicMetabolicRate, basicMetabolicRate * activity
+}
+
+// CalculateMetabolicRatesForFormula calculates the metabolic rates with the given formula
+// Katch-McArdle needs a body fat percentage, if none is known, Harris-Benedict is used
+func CalculateMetabolicRatesForFormula(formula string, date, birthday time.Time, height, weight, bodyFat, activity float64, gender string) (float64, float64) {
+	if formula == KatchMcArdle && bodyFat > 0 {
+		return CalculateKatchMcArdle(LeanMass(weight, bodyFat), activity)
+	}
+	return CalculateMetabolicRates(date, birthday, height, weight, activity, gender)
+}
+
+// IsValidFormula returns true, if the given formula is empty or a known formula
+func IsValidFormula(formula string) bool {
+	return formula == "" || formula == HarrisBenedict || formula == KatchMcArdle
+}
+
+// HealthyBMIMin and HealthyBMIMax are the bounds of the normal BMI range
+const (
+	HealthyBMIMin = 18.5
+	HealthyBMIMax = 24.9
+)
+
+// CalculateBMI calculates the body mass index for the given weight in kg and height in cm
+func CalculateBMI(weight, height float64) float64 {
+	if height <= 0 {
+		return 0
+	}
+	meters := height / 100
+	return weight / (meters * meters)
+}
+
+// BMICategory returns the WHO category of the given BMI
+func BMICategory(bmi float64) string {
+	switch {
+	case bmi < HealthyBMIMin:
+		return "underweight"
+	case bmi < 25:
+		return "normal"
+	case bmi < 30:
+		return "overweight"
+	default:
+		return "obese"
+	}
+}
+
+// HealthyWeightRange calculates the weight range in kg with a normal BMI for the given height in cm
+func HealthyWeightRange(height float64) (float64, float64) {
+	meters := height / 100
+	return HealthyBMIMin * meters * meters, HealthyBMIMax * meters * meters
+}
+
+// CalculateFFMI calculates the fat-free mass index for the given lean mass in kg and height in cm
+func CalculateFFMI(leanMass, height float64) float64 {
+	return CalculateBMI(leanMass, height)
+}
+
+// LeanMass calculates the lean body mass for the given weight and body fat percentage
+func LeanMass(weight, bodyFat float64) float64 {
+	return weight * (1 - bodyFat/100)
+}
+
+// CalculateMetabolicRates calculates the basic and active metabolic rate on the given date,
+// using the age on that date
+func CalculateMetabolicRates(date, birthday time.Time, height, weight, activity float64, gender string) (float64, float64) {
+	age := float64(CalculateAgeInYears(FixedClock{Time: date}, birthday))
+	return CalculateHarrisBenedict(age, height, weight, activity, gender)
+}
+
 // CalculateAgeInYears calculates the age in years given a date by comparing the
-// year, month and day of now and the given date
-func CalculateAgeInYears(birthday time.Time) int {
-	now := time.Now()
+// year, month and day of the current time of the given clock and the given date
+func CalculateAgeInYears(clock Clock, birthday time.Time) int {
+	now := Now(clock)
 	yearsDiff := now.Year() - birthday.Year()
 	if now.Month() < birthday.Month() {
 		return yearsDiff - 1
@@ -67,6 +165,72 @@ func CalculateAgeInYears(birthday time.Time) int {
 	return yearsDiff - 1
 }
 
+// IsValidDateFormat checks if the given display date format is supported
+func IsValidDateFormat(format string) bool {
+	_, ok := displayDateLayouts[format]
+	return ok
+}
+
+// NormalizeDateFormat returns the given display date format, or the default
+// display date format, if the given one is not supported
+func NormalizeDateFormat(format string) string {
+	if IsValidDateFormat(format) {
+		return format
+	}
+	return DefaultDisplayDateFormat
+}
+
+// DateLayout returns the layout for the given display date format
+func DateLayout(format string) string {
+	return displayDateLayouts[NormalizeDateFormat(format)]
+}
+
+// ParseDate parses the given date using the given display date format
+func ParseDate(date, format string) (time.Time, error) {
+	return time.Parse(DateLayout(format), date)
+}
+
+// FormatDate formats the given date using the given display date format
+func FormatDate(date time.Time, format string) string {
+	return date.Format(DateLayout(format))
+}
+
+// DisplayDate converts a date in the storage format to the given display date format,
+// returning the date unchanged if it can't be parsed
+func DisplayDate(entryDate, format string) string {
+	date, err := time.Parse(DateFormat, entryDate)
+	if err != nil {
+		return entryDate
+	}
+	return FormatDate(date, format)
+}
+
+// LoadLocation loads the location for the given timezone name, using the local
+// timezone if no name is given
+func LoadLocation(timezone string) (*time.Location, error) {
+	if timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(timezone)
+}
+
+// CurrentDate calculates the date of the given point in time in the given location
+// The day rollover is the hour at which a new day starts, e.g. with a rollover of 4,
+// 3am still belongs to the previous day
+func CurrentDate(now time.Time, loc *time.Location, dayRollover int) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	shifted := now.In(loc).Add(-time.Duration(dayRollover) * time.Hour)
+	return time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, loc)
+}
+
+// TruncateToDate returns the date of the given point in time at midnight UTC, which
+// makes dates comparable independent of their location
+func TruncateToDate(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 // GetBeginningOfWeek calculates the first day of the week (Monday) given a date
 func GetBeginningOfWeek(date time.Time) time.Time {
 	mondayDiff := -int(date.Weekday()) + 1
@@ -88,6 +252,12 @@ func WeightUnit(unitSystem string, value float64) string {
 
 // HeightUnit returns the height unit for the given unit system
 func HeightUnit(unitSystem string, value float64) string {
+	return LengthUnit(unitSystem, value)
+}
+
+// LengthUnit returns the length unit for the given unit system, used for
+// the height and body measurements
+func LengthUnit(unitSystem string, value float64) string {
 	unit := "cm"
 	if unitSystem == Imperial {
 		value = ToInches(value)
@@ -96,6 +266,14 @@ func HeightUnit(unitSystem string, value float64) string {
 	return fmt.Sprintf("%.1f %s", value, unit)
 }
 
+// WaterUnit returns the water unit for the given unit system
+func WaterUnit(unitSystem string, value float64) string {
+	if unitSystem == Imperial {
+		return fmt.Sprintf("%.1f fl oz", ToFluidOunces(value))
+	}
+	return fmt.Sprintf("%.0f ml", value)
+}
+
 // ToPounds converts kg to pounds
 func ToPounds(weight float64) float64 {
 	return weight * 2.20462
@@ -115,3 +293,13 @@ func ToKg(weight float64) float64 {
 func ToCm(height float64) float64 {
 	return height / 0.393701
 }
+
+// ToFluidOunces converts ml to US fluid ounces
+func ToFluidOunces(water float64) float64 {
+	return water / 29.5735
+}
+
+// ToMl converts US fluid ounces to ml
+func ToMl(water float64) float64 {
+	return water * 29.5735
+}
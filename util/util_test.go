@@ -78,7 +78,7 @@ var testsSort = []struct {
 func TestCalculateAgeInYears(t *testing.T) {
 	for _, tc := range testsSort {
 		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
-			res := CalculateAgeInYears(tc.in)
+			res := CalculateAgeInYears(SystemClock{}, tc.in)
 			if res != tc.result {
 				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
 				return
@@ -188,3 +188,228 @@ func TestConversionsToMetric(t *testing.T) {
 		return
 	}
 }
+
+var testsDateFormat = []struct {
+	description string
+	format      string
+	result      string
+}{
+	{
+		"default",
+		"",
+		"15.11.2016",
+	},
+	{
+		"unknown",
+		"yyyy/dd/mm",
+		"15.11.2016",
+	},
+	{
+		"us",
+		"mm/dd/yyyy",
+		"11/15/2016",
+	},
+	{
+		"iso",
+		"yyyy-mm-dd",
+		"2016-11-15",
+	},
+}
+
+func TestFormatDate(t *testing.T) {
+	date, _ := time.Parse(DateFormat, "15.11.2016")
+	for _, tc := range testsDateFormat {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := FormatDate(date, tc.format)
+			if res != tc.result {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
+				return
+			}
+			parsed, err := ParseDate(res, tc.format)
+			if err != nil || !parsed.Equal(date) {
+				t.Errorf("Error, actual: %v expected: %v", parsed, date)
+				return
+			}
+		})
+	}
+}
+
+func TestDisplayDate(t *testing.T) {
+	res := DisplayDate("15.11.2016", "yyyy-mm-dd")
+	expected := "2016-11-15"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+	res = DisplayDate("bla", "yyyy-mm-dd")
+	expected = "bla"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+var testsCurrentDate = []struct {
+	description string
+	now         time.Time
+	timezone    string
+	rollover    int
+	result      string
+}{
+	{
+		"utc",
+		time.Date(2017, 1, 15, 23, 30, 0, 0, time.UTC),
+		"UTC",
+		0,
+		"15.01.2017",
+	},
+	{
+		"timezone",
+		time.Date(2017, 1, 15, 23, 30, 0, 0, time.UTC),
+		"Europe/Vienna",
+		0,
+		"16.01.2017",
+	},
+	{
+		"rollover",
+		time.Date(2017, 1, 16, 3, 30, 0, 0, time.UTC),
+		"UTC",
+		4,
+		"15.01.2017",
+	},
+	{
+		"after rollover",
+		time.Date(2017, 1, 16, 4, 0, 0, 0, time.UTC),
+		"UTC",
+		4,
+		"16.01.2017",
+	},
+}
+
+func TestCurrentDate(t *testing.T) {
+	for _, tc := range testsCurrentDate {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			loc, err := LoadLocation(tc.timezone)
+			if err != nil {
+				t.Errorf("Error, actual: %v expected: %v", err, nil)
+				return
+			}
+			res := CurrentDate(tc.now, loc, tc.rollover).Format(DateFormat)
+			if res != tc.result {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
+				return
+			}
+		})
+	}
+}
+
+func TestLoadLocationInvalid(t *testing.T) {
+	_, err := LoadLocation("Nowhere/Nothing")
+	if err == nil {
+		t.Errorf("Error, actual: %v expected: %v", err, "error")
+		return
+	}
+}
+
+func TestTruncateToDate(t *testing.T) {
+	loc, _ := LoadLocation("Europe/Vienna")
+	res := TruncateToDate(time.Date(2017, 1, 15, 23, 30, 0, 0, loc))
+	expected, _ := time.Parse(DateFormat, "15.01.2017")
+	if !res.Equal(expected) {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}
+
+func TestCalculateMetabolicRates(t *testing.T) {
+	birthday, _ := time.Parse(DateFormat, "01.02.1990")
+	date, _ := time.Parse(DateFormat, "31.01.2000")
+	bmr, amr := CalculateMetabolicRates(date, birthday, 0, 0, 2, "male")
+	expectedBMR := 88.362 - 5.677*9
+	if math.Abs(bmr-expectedBMR) > 0.0001 || math.Abs(amr-2*expectedBMR) > 0.0001 {
+		t.Errorf("Error, actual: %v %v expected: %v %v", bmr, amr, expectedBMR, 2*expectedBMR)
+		return
+	}
+}
+
+var testsWaterUnit = []struct {
+	description string
+	unit        string
+	value       float64
+	result      string
+}{
+	{
+		"imp",
+		Imperial,
+		500.0,
+		"16.9 fl oz",
+	},
+	{
+		"metric",
+		Metric,
+		500.0,
+		"500 ml",
+	},
+}
+
+func TestWaterUnit(t *testing.T) {
+	for _, tc := range testsWaterUnit {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			res := WaterUnit(tc.unit, tc.value)
+			if res != tc.result {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.result)
+				return
+			}
+		})
+	}
+}
+
+func TestWaterConversions(t *testing.T) {
+	ml := math.Round(ToMl(ToFluidOunces(500.0)))
+	if ml != 500.0 {
+		t.Errorf("Error, actual: %v expected: %v", ml, 500.0)
+		return
+	}
+}
+
+func TestCalculateKatchMcArdle(t *testing.T) {
+	bmr, amr := CalculateKatchMcArdle(LeanMass(100, 20), 1.5)
+	if bmr != 2098 || amr != 3147 {
+		t.Errorf("Error, actual: %v %v expected: %v %v", bmr, amr, 2098, 3147)
+		return
+	}
+}
+
+func TestCalculateBMI(t *testing.T) {
+	testCases := []struct {
+		description string
+		weight      float64
+		height      float64
+		expected    string
+	}{
+		{"underweight", 55, 180, "17.0 underweight"},
+		{"normal", 80, 180, "24.7 normal"},
+		{"overweight", 90, 180, "27.8 overweight"},
+		{"obese", 110, 180, "34.0 obese"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("Test: %s", tc.description), func(t *testing.T) {
+			bmi := CalculateBMI(tc.weight, tc.height)
+			res := fmt.Sprintf("%.1f %s", bmi, BMICategory(bmi))
+			if res != tc.expected {
+				t.Errorf("Error, actual: %v expected: %v", res, tc.expected)
+				return
+			}
+		})
+	}
+}
+
+func TestHealthyWeightRange(t *testing.T) {
+	min, max := HealthyWeightRange(180)
+	res := fmt.Sprintf("%.1f - %.1f", min, max)
+	expected := "59.9 - 80.7"
+	if res != expected {
+		t.Errorf("Error, actual: %v expected: %v", res, expected)
+		return
+	}
+}